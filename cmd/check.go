@@ -20,6 +20,7 @@ func init() {
 	checkCmd.Flags().StringP("spec", "s", "openapi.yaml", "Path to OpenAPI specification file")
 	checkCmd.Flags().BoolP("strict", "t", false, "Enable strict validation mode")
 	checkCmd.Flags().BoolP("verbose", "v", false, "Show detailed validation results")
+	addSpecHeaderFlag(checkCmd)
 }
 
 func runCheck(cmd *cobra.Command, args []string) error {
@@ -31,15 +32,12 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		specPath = args[0]
 	}
 
-	loader := openapi3.NewLoader()
-	loader.IsExternalRefsAllowed = true
-
-	doc, err := loader.LoadFromFile(specPath)
+	doc, err := loadSpec(cmd, specPath)
 	if err != nil {
 		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
 	}
 
-	if err := doc.Validate(loader.Context); err != nil {
+	if err := doc.Validate(cmd.Context()); err != nil {
 		return fmt.Errorf("OpenAPI spec validation failed: %w", err)
 	}
 
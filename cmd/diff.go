@@ -0,0 +1,445 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two OpenAPI specifications",
+	Long: `Compare two versions of an OpenAPI specification and report breaking and
+non-breaking changes. Exits with a non-zero status when breaking changes are found.`,
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().String("old", "", "Path to the previous OpenAPI specification")
+	diffCmd.Flags().String("new", "", "Path to the updated OpenAPI specification")
+	diffCmd.Flags().BoolP("verbose", "v", false, "Also list non-breaking changes")
+	addSpecHeaderFlag(diffCmd)
+}
+
+// specChange is a single difference between two specs
+type specChange struct {
+	Breaking bool
+	Location string
+	Message  string
+}
+
+func (c specChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Location, c.Message)
+}
+
+// diffMethods lists the operations compared, in report order
+var diffMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	oldPath, _ := cmd.Flags().GetString("old")
+	newPath, _ := cmd.Flags().GetString("new")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if oldPath == "" || newPath == "" {
+		return fmt.Errorf("both --old and --new must be provided")
+	}
+
+	oldSpec, err := loadSpec(cmd, oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to load old OpenAPI spec: %w", err)
+	}
+
+	newSpec, err := loadSpec(cmd, newPath)
+	if err != nil {
+		return fmt.Errorf("failed to load new OpenAPI spec: %w", err)
+	}
+
+	var breaking, nonBreaking []specChange
+	for _, change := range diffSpecs(oldSpec, newSpec) {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		} else {
+			nonBreaking = append(nonBreaking, change)
+		}
+	}
+
+	if len(breaking) > 0 {
+		fmt.Printf("Breaking changes (%d):\n", len(breaking))
+		for _, change := range breaking {
+			fmt.Printf("  - %s\n", change)
+		}
+	}
+
+	if len(nonBreaking) > 0 {
+		fmt.Printf("Non-breaking changes (%d):\n", len(nonBreaking))
+		if verbose {
+			for _, change := range nonBreaking {
+				fmt.Printf("  - %s\n", change)
+			}
+		}
+	}
+
+	if len(breaking) > 0 {
+		return fmt.Errorf("found %d breaking changes", len(breaking))
+	}
+
+	fmt.Println("✅ No breaking changes")
+	return nil
+}
+
+// diffSpecs walks the paths, operations and schemas of both specs and
+// returns the changes in path and method order
+func diffSpecs(oldSpec, newSpec *openapi3.T) []specChange {
+	d := &specDiff{}
+
+	oldPaths := oldSpec.Paths.Map()
+	newPaths := newSpec.Paths.Map()
+
+	for _, path := range sortedKeys(oldPaths, newPaths) {
+		oldItem, newItem := oldPaths[path], newPaths[path]
+		switch {
+		case newItem == nil:
+			d.add(true, path, "path removed")
+			continue
+		case oldItem == nil:
+			d.add(false, path, "path added")
+			continue
+		}
+
+		for _, method := range diffMethods {
+			oldOp, newOp := oldItem.GetOperation(method), newItem.GetOperation(method)
+			location := method + " " + path
+			switch {
+			case oldOp == nil && newOp == nil:
+			case newOp == nil:
+				d.add(true, location, "operation removed")
+			case oldOp == nil:
+				d.add(false, location, "operation added")
+			default:
+				d.operation(location, oldItem, newItem, oldOp, newOp)
+			}
+		}
+	}
+
+	return d.changes
+}
+
+type specDiff struct {
+	changes []specChange
+}
+
+func (d *specDiff) add(breaking bool, location, format string, a ...interface{}) {
+	d.changes = append(d.changes, specChange{
+		Breaking: breaking,
+		Location: location,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+func (d *specDiff) operation(location string, oldItem, newItem *openapi3.PathItem, oldOp, newOp *openapi3.Operation) {
+	oldParams := operationParameters(oldItem, oldOp)
+	newParams := operationParameters(newItem, newOp)
+
+	for _, key := range sortedKeys(oldParams, newParams) {
+		oldParam, newParam := oldParams[key], newParams[key]
+		switch {
+		case newParam == nil:
+			d.add(false, location, "%s parameter removed", key)
+		case oldParam == nil && newParam.Required:
+			d.add(true, location, "required %s parameter added", key)
+		case oldParam == nil:
+			d.add(false, location, "optional %s parameter added", key)
+		default:
+			if newParam.Required && !oldParam.Required {
+				d.add(true, location, "%s parameter is now required", key)
+			}
+			if oldParam.Schema != nil && newParam.Schema != nil {
+				d.schema(location, key+" parameter", oldParam.Schema.Value, newParam.Schema.Value, true, map[[2]*openapi3.Schema]bool{})
+			}
+		}
+	}
+
+	d.requestBody(location, oldOp.RequestBody, newOp.RequestBody)
+	d.responses(location, oldOp.Responses, newOp.Responses)
+}
+
+func (d *specDiff) requestBody(location string, oldBody, newBody *openapi3.RequestBodyRef) {
+	switch {
+	case newBody == nil || newBody.Value == nil:
+		if oldBody != nil && oldBody.Value != nil {
+			d.add(false, location, "request body removed")
+		}
+		return
+	case oldBody == nil || oldBody.Value == nil:
+		d.add(newBody.Value.Required, location, "request body added")
+		return
+	}
+
+	if newBody.Value.Required && !oldBody.Value.Required {
+		d.add(true, location, "request body is now required")
+	}
+
+	for _, mediaType := range sortedKeys(oldBody.Value.Content, newBody.Value.Content) {
+		oldMedia, newMedia := oldBody.Value.Content[mediaType], newBody.Value.Content[mediaType]
+		switch {
+		case newMedia == nil:
+			d.add(true, location, "request content type %s removed", mediaType)
+		case oldMedia == nil:
+			d.add(false, location, "request content type %s added", mediaType)
+		case oldMedia.Schema != nil && newMedia.Schema != nil:
+			d.schema(location, "request body", oldMedia.Schema.Value, newMedia.Schema.Value, true, map[[2]*openapi3.Schema]bool{})
+		}
+	}
+}
+
+func (d *specDiff) responses(location string, oldResponses, newResponses *openapi3.Responses) {
+	if oldResponses == nil || newResponses == nil {
+		return
+	}
+
+	oldMap, newMap := oldResponses.Map(), newResponses.Map()
+	for _, status := range sortedKeys(oldMap, newMap) {
+		oldResp, newResp := oldMap[status], newMap[status]
+		switch {
+		case newResp == nil:
+			d.add(true, location, "response %s removed", status)
+			continue
+		case oldResp == nil:
+			d.add(false, location, "response %s added", status)
+			continue
+		case oldResp.Value == nil || newResp.Value == nil:
+			continue
+		}
+
+		for _, mediaType := range sortedKeys(oldResp.Value.Content, newResp.Value.Content) {
+			oldMedia, newMedia := oldResp.Value.Content[mediaType], newResp.Value.Content[mediaType]
+			switch {
+			case newMedia == nil:
+				d.add(true, location, "response %s content type %s removed", status, mediaType)
+			case oldMedia == nil:
+				d.add(false, location, "response %s content type %s added", status, mediaType)
+			case oldMedia.Schema != nil && newMedia.Schema != nil:
+				d.schema(location, "response "+status, oldMedia.Schema.Value, newMedia.Schema.Value, false, map[[2]*openapi3.Schema]bool{})
+			}
+		}
+	}
+}
+
+// schema compares two schemas found at field. Request schemas break clients
+// when they accept less than before; response schemas break clients when
+// fields they rely on disappear or change type.
+func (d *specDiff) schema(location, field string, oldSchema, newSchema *openapi3.Schema, request bool, seen map[[2]*openapi3.Schema]bool) {
+	if oldSchema == nil || newSchema == nil {
+		return
+	}
+
+	// Recursive $refs resolve to the same schema pointers
+	pair := [2]*openapi3.Schema{oldSchema, newSchema}
+	if seen[pair] {
+		return
+	}
+	seen[pair] = true
+
+	if oldSchema.Type != newSchema.Type {
+		switch {
+		case oldSchema.Type == "":
+			d.add(request, location, "%s type narrowed to %s", field, newSchema.Type)
+		case newSchema.Type == "":
+			d.add(!request, location, "%s type widened from %s", field, oldSchema.Type)
+		case oldSchema.Type == "number" && newSchema.Type == "integer":
+			d.add(request, location, "%s type narrowed from number to integer", field)
+		case oldSchema.Type == "integer" && newSchema.Type == "number":
+			d.add(!request, location, "%s type widened from integer to number", field)
+		default:
+			d.add(true, location, "%s type changed from %s to %s", field, oldSchema.Type, newSchema.Type)
+		}
+	}
+
+	if request {
+		d.enum(location, field, oldSchema.Enum, newSchema.Enum)
+		d.required(location, field, oldSchema.Required, newSchema.Required)
+		d.constraints(location, field, oldSchema, newSchema)
+	}
+
+	for _, name := range sortedKeys(oldSchema.Properties, newSchema.Properties) {
+		oldProp, newProp := oldSchema.Properties[name], newSchema.Properties[name]
+		propField := field + "." + name
+		switch {
+		case newProp == nil:
+			d.add(!request, location, "%s removed", propField)
+		case oldProp == nil:
+			d.add(false, location, "%s added", propField)
+		default:
+			d.schema(location, propField, oldProp.Value, newProp.Value, request, seen)
+		}
+	}
+
+	if oldSchema.Items != nil && newSchema.Items != nil {
+		d.schema(location, field+"[]", oldSchema.Items.Value, newSchema.Items.Value, request, seen)
+	}
+}
+
+func (d *specDiff) enum(location, field string, oldEnum, newEnum []interface{}) {
+	if len(oldEnum) == 0 {
+		if len(newEnum) > 0 {
+			d.add(true, location, "%s restricted to enum values", field)
+		}
+		return
+	}
+	if len(newEnum) == 0 {
+		d.add(false, location, "%s no longer restricted to enum values", field)
+		return
+	}
+
+	for _, value := range oldEnum {
+		if !containsValue(newEnum, value) {
+			d.add(true, location, "%s enum value %v removed", field, value)
+		}
+	}
+	for _, value := range newEnum {
+		if !containsValue(oldEnum, value) {
+			d.add(false, location, "%s enum value %v added", field, value)
+		}
+	}
+}
+
+func (d *specDiff) required(location, field string, oldRequired, newRequired []string) {
+	for _, name := range newRequired {
+		if !containsString(oldRequired, name) {
+			d.add(true, location, "%s.%s is now required", field, name)
+		}
+	}
+	for _, name := range oldRequired {
+		if !containsString(newRequired, name) {
+			d.add(false, location, "%s.%s is no longer required", field, name)
+		}
+	}
+}
+
+// constraints reports bounds that were tightened (breaking) or loosened
+func (d *specDiff) constraints(location, field string, oldSchema, newSchema *openapi3.Schema) {
+	d.lowerBound(location, field, "minimum", oldSchema.Min, newSchema.Min)
+	d.upperBound(location, field, "maximum", oldSchema.Max, newSchema.Max)
+	d.lowerBound(location, field, "minLength", uintBound(oldSchema.MinLength), uintBound(newSchema.MinLength))
+	d.upperBound(location, field, "maxLength", uintPtrBound(oldSchema.MaxLength), uintPtrBound(newSchema.MaxLength))
+	d.lowerBound(location, field, "minItems", uintBound(oldSchema.MinItems), uintBound(newSchema.MinItems))
+	d.upperBound(location, field, "maxItems", uintPtrBound(oldSchema.MaxItems), uintPtrBound(newSchema.MaxItems))
+
+	if !oldSchema.ExclusiveMin && newSchema.ExclusiveMin {
+		d.add(true, location, "%s minimum is now exclusive", field)
+	}
+	if !oldSchema.ExclusiveMax && newSchema.ExclusiveMax {
+		d.add(true, location, "%s maximum is now exclusive", field)
+	}
+
+	if oldSchema.Pattern != newSchema.Pattern && newSchema.Pattern != "" {
+		d.add(true, location, "%s pattern changed to %s", field, newSchema.Pattern)
+	}
+
+	if !reflect.DeepEqual(oldSchema.MultipleOf, newSchema.MultipleOf) && newSchema.MultipleOf != nil {
+		d.add(true, location, "%s multipleOf changed to %v", field, *newSchema.MultipleOf)
+	}
+}
+
+func (d *specDiff) lowerBound(location, field, keyword string, oldBound, newBound *float64) {
+	switch {
+	case newBound == nil:
+		if oldBound != nil {
+			d.add(false, location, "%s %s removed", field, keyword)
+		}
+	case oldBound == nil || *newBound > *oldBound:
+		d.add(true, location, "%s %s tightened to %v", field, keyword, *newBound)
+	case *newBound < *oldBound:
+		d.add(false, location, "%s %s loosened to %v", field, keyword, *newBound)
+	}
+}
+
+func (d *specDiff) upperBound(location, field, keyword string, oldBound, newBound *float64) {
+	switch {
+	case newBound == nil:
+		if oldBound != nil {
+			d.add(false, location, "%s %s removed", field, keyword)
+		}
+	case oldBound == nil || *newBound < *oldBound:
+		d.add(true, location, "%s %s tightened to %v", field, keyword, *newBound)
+	case *newBound > *oldBound:
+		d.add(false, location, "%s %s loosened to %v", field, keyword, *newBound)
+	}
+}
+
+// operationParameters indexes the parameters of an operation, including the
+// ones inherited from its path item, by location and name
+func operationParameters(item *openapi3.PathItem, op *openapi3.Operation) map[string]*openapi3.Parameter {
+	params := make(map[string]*openapi3.Parameter)
+	for _, ref := range append(append(openapi3.Parameters{}, item.Parameters...), op.Parameters...) {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		params[ref.Value.In+" "+ref.Value.Name] = ref.Value
+	}
+	return params
+}
+
+// uintBound treats a zero minLength or minItems as unset
+func uintBound(v uint64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+func uintPtrBound(v *uint64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the union of the keys of both maps in sorted order
+func sortedKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
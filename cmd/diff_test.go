@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felipevolpatto/meridian/internal/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestSpec(t *testing.T, specYAML string) *openapi3.T {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(specYAML), 0644))
+
+	spec, err := openapi.ParseFile(specPath)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestDiffSpecs(t *testing.T) {
+	oldSpec := parseTestSpec(t, `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                  nickname:
+                    type: string
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  maxLength: 50
+                email:
+                  type: string
+                age:
+                  type: number
+                role:
+                  type: string
+                  enum: [admin, member, guest]
+      responses:
+        '201':
+          description: Created
+  /users/{id}:
+    delete:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '204':
+          description: Deleted
+  /legacy:
+    get:
+      responses:
+        '200':
+          description: Success
+`)
+
+	newSpec := parseTestSpec(t, `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 2.0.0
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: string
+                  createdAt:
+                    type: string
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, email]
+              properties:
+                name:
+                  type: string
+                  maxLength: 20
+                email:
+                  type: string
+                age:
+                  type: integer
+                role:
+                  type: string
+                  enum: [admin, member]
+      responses:
+        '201':
+          description: Created
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Success
+`)
+
+	var breaking, nonBreaking []string
+	for _, change := range diffSpecs(oldSpec, newSpec) {
+		if change.Breaking {
+			breaking = append(breaking, change.String())
+		} else {
+			nonBreaking = append(nonBreaking, change.String())
+		}
+	}
+
+	assert.ElementsMatch(t, []string{
+		"/legacy: path removed",
+		"GET /users: response 200.nickname removed",
+		"POST /users: request body.email is now required",
+		"POST /users: request body.age type narrowed from number to integer",
+		"POST /users: request body.name maxLength tightened to 20",
+		"POST /users: request body.role enum value guest removed",
+		"DELETE /users/{id}: operation removed",
+	}, breaking)
+
+	assert.ElementsMatch(t, []string{
+		"GET /users: optional query limit parameter added",
+		"GET /users: response 200.createdAt added",
+		"GET /users/{id}: operation added",
+	}, nonBreaking)
+}
+
+func TestDiffSpecs_Unchanged(t *testing.T) {
+	specYAML := `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  minLength: 3
+      responses:
+        '201':
+          description: Created
+`
+
+	assert.Empty(t, diffSpecs(parseTestSpec(t, specYAML), parseTestSpec(t, specYAML)))
+}
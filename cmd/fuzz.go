@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/felipevolpatto/meridian/internal/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+var fuzzCmd = &cobra.Command{
+	Use:   "fuzz",
+	Short: "Send random valid requests to a server",
+	Long: `Generate schema-valid requests for the operations in the OpenAPI specification, send
+them to a target server and check every response against the specification. Reports
+the status code distribution and exits with a non-zero status when any response is invalid.`,
+	RunE: runFuzzCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(fuzzCmd)
+	fuzzCmd.Flags().StringP("spec", "s", "openapi.yaml", "Path to OpenAPI specification file")
+	fuzzCmd.Flags().StringP("target", "t", "http://localhost:8080", "Base URL of the server under test")
+	fuzzCmd.Flags().IntP("requests", "n", 100, "Number of requests to send")
+	fuzzCmd.Flags().IntP("concurrency", "c", 4, "Number of requests in flight at once")
+	fuzzCmd.Flags().Duration("timeout", 10*time.Second, "Timeout for each request")
+	fuzzCmd.Flags().BoolP("verbose", "v", false, "Show every invalid response instead of the first few")
+	addSpecHeaderFlag(fuzzCmd)
+}
+
+// fuzzOptions configures a fuzz run
+type fuzzOptions struct {
+	Target      string
+	Requests    int
+	Concurrency int
+	Client      *http.Client
+}
+
+// fuzzFailure is a response that didn't match the specification
+type fuzzFailure struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Err        string
+}
+
+// fuzzReport summarizes a fuzz run
+type fuzzReport struct {
+	StatusCodes map[int]int
+	Failures    []fuzzFailure
+	Errors      int
+}
+
+// fuzzOperation is an operation requests can be generated for
+type fuzzOperation struct {
+	Method   string
+	Path     string
+	PathItem *openapi3.PathItem
+	Op       *openapi3.Operation
+}
+
+func runFuzzCmd(cmd *cobra.Command, args []string) error {
+	specPath, _ := cmd.Flags().GetString("spec")
+	target, _ := cmd.Flags().GetString("target")
+	requests, _ := cmd.Flags().GetInt("requests")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	if requests <= 0 {
+		return fmt.Errorf("--requests must be positive")
+	}
+
+	spec, err := loadSpec(cmd, specPath)
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	start := time.Now()
+	report, err := runFuzz(spec, fuzzOptions{
+		Target:      target,
+		Requests:    requests,
+		Concurrency: concurrency,
+		Client:      &http.Client{Timeout: timeout},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sent %d requests to %s in %s\n", requests, target, time.Since(start).Round(time.Millisecond))
+	fmt.Println("Status codes:")
+	codes := make([]int, 0, len(report.StatusCodes))
+	for code := range report.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("  %d: %d\n", code, report.StatusCodes[code])
+	}
+	if report.Errors > 0 {
+		fmt.Printf("  failed to send: %d\n", report.Errors)
+	}
+
+	if len(report.Failures) == 0 {
+		fmt.Println("✅ All responses match the specification")
+		return nil
+	}
+
+	fmt.Printf("Invalid responses (%d):\n", len(report.Failures))
+	for i, failure := range report.Failures {
+		if !verbose && i == 10 {
+			fmt.Printf("  ... and %d more (use --verbose to show all)\n", len(report.Failures)-i)
+			break
+		}
+		fmt.Printf("  - %s %s -> %d: %s\n", failure.Method, failure.Path, failure.StatusCode, failure.Err)
+	}
+
+	return fmt.Errorf("%d responses did not match the specification", len(report.Failures))
+}
+
+// runFuzz sends opts.Requests generated requests to opts.Target and validates
+// each response
+func runFuzz(spec *openapi3.T, opts fuzzOptions) (*fuzzReport, error) {
+	operations := fuzzOperations(spec)
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("no operations found in API specification")
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	target := strings.TrimSuffix(opts.Target, "/")
+	validator := validation.NewRequestValidator(spec)
+
+	report := &fuzzReport{StatusCodes: make(map[int]int)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := make(chan fuzzOperation)
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for operation := range jobs {
+				path, failure, statusCode, err := sendFuzzRequest(opts.Client, target, validator, operation)
+
+				mu.Lock()
+				if err != nil {
+					report.Errors++
+				} else {
+					report.StatusCodes[statusCode]++
+					if failure != "" {
+						report.Failures = append(report.Failures, fuzzFailure{
+							Method:     operation.Method,
+							Path:       path,
+							StatusCode: statusCode,
+							Err:        failure,
+						})
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < opts.Requests; i++ {
+		jobs <- operations[rand.Intn(len(operations))]
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report, nil
+}
+
+// sendFuzzRequest sends one generated request for the operation and returns
+// the concrete path, the validation failure if any, and the status code
+func sendFuzzRequest(client *http.Client, target string, validator *validation.RequestValidator, operation fuzzOperation) (string, string, int, error) {
+	req, path, err := buildFuzzRequest(target, operation)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return path, "", 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return path, "", 0, err
+	}
+
+	errs := validator.ValidateResponse(operation.Method, path, resp.StatusCode, resp.Header, body).Errors()
+	if len(errs) > 0 {
+		return path, errs.Error(), resp.StatusCode, nil
+	}
+	return path, "", resp.StatusCode, nil
+}
+
+// buildFuzzRequest generates parameter values and a body for the operation
+func buildFuzzRequest(target string, operation fuzzOperation) (*http.Request, string, error) {
+	path := operation.Path
+	query := url.Values{}
+	headers := http.Header{}
+
+	for _, param := range operationParameters(operation.PathItem, operation.Op) {
+		// Optional parameters are sent about half the time
+		if param.In != openapi3.ParameterInPath && !param.Required && rand.Intn(2) == 0 {
+			continue
+		}
+
+		value, err := generateParamValue(param)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to generate parameter %s: %w", param.Name, err)
+		}
+
+		switch param.In {
+		case openapi3.ParameterInPath:
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(value))
+		case openapi3.ParameterInQuery:
+			query.Set(param.Name, value)
+		case openapi3.ParameterInHeader:
+			headers.Set(param.Name, value)
+		}
+	}
+
+	var body io.Reader
+	if rb := operation.Op.RequestBody; rb != nil && rb.Value != nil {
+		if media := rb.Value.Content.Get("application/json"); media != nil && media.Schema != nil {
+			data, err := generator.GenerateAdvancedData(media.Schema, "")
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to generate request body: %w", err)
+			}
+			encoded, err := json.Marshal(data)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to marshal request body: %w", err)
+			}
+			body = bytes.NewReader(encoded)
+			headers.Set("Content-Type", "application/json")
+		}
+	}
+
+	rawURL := target + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(operation.Method, rawURL, body)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	return req, path, nil
+}
+
+// generateParamValue generates a value for a parameter and formats it as it
+// appears in a URL or header
+func generateParamValue(param *openapi3.Parameter) (string, error) {
+	if param.Schema == nil {
+		return "value", nil
+	}
+
+	value, err := generator.GenerateAdvancedData(param.Schema, param.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ","), nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// fuzzOperations lists the operations of a spec in path and method order
+func fuzzOperations(spec *openapi3.T) []fuzzOperation {
+	var operations []fuzzOperation
+	paths := spec.Paths.Map()
+	for _, path := range sortedKeys(paths, nil) {
+		item := paths[path]
+		for _, method := range diffMethods {
+			if op := item.GetOperation(method); op != nil {
+				operations = append(operations, fuzzOperation{Method: method, Path: path, PathItem: item, Op: op})
+			}
+		}
+	}
+	return operations
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFuzz(t *testing.T) {
+	spec := parseTestSpec(t, `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, age]
+              properties:
+                name:
+                  type: string
+                age:
+                  type: integer
+                  minimum: 18
+                  maximum: 99
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id:
+                    type: string
+                  name:
+                    type: string
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id:
+                    type: string
+                  name:
+                    type: string
+`)
+
+	var invalidBodies int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] == nil || body["age"] == nil {
+				atomic.AddInt32(&invalidBodies, 1)
+			}
+			// The created resource is missing its required id
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"name": "Ada"}`))
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/users/")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "name": "Ada"})
+	}))
+	defer target.Close()
+
+	report, err := runFuzz(spec, fuzzOptions{Target: target.URL, Requests: 40, Concurrency: 4})
+	require.NoError(t, err)
+
+	assert.Zero(t, report.Errors)
+	assert.Zero(t, atomic.LoadInt32(&invalidBodies), "generated request bodies should be schema-valid")
+	assert.Equal(t, 40, report.StatusCodes[http.StatusOK]+report.StatusCodes[http.StatusCreated])
+	assert.Len(t, report.Failures, report.StatusCodes[http.StatusCreated])
+	for _, failure := range report.Failures {
+		assert.Equal(t, http.MethodPost, failure.Method)
+		assert.Contains(t, failure.Err, "id")
+	}
+}
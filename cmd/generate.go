@@ -7,7 +7,6 @@ import (
 
 	"github.com/felipevolpatto/meridian/internal/config"
 	"github.com/felipevolpatto/meridian/internal/generator"
-	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +24,7 @@ var generateCmd = &cobra.Command{
 			log.Fatalf("Error loading config: %v", err)
 		}
 
-		spec, err := openapi.ParseFile(cfg.OpenAPI)
+		spec, err := loadSpec(cmd, cfg.OpenAPI)
 		if err != nil {
 			log.Fatalf("Error parsing OpenAPI spec: %v", err)
 		}
@@ -69,5 +68,6 @@ var generateCmd = &cobra.Command{
 }
 
 func init() {
+	addSpecHeaderFlag(generateCmd)
 	rootCmd.AddCommand(generateCmd)
 }
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Report common OpenAPI specification smells",
+	Long: `Check an OpenAPI specification for issues that affect the mock server, such as
+operations without responses or undeclared path parameters. Exits with a non-zero
+status when any errors are found.`,
+	RunE: runLint,
+}
+
+// lintRule is a single check run against a spec
+type lintRule struct {
+	Name        string
+	Severity    string
+	Description string
+	check       func(l *specLinter)
+}
+
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+var lintRules = []lintRule{
+	{Name: "operation-responses", Severity: lintError, Description: "operations must declare at least one response", check: (*specLinter).operationResponses},
+	{Name: "operation-id", Severity: lintWarning, Description: "operations should have an operationId", check: (*specLinter).operationIDs},
+	{Name: "path-params", Severity: lintError, Description: "path template parameters must be declared in parameters", check: (*specLinter).pathParams},
+	{Name: "empty-response-schema", Severity: lintWarning, Description: "JSON response schemas should describe their properties", check: (*specLinter).emptyResponseSchemas},
+	{Name: "additional-properties", Severity: lintWarning, Description: "object schemas without properties should set additionalProperties explicitly", check: (*specLinter).additionalProperties},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringP("spec", "s", "openapi.yaml", "Path to OpenAPI specification file")
+	lintCmd.Flags().StringSlice("disable", nil, "Comma-separated rules to skip")
+	lintCmd.Flags().Bool("list-rules", false, "List the available rules and exit")
+	addSpecHeaderFlag(lintCmd)
+}
+
+// lintFinding is a rule violation at a JSON pointer in the spec
+type lintFinding struct {
+	Rule     string
+	Severity string
+	Pointer  string
+	Message  string
+}
+
+func (f lintFinding) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", f.Severity, f.Pointer, f.Message, f.Rule)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	specPath, _ := cmd.Flags().GetString("spec")
+	disabled, _ := cmd.Flags().GetStringSlice("disable")
+	listRules, _ := cmd.Flags().GetBool("list-rules")
+
+	if listRules {
+		for _, rule := range lintRules {
+			fmt.Printf("%-22s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+		return nil
+	}
+
+	if len(args) > 0 {
+		specPath = args[0]
+	}
+
+	for _, name := range disabled {
+		if findLintRule(name) == nil {
+			return fmt.Errorf("unknown lint rule: %s", name)
+		}
+	}
+
+	spec, err := loadSpec(cmd, specPath)
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	findings := lintSpec(spec, disabled)
+
+	errorCount := 0
+	for _, finding := range findings {
+		fmt.Println(finding)
+		if finding.Severity == lintError {
+			errorCount++
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("lint found %d errors and %d warnings", errorCount, len(findings)-errorCount)
+	}
+
+	if len(findings) > 0 {
+		fmt.Printf("⚠️  %d warnings\n", len(findings))
+		return nil
+	}
+
+	fmt.Println("✅ No issues found")
+	return nil
+}
+
+func findLintRule(name string) *lintRule {
+	for i := range lintRules {
+		if lintRules[i].Name == name {
+			return &lintRules[i]
+		}
+	}
+	return nil
+}
+
+// lintSpec runs every rule that isn't disabled and returns the findings
+func lintSpec(spec *openapi3.T, disabled []string) []lintFinding {
+	l := &specLinter{spec: spec}
+	for _, rule := range lintRules {
+		if containsString(disabled, rule.Name) {
+			continue
+		}
+		l.rule = rule
+		rule.check(l)
+	}
+	return l.findings
+}
+
+type specLinter struct {
+	spec     *openapi3.T
+	rule     lintRule
+	findings []lintFinding
+}
+
+func (l *specLinter) report(pointer, format string, a ...interface{}) {
+	l.findings = append(l.findings, lintFinding{
+		Rule:     l.rule.Name,
+		Severity: l.rule.Severity,
+		Pointer:  pointer,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// eachOperation calls fn for every operation in path and method order
+func (l *specLinter) eachOperation(fn func(pointer, path string, item *openapi3.PathItem, op *openapi3.Operation)) {
+	if l.spec.Paths == nil {
+		return
+	}
+
+	paths := l.spec.Paths.Map()
+	for _, path := range sortedKeys(paths, nil) {
+		item := paths[path]
+		for _, method := range diffMethods {
+			if op := item.GetOperation(method); op != nil {
+				fn(jsonPointer("paths", path, strings.ToLower(method)), path, item, op)
+			}
+		}
+	}
+}
+
+func (l *specLinter) operationResponses() {
+	l.eachOperation(func(pointer, path string, item *openapi3.PathItem, op *openapi3.Operation) {
+		if op.Responses == nil || op.Responses.Len() == 0 {
+			l.report(pointer, "operation has no responses")
+		}
+	})
+}
+
+func (l *specLinter) operationIDs() {
+	l.eachOperation(func(pointer, path string, item *openapi3.PathItem, op *openapi3.Operation) {
+		if op.OperationID == "" {
+			l.report(pointer, "operation has no operationId")
+		}
+	})
+}
+
+func (l *specLinter) pathParams() {
+	l.eachOperation(func(pointer, path string, item *openapi3.PathItem, op *openapi3.Operation) {
+		declared := make(map[string]bool)
+		for _, params := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+			for _, ref := range params {
+				if ref != nil && ref.Value != nil && ref.Value.In == openapi3.ParameterInPath {
+					declared[ref.Value.Name] = true
+				}
+			}
+		}
+
+		for _, segment := range strings.Split(path, "/") {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				name := strings.Trim(segment, "{}")
+				if !declared[name] {
+					l.report(pointer, "path parameter %s is not declared", name)
+				}
+			}
+		}
+	})
+}
+
+func (l *specLinter) emptyResponseSchemas() {
+	l.eachOperation(func(pointer, path string, item *openapi3.PathItem, op *openapi3.Operation) {
+		if op.Responses == nil {
+			return
+		}
+
+		responses := op.Responses.Map()
+		for _, status := range sortedKeys(responses, nil) {
+			resp := responses[status]
+			if resp.Value == nil {
+				continue
+			}
+			for _, mediaType := range sortedKeys(resp.Value.Content, nil) {
+				media := resp.Value.Content[mediaType]
+				if !isJSONContentType(mediaType) || media.Schema == nil || media.Schema.Value == nil {
+					continue
+				}
+				if isEmptyObjectSchema(media.Schema.Value) {
+					l.report(jsonPointer(pointer, "responses", status, "content", mediaType, "schema"), "response schema has no properties")
+				}
+			}
+		}
+	})
+}
+
+func (l *specLinter) additionalProperties() {
+	if l.spec.Components == nil {
+		return
+	}
+
+	seen := make(map[*openapi3.Schema]bool)
+	for _, name := range sortedKeys(l.spec.Components.Schemas, nil) {
+		l.freeFormObjects(jsonPointer("components", "schemas", name), l.spec.Components.Schemas[name], seen)
+	}
+}
+
+// freeFormObjects reports object schemas that leave it unclear whether they
+// are an empty object or a map of arbitrary keys
+func (l *specLinter) freeFormObjects(pointer string, ref *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) {
+	if ref == nil || ref.Value == nil || seen[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	seen[schema] = true
+
+	if schema.Type == "object" && len(schema.Properties) == 0 &&
+		schema.AdditionalProperties.Has == nil && schema.AdditionalProperties.Schema == nil {
+		l.report(pointer, "object has no properties and no additionalProperties")
+	}
+
+	for _, name := range sortedKeys(schema.Properties, nil) {
+		l.freeFormObjects(jsonPointer(pointer, "properties", name), schema.Properties[name], seen)
+	}
+	if schema.Items != nil {
+		l.freeFormObjects(jsonPointer(pointer, "items"), schema.Items, seen)
+	}
+}
+
+// isEmptyObjectSchema reports whether a schema describes an object without
+// saying anything about its contents
+func isEmptyObjectSchema(schema *openapi3.Schema) bool {
+	if schema.Type != "" && schema.Type != "object" {
+		return false
+	}
+	freeForm := schema.AdditionalProperties.Schema != nil ||
+		(schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has)
+	return len(schema.Properties) == 0 && !freeForm &&
+		len(schema.AllOf) == 0 && len(schema.OneOf) == 0 && len(schema.AnyOf) == 0
+}
+
+func isJSONContentType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// jsonPointer joins segments into a JSON pointer, escaping each segment. A
+// first segment that is already a pointer is extended.
+func jsonPointer(segments ...string) string {
+	var b strings.Builder
+	if len(segments) > 0 && strings.HasPrefix(segments[0], "#") {
+		b.WriteString(segments[0])
+		segments = segments[1:]
+	} else {
+		b.WriteString("#")
+	}
+
+	replacer := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, segment := range segments {
+		b.WriteString("/")
+		b.WriteString(replacer.Replace(segment))
+	}
+	return b.String()
+}
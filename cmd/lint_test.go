@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const lintTestSpec = `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+    post:
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+  /users/{id}:
+    delete:
+      operationId: deleteUser
+      responses: {}
+  /tags:
+    get:
+      operationId: listTags
+      responses:
+        '200':
+          description: Success
+          content:
+            application/json:
+              schema:
+                type: object
+                additionalProperties: true
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+        metadata:
+          type: object
+`
+
+func TestLintSpec(t *testing.T) {
+	spec := parseTestSpec(t, lintTestSpec)
+
+	var findings []string
+	for _, finding := range lintSpec(spec, nil) {
+		findings = append(findings, finding.String())
+	}
+
+	assert.ElementsMatch(t, []string{
+		"error #/paths/~1users~1{id}/delete: operation has no responses (operation-responses)",
+		"warning #/paths/~1users/post: operation has no operationId (operation-id)",
+		"error #/paths/~1users~1{id}/delete: path parameter id is not declared (path-params)",
+		"warning #/paths/~1users/post/responses/201/content/application~1json/schema: response schema has no properties (empty-response-schema)",
+		"warning #/components/schemas/User/properties/metadata: object has no properties and no additionalProperties (additional-properties)",
+	}, findings)
+}
+
+func TestLintSpec_DisabledRules(t *testing.T) {
+	spec := parseTestSpec(t, lintTestSpec)
+
+	findings := lintSpec(spec, []string{"operation-responses", "path-params", "empty-response-schema", "additional-properties"})
+
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "operation-id", findings[0].Rule)
+		assert.Equal(t, lintWarning, findings[0].Severity)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/felipevolpatto/meridian/internal/state"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Generate seed data from the specification",
+	Long: `Generate seed data for every resource in the OpenAPI specification, respecting
+dependencies between resources, and write it to a file the server can load as its seed.`,
+	RunE: runSeed,
+}
+
+func init() {
+	rootCmd.AddCommand(seedCmd)
+	seedCmd.Flags().StringP("spec", "s", "openapi.yaml", "Path to OpenAPI specification file")
+	seedCmd.Flags().IntP("count", "c", 5, "Number of items to generate per resource")
+	seedCmd.Flags().StringP("out", "o", "seed.json", "Output file path")
+	seedCmd.Flags().StringSlice("include", nil, "Resources to generate (empty means all)")
+	seedCmd.Flags().StringSlice("exclude", nil, "Resources to skip")
+	seedCmd.Flags().String("fk-distribution", generator.FKDistributionEven, "How children are spread over their parents: even, random or skewed")
+	seedCmd.Flags().BoolP("force", "f", false, "Overwrite the output file if it exists")
+	addSpecHeaderFlag(seedCmd)
+}
+
+func runSeed(cmd *cobra.Command, args []string) error {
+	specPath, _ := cmd.Flags().GetString("spec")
+	count, _ := cmd.Flags().GetInt("count")
+	out, _ := cmd.Flags().GetString("out")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	force, _ := cmd.Flags().GetBool("force")
+	distribution, _ := cmd.Flags().GetString("fk-distribution")
+
+	if count <= 0 {
+		return fmt.Errorf("--count must be positive")
+	}
+	if !generator.IsFKDistribution(distribution) {
+		return fmt.Errorf("--fk-distribution must be one of: even, random, skewed")
+	}
+
+	if !force {
+		if _, err := os.Stat(out); err == nil {
+			return fmt.Errorf("file already exists: %s (use --force to overwrite)", out)
+		}
+	}
+
+	spec, err := loadSpec(cmd, specPath)
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	data, err := generateSeed(spec, generator.AutoSeedConfig{
+		ItemsPerResource: count,
+		IncludeResources: include,
+		ExcludeResources: exclude,
+		FKDistribution:   distribution,
+	})
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal seed data: %w", err)
+	}
+
+	if dir := filepath.Dir(out); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(out, content, 0644); err != nil {
+		return fmt.Errorf("failed to write seed file: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote seed data to %s\n", out)
+	for _, resource := range sortedKeys(data.Resources, nil) {
+		fmt.Printf("   %s: %d items\n", resource, len(data.Resources[resource]))
+	}
+	return nil
+}
+
+// generateSeed runs the auto seeder and wraps its output in the export format
+// accepted by the state.seed setting
+func generateSeed(spec *openapi3.T, config generator.AutoSeedConfig) (*state.ExportData, error) {
+	seeder := generator.NewAutoSeeder(spec, config)
+	resources, err := seeder.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate seed data: %w", err)
+	}
+
+	return state.NewExportData(resources, seeder.Relations()), nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSeed(t *testing.T) {
+	spec := parseTestSpec(t, `
+openapi: 3.0.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        '201':
+          description: Created
+  /posts:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Post'
+      responses:
+        '201':
+          description: Created
+  /tags:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Tag'
+      responses:
+        '201':
+          description: Created
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: string
+        name:
+          type: string
+    Post:
+      type: object
+      properties:
+        id:
+          type: string
+        user_id:
+          type: string
+        title:
+          type: string
+    Tag:
+      type: object
+      properties:
+        id:
+          type: string
+`)
+
+	data, err := generateSeed(spec, generator.AutoSeedConfig{
+		ItemsPerResource: 3,
+		ExcludeResources: []string{"tags"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0", data.Version)
+	assert.Len(t, data.Resources["users"], 3)
+	assert.Len(t, data.Resources["posts"], 3)
+	assert.NotContains(t, data.Resources, "tags")
+
+	// Posts reference users that exist in the seed
+	userIDs := make(map[interface{}]bool)
+	for _, user := range data.Resources["users"] {
+		userIDs[user.(map[string]interface{})["id"]] = true
+	}
+	for _, post := range data.Resources["posts"] {
+		assert.True(t, userIDs[post.(map[string]interface{})["user_id"]], "post references unknown user %v", post)
+	}
+}
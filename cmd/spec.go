@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/spf13/cobra"
+)
+
+// addSpecHeaderFlag registers the --spec-header flag on a command that loads
+// specs, which may be files or http(s) URLs
+func addSpecHeaderFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("spec-header", nil, `Header sent when fetching a remote spec, e.g. "Authorization: Bearer <token>" (repeatable)`)
+}
+
+// loadSpec loads a spec from a file or URL, sending the --spec-header
+// headers when fetching it
+func loadSpec(cmd *cobra.Command, location string) (*openapi3.T, error) {
+	headers, err := specHeaders(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Load(location, headers)
+}
+
+// specHeaders returns the headers given with --spec-header
+func specHeaders(cmd *cobra.Command) (http.Header, error) {
+	values, _ := cmd.Flags().GetStringArray("spec-header")
+	return parseSpecHeaders(values)
+}
+
+// parseSpecHeaders parses "Name: value" pairs
+func parseSpecHeaders(values []string) (http.Header, error) {
+	headers := make(http.Header)
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid spec header %q, expected \"Name: value\"", value)
+		}
+		headers.Add(name, strings.TrimSpace(v))
+	}
+	return headers, nil
+}
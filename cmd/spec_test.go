@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpecHeaders(t *testing.T) {
+	headers, err := parseSpecHeaders([]string{"Authorization: Bearer abc:def", "X-Tenant:acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer abc:def", headers.Get("Authorization"))
+	assert.Equal(t, "acme", headers.Get("X-Tenant"))
+
+	_, err = parseSpecHeaders([]string{"Authorization"})
+	assert.Error(t, err)
+}
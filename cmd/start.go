@@ -10,8 +10,6 @@ import (
 	"time"
 
 	"github.com/felipevolpatto/meridian/internal/config"
-	"github.com/felipevolpatto/meridian/internal/generator"
-	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/felipevolpatto/meridian/internal/server"
 	"github.com/felipevolpatto/meridian/internal/state"
 	"github.com/spf13/cobra"
@@ -27,15 +25,20 @@ var startCmd = &cobra.Command{
 		configPath := "meridian.yaml"
 
 		if watchFlag {
-			runWithHotReload(configPath)
+			headers, err := specHeaders(cmd)
+			if err != nil {
+				log.Fatalf("Error parsing spec headers: %v", err)
+			}
+			runWithHotReload(configPath, headers)
 		} else {
-			runNormal(configPath)
+			runNormal(cmd, configPath)
 		}
 	},
 }
 
-func runWithHotReload(configPath string) {
+func runWithHotReload(configPath string, headers http.Header) {
 	hrs := server.NewHotReloadServer(configPath)
+	hrs.SetSpecHeaders(headers)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -54,13 +57,13 @@ func runWithHotReload(configPath string) {
 	log.Println("Server stopped")
 }
 
-func runNormal(configPath string) {
+func runNormal(cmd *cobra.Command, configPath string) {
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
-	spec, err := openapi.ParseFile(cfg.OpenAPI)
+	spec, err := loadSpec(cmd, cfg.OpenAPI)
 	if err != nil {
 		log.Fatalf("Error parsing OpenAPI spec: %v", err)
 	}
@@ -73,15 +76,12 @@ func runNormal(configPath string) {
 		Spec:            spec,
 	}
 
+	if err := server.ApplyGeneratorConfig(cfg); err != nil {
+		log.Fatalf("Error applying generator config: %v", err)
+	}
+
 	if cfg.State.AutoSeed.Enabled {
-		initOpts.AutoSeedConfig = generator.AutoSeedConfig{
-			ItemsPerResource: cfg.State.AutoSeed.ItemsPerResource,
-			IncludeResources: cfg.State.AutoSeed.IncludeResources,
-			ExcludeResources: cfg.State.AutoSeed.ExcludeResources,
-		}
-		if initOpts.AutoSeedConfig.ItemsPerResource <= 0 {
-			initOpts.AutoSeedConfig.ItemsPerResource = 5
-		}
+		initOpts.AutoSeedConfig = server.AutoSeedConfig(cfg)
 	}
 
 	if err := state.InitializeWithOptions(initOpts); err != nil {
@@ -94,7 +94,7 @@ func runNormal(configPath string) {
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		if err := server.StartServer(spec, cfg); err != nil && err != http.ErrServerClosed {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error starting server: %v", err)
 		}
 	}()
@@ -118,5 +118,6 @@ func runNormal(configPath string) {
 
 func init() {
 	startCmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "Enable hot reload on file changes")
+	addSpecHeaderFlag(startCmd)
 	rootCmd.AddCommand(startCmd)
 }
@@ -31,6 +31,7 @@ func init() {
 	validateCmd.Flags().StringP("request", "r", "", "Path to request file (JSON)")
 	validateCmd.Flags().StringP("response", "p", "", "Path to response file (JSON)")
 	validateCmd.Flags().BoolP("verbose", "v", false, "Show detailed validation results")
+	addSpecHeaderFlag(validateCmd)
 }
 
 type RequestData struct {
@@ -53,8 +54,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	responsePath, _ := cmd.Flags().GetString("response")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
-	loader := openapi3.NewLoader()
-	spec, err := loader.LoadFromFile(specPath)
+	spec, err := loadSpec(cmd, specPath)
 	if err != nil {
 		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
 	}
@@ -3,8 +3,10 @@ module github.com/felipevolpatto/meridian
 go 1.21
 
 require (
+	github.com/andybalholm/brotli v1.1.0
 	github.com/fatih/color v1.16.0
 	github.com/getkin/kin-openapi v0.122.0
+	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.1
 	github.com/jaswdr/faker v1.19.1
 	github.com/mattn/go-sqlite3 v1.14.22
@@ -18,7 +20,6 @@ require (
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/go-openapi/jsonpointer v0.20.2 // indirect
 	github.com/go-openapi/swag v0.22.8 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/invopop/yaml v0.2.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVirtual_Freeze(t *testing.T) {
+	c := NewVirtual()
+	frozen := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.Freeze(frozen)
+
+	time.Sleep(5 * time.Millisecond)
+	if got := c.Now(); !got.Equal(frozen) {
+		t.Errorf("Now() = %v, want %v", got, frozen)
+	}
+	if !c.Frozen() {
+		t.Error("Frozen() = false, want true")
+	}
+}
+
+func TestVirtual_Shift(t *testing.T) {
+	c := NewVirtual()
+	c.Freeze(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.Shift(-24 * time.Hour)
+
+	if c.Frozen() {
+		t.Error("Frozen() = true after Shift, want false")
+	}
+	want := time.Now().Add(-24 * time.Hour)
+	if got := c.Now(); got.Sub(want).Abs() > time.Second {
+		t.Errorf("Now() = %v, want about %v", got, want)
+	}
+
+	c.Reset()
+	if got := c.Now(); time.Since(got).Abs() > time.Second {
+		t.Errorf("Now() after Reset = %v, want about the system time", got)
+	}
+}
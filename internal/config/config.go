@@ -20,6 +20,54 @@ type Config struct {
 
 	// Behavior configuration
 	Behavior BehaviorConfig `yaml:"behavior"`
+
+	// Data generation configuration
+	Generator GeneratorConfig `yaml:"generator"`
+}
+
+// GeneratorConfig represents data generation settings
+type GeneratorConfig struct {
+	// Generate reproducible v5 UUIDs instead of random v4 UUIDs
+	DeterministicUUIDs bool `yaml:"deterministic_uuids"`
+
+	// Seed from which deterministic values are derived
+	Seed string `yaml:"seed"`
+
+	// Generate and accept RFC 5322 display-name addresses and address lists for format email
+	ExtendedEmail bool `yaml:"extended_email"`
+
+	// Chance of including each optional property in generated objects
+	// (defaults to 0.8, 0 leaves them all out)
+	OptionalProbability *float64 `yaml:"optional_probability"`
+
+	// Chance of generating null for nullable values (defaults to 0.1, 0 turns
+	// nulls off)
+	NullProbability *float64 `yaml:"null_probability"`
+
+	// Custom semantic fields, consulted before the built-in ones
+	SemanticFields []SemanticFieldConfig `yaml:"semantic_fields"`
+
+	// Relative weights of enum values by field name (e.g., status: {active: 9, inactive: 1})
+	EnumWeights map[string]map[string]float64 `yaml:"enum_weights"`
+
+	// How often recursive schemas nest in themselves in generated data (defaults to 5)
+	MaxDepth int `yaml:"max_depth"`
+}
+
+// SemanticFieldConfig maps field names to generated values. Exactly one of
+// Pattern, Type and Values is set.
+type SemanticFieldConfig struct {
+	// Regular expression matched against field names (e.g., ^iban$)
+	Field string `yaml:"field"`
+
+	// Regular expression generated values match
+	Pattern string `yaml:"pattern"`
+
+	// Built-in semantic type to generate (e.g., credit_card)
+	Type string `yaml:"type"`
+
+	// Values to pick from
+	Values []string `yaml:"values"`
 }
 
 // ServerConfig represents the server configuration
@@ -29,6 +77,10 @@ type ServerConfig struct {
 
 	// Server port
 	Port int `yaml:"port"`
+
+	// Path prefix the API is served under (e.g., /api/v1). Defaults to the
+	// path of the spec's first server URL.
+	BasePath string `yaml:"base_path"`
 }
 
 // StateConfig represents the state configuration
@@ -42,14 +94,24 @@ type StateConfig struct {
 	// Maximum number of items per resource
 	MaxItems int `yaml:"max_items"`
 
-	// Time-to-live for items
+	// What happens to an item added past max_items: fifo evicts the oldest
+	// items (default), reject refuses the new one
+	EvictionPolicy string `yaml:"eviction_policy"`
+
+	// Time-to-live for items (default 24h), 0 keeps items forever
 	TTL Duration `yaml:"ttl"`
 
+	// How often items past their TTL are deleted (default 1m)
+	SweepInterval Duration `yaml:"sweep_interval"`
+
 	// Resource relationships configuration
 	Relationships map[string]ResourceRelationships `yaml:"relationships"`
 
 	// Auto seeding configuration
 	AutoSeed AutoSeedConfig `yaml:"auto_seed"`
+
+	// Fields that must be unique within a resource type, keyed by resource name
+	UniqueFields map[string][]string `yaml:"unique_fields"`
 }
 
 // AutoSeedConfig represents auto seeding settings
@@ -60,11 +122,29 @@ type AutoSeedConfig struct {
 	// Number of items to generate per resource
 	ItemsPerResource int `yaml:"items_per_resource"`
 
+	// Number of items to generate for specific resources, instead of items_per_resource
+	CountsByResource map[string]int `yaml:"counts_by_resource"`
+
+	// Bounds of a random number of items to generate for specific resources
+	CountRanges map[string]CountRangeConfig `yaml:"count_ranges"`
+
 	// Resources to include (empty means all)
 	IncludeResources []string `yaml:"include_resources"`
 
 	// Resources to exclude
 	ExcludeResources []string `yaml:"exclude_resources"`
+
+	// How generated children are spread over their parents: even (default), random or skewed
+	FKDistribution string `yaml:"fk_distribution"`
+}
+
+// CountRangeConfig bounds the number of items auto seeding generates for a resource
+type CountRangeConfig struct {
+	// Fewest items to generate
+	Min int `yaml:"min"`
+
+	// Most items to generate
+	Max int `yaml:"max"`
 }
 
 // ResourceRelationships defines relationships for a resource
@@ -73,6 +153,16 @@ type ResourceRelationships struct {
 	Relations map[string]string `yaml:"relations"`
 }
 
+// RelationshipTypes returns the configured relationship types keyed by
+// resource and related resource
+func (s StateConfig) RelationshipTypes() map[string]map[string]string {
+	types := make(map[string]map[string]string, len(s.Relationships))
+	for resource, relationships := range s.Relationships {
+		types[resource] = relationships.Relations
+	}
+	return types
+}
+
 // BehaviorConfig represents the server behavior configuration
 type BehaviorConfig struct {
 	// Error simulation configuration
@@ -81,17 +171,168 @@ type BehaviorConfig struct {
 	// Latency simulation configuration
 	Latency LatencyConfig `yaml:"latency"`
 
+	// Slow response simulation, writing bodies a few bytes at a time
+	Drip DripConfig `yaml:"drip"`
+
 	// CORS configuration
 	CORS CORSConfig `yaml:"cors"`
 
 	// Rate limiting configuration
 	RateLimit RateLimitConfig `yaml:"rate_limit"`
 
-	// Compression configuration
-	Compression bool `yaml:"compression"`
+	// Compression configuration, either a boolean or a CompressionConfig mapping
+	Compression CompressionConfig `yaml:"compression"`
+
+	// Minimum response size in bytes before compression is applied.
+	//
+	// Deprecated: use compression.min_bytes, which this is read into when
+	// it isn't set.
+	CompressionMinLength int `yaml:"compression_min_length"`
 
 	// Caching configuration
 	Caching CachingConfig `yaml:"caching"`
+
+	// Response body templates (Go text/template) keyed by operation ID
+	Templates map[string]string `yaml:"templates"`
+
+	// Files served by binary operations, keyed by operation ID
+	Files map[string]string `yaml:"files"`
+
+	// Action endpoints that update their target resource instead of creating one, keyed by operation ID
+	Actions map[string]ActionConfig `yaml:"actions"`
+
+	// Generate a response from the schema when a single resource isn't in state
+	GenerateOnMiss bool `yaml:"generate_on_miss"`
+
+	// Persist resources generated on a miss so repeated requests return the same data
+	PersistGenerated bool `yaml:"persist_generated"`
+
+	// Response envelope configuration
+	Envelope EnvelopeConfig `yaml:"envelope"`
+
+	// Shape CRUD requests and responses as JSON:API documents
+	JSONAPI bool `yaml:"jsonapi"`
+
+	// Check outgoing responses against the media types the spec declares
+	ValidateResponses bool `yaml:"validate_responses"`
+
+	// Resources the server serves; when set, every other resource is forbidden
+	AllowedResources []string `yaml:"allowed_resources"`
+
+	// Resources the server refuses to serve, even if the spec declares them
+	DeniedResources []string `yaml:"denied_resources"`
+
+	// Invalid data injection for negative testing of clients
+	InjectInvalid InjectInvalidConfig `yaml:"inject_invalid"`
+
+	// URLs notified when resources are created, updated or deleted
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+
+	// Send the callbacks an operation declares in the spec after a successful POST
+	Callbacks bool `yaml:"callbacks"`
+
+	// Convert stored string values to the scalar types the response schema declares on read
+	CoerceTypes bool `yaml:"coerce_types"`
+
+	// Masking strategy (full or last4) for sensitive fields in read responses, keyed by
+	// field name or semantic type such as credit_card
+	RedactFields map[string]string `yaml:"redact_fields"`
+
+	// Bounds on the queues that hold pending webhook deliveries and event stream messages
+	DeliveryQueue DeliveryQueueConfig `yaml:"delivery_queue"`
+
+	// Status returned when a path parameter fails its schema: 400 (default) or 404 to
+	// treat the request as addressing a resource that doesn't exist
+	InvalidPathParamStatus int `yaml:"invalid_path_param_status"`
+
+	// Case-insensitive substring search over list responses
+	Search SearchConfig `yaml:"search"`
+
+	// How deeply objects and arrays may nest in validated bodies (defaults to 64)
+	MaxValidationDepth int `yaml:"max_validation_depth"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. The deprecated
+// compression_min_length is read into compression.min_bytes unless that is
+// set too.
+func (b *BehaviorConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain BehaviorConfig
+	if err := unmarshal((*plain)(b)); err != nil {
+		return err
+	}
+
+	if b.Compression.MinBytes == 0 {
+		b.Compression.MinBytes = b.CompressionMinLength
+	}
+	return nil
+}
+
+// SearchConfig represents settings for searching lists with a query parameter
+type SearchConfig struct {
+	// Query parameter carrying the search text (default q)
+	Param string `yaml:"param"`
+
+	// Fields searched per resource, with dots for nested fields such as address.city.
+	// Resources that aren't listed are searched across all top-level string fields.
+	Fields map[string][]string `yaml:"fields"`
+
+	// Also search strings in nested objects and arrays when no fields are listed
+	Nested bool `yaml:"nested"`
+}
+
+// DeliveryQueueConfig represents the limits of a delivery queue
+type DeliveryQueueConfig struct {
+	// Maximum number of pending events per queue (default 64)
+	Capacity int `yaml:"capacity"`
+
+	// What happens when a queue is full: drop_oldest (default) or block
+	Policy string `yaml:"policy"`
+}
+
+// WebhookConfig represents a webhook subscription
+type WebhookConfig struct {
+	// Event that triggers the webhook: created, updated, deleted or * for all
+	Event string `yaml:"event"`
+
+	// URL the event is posted to
+	URL string `yaml:"url"`
+}
+
+// InjectInvalidConfig represents settings for responses that deliberately
+// violate the spec
+type InjectInvalidConfig struct {
+	// Whether invalid data injection is enabled
+	Enabled bool `yaml:"enabled"`
+
+	// Fraction (0.0 to 1.0) of successful responses that are made invalid
+	Rate float64 `yaml:"rate"`
+
+	// Path patterns (as in path.Match, e.g. /users/*) to inject into; empty means all paths
+	Paths []string `yaml:"paths"`
+
+	// Violations to choose from: wrong_type, missing_required, out_of_range; empty means all
+	Kinds []string `yaml:"kinds"`
+}
+
+// ActionConfig represents an action endpoint such as POST /users/{id}/activate
+type ActionConfig struct {
+	// Fields set on the target resource when the action runs
+	Set map[string]interface{} `yaml:"set"`
+}
+
+// EnvelopeConfig represents response envelope settings
+type EnvelopeConfig struct {
+	// Whether CRUD responses are wrapped in an envelope
+	Enabled bool `yaml:"enabled"`
+
+	// Key holding the response data (defaults to "data")
+	DataKey string `yaml:"data_key"`
+
+	// Whether to include a meta object with counts for list responses
+	Meta bool `yaml:"meta"`
+
+	// Key holding the meta object (defaults to "meta")
+	MetaKey string `yaml:"meta_key"`
 }
 
 // ErrorConfig represents error simulation settings
@@ -107,6 +348,28 @@ type ErrorConfig struct {
 
 	// HTTP status codes to return for simulated errors
 	StatusCodes []int `yaml:"status_codes"`
+
+	// Error response format: legacy (default) or problem (RFC 7807)
+	Format string `yaml:"format"`
+
+	// Transport failures, simulated independently of error responses
+	Network NetworkErrorConfig `yaml:"network"`
+}
+
+// NetworkErrorConfig represents settings for simulated transport failures
+type NetworkErrorConfig struct {
+	// Whether network failure simulation is enabled
+	Enabled bool `yaml:"enabled"`
+
+	// Failure rate (0.0 to 1.0)
+	Rate float64 `yaml:"rate"`
+
+	// Failure modes: reset (close the connection abruptly) and timeout (hold the
+	// request without responding). Empty means both.
+	Modes []string `yaml:"modes"`
+
+	// How long a timeout holds the request before closing the connection (default 30s)
+	Timeout Duration `yaml:"timeout"`
 }
 
 // LatencyConfig represents latency simulation settings
@@ -121,6 +384,18 @@ type LatencyConfig struct {
 	Max int `yaml:"max"`
 }
 
+// DripConfig represents settings for responses whose body is sent slowly
+type DripConfig struct {
+	// Whether drip simulation is enabled
+	Enabled bool `yaml:"enabled"`
+
+	// Bytes written per interval (default 64)
+	Bytes int `yaml:"bytes"`
+
+	// Pause between writes (default 100ms)
+	Interval Duration `yaml:"interval"`
+}
+
 // CORSConfig represents CORS settings
 type CORSConfig struct {
 	// Whether CORS is enabled
@@ -152,6 +427,15 @@ type RateLimitConfig struct {
 
 	// Per-client rate limiting based on IP
 	PerClient bool `yaml:"per_client"`
+
+	// Rate limiting algorithm: fixed, sliding or token_bucket (default)
+	Algorithm string `yaml:"algorithm"`
+
+	// Maximum number of requests allowed in a burst with token_bucket (defaults to the rate)
+	Burst int `yaml:"burst"`
+
+	// Retry-After header format: seconds (default) or http-date
+	RetryAfterFormat string `yaml:"retry_after_format"`
 }
 
 // CachingConfig represents caching settings
@@ -169,6 +453,31 @@ type CachingConfig struct {
 	Resources []string `yaml:"resources"`
 }
 
+// CompressionConfig represents response compression settings
+type CompressionConfig struct {
+	// Whether compression is enabled
+	Enabled bool `yaml:"enabled"`
+
+	// Minimum response size in bytes before compression is applied (0 compresses everything)
+	MinBytes int `yaml:"min_bytes"`
+
+	// Content types that are compressed, such as application/json or text/* (empty means all)
+	Types []string `yaml:"types"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. A plain boolean,
+// as in compression: true, only toggles compression.
+func (c *CompressionConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var enabled bool
+	if err := unmarshal(&enabled); err == nil {
+		*c = CompressionConfig{Enabled: enabled}
+		return nil
+	}
+
+	type plain CompressionConfig
+	return unmarshal((*plain)(c))
+}
+
 // Duration is a wrapper around time.Duration for YAML unmarshaling
 type Duration struct {
 	time.Duration
@@ -230,7 +539,7 @@ func New() *Config {
 				Rate:     "100/minute",
 				PerClient: true,
 			},
-			Compression: true,
+			Compression: CompressionConfig{Enabled: true},
 			Caching: CachingConfig{
 				Enabled:   true,
 				TTL:      Duration{5 * time.Minute},
@@ -248,7 +557,8 @@ func Load(filename string) (*Config, error) {
 		return nil, err
 	}
 
-	var cfg Config
+	// The TTL defaults before decoding, so an explicit ttl: 0 disables expiry
+	cfg := Config{State: StateConfig{TTL: Duration{24 * time.Hour}}}
 	if err = yaml.Unmarshal(bytes, &cfg); err != nil {
 		return nil, err
 	}
@@ -263,9 +573,6 @@ func Load(filename string) (*Config, error) {
 	if cfg.State.MaxItems == 0 {
 		cfg.State.MaxItems = 1000
 	}
-	if cfg.State.TTL.Duration == 0 {
-		cfg.State.TTL = Duration{24 * time.Hour}
-	}
 	if cfg.Behavior.CORS.Enabled && len(cfg.Behavior.CORS.AllowedMethods) == 0 {
 		cfg.Behavior.CORS.AllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func createTestFiles(t *testing.T) (string, string) {
@@ -40,7 +41,7 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, []string{"*"}, cfg.Behavior.CORS.AllowedOrigins)
 	assert.Equal(t, []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}, cfg.Behavior.CORS.AllowedMethods)
 	assert.Equal(t, "100/minute", cfg.Behavior.RateLimit.Rate)
-	assert.True(t, cfg.Behavior.Compression)
+	assert.True(t, cfg.Behavior.Compression.Enabled)
 	assert.Equal(t, 5*time.Minute, cfg.Behavior.Caching.TTL.Duration)
 }
 
@@ -103,12 +104,35 @@ behavior:
 	assert.Equal(t, time.Hour, cfg.Behavior.CORS.MaxAge.Duration)
 	assert.Equal(t, "50/minute", cfg.Behavior.RateLimit.Rate)
 	assert.True(t, cfg.Behavior.RateLimit.PerClient)
-	assert.True(t, cfg.Behavior.Compression)
+	assert.True(t, cfg.Behavior.Compression.Enabled)
 	assert.Equal(t, 10*time.Minute, cfg.Behavior.Caching.TTL.Duration)
 	assert.True(t, cfg.Behavior.Caching.UseETag)
 	assert.Equal(t, []string{"users"}, cfg.Behavior.Caching.Resources)
 }
 
+func TestLoad_StateTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    string
+		expected time.Duration
+	}{
+		{name: "default", state: "max_items: 10", expected: 24 * time.Hour},
+		{name: "configured", state: "ttl: 1h", expected: time.Hour},
+		{name: "zero disables expiry", state: "ttl: 0", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte("state:\n  "+tt.state+"\n"), 0644))
+
+			cfg, err := Load(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.State.TTL.Duration)
+		})
+	}
+}
+
 func TestDuration_UnmarshalYAML(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -162,6 +186,70 @@ func TestDuration_UnmarshalYAML(t *testing.T) {
 	}
 }
 
+func TestCompressionConfig_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected CompressionConfig
+	}{
+		{
+			name:     "boolean",
+			input:    "compression: true",
+			expected: CompressionConfig{Enabled: true},
+		},
+		{
+			name:     "disabled",
+			input:    "compression: false",
+			expected: CompressionConfig{},
+		},
+		{
+			name: "mapping",
+			input: `compression:
+  enabled: true
+  min_bytes: 1024
+  types: ["application/json", "text/*"]`,
+			expected: CompressionConfig{Enabled: true, MinBytes: 1024, Types: []string{"application/json", "text/*"}},
+		},
+		{
+			name: "deprecated min length",
+			input: `compression: true
+compression_min_length: 512`,
+			expected: CompressionConfig{Enabled: true, MinBytes: 512},
+		},
+		{
+			name: "min_bytes wins over the deprecated min length",
+			input: `compression:
+  enabled: true
+  min_bytes: 1024
+compression_min_length: 512`,
+			expected: CompressionConfig{Enabled: true, MinBytes: 1024},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var behavior BehaviorConfig
+			require.NoError(t, yaml.Unmarshal([]byte(tt.input), &behavior))
+			assert.Equal(t, tt.expected, behavior.Compression)
+		})
+	}
+}
+
+func TestGeneratorConfig_Probabilities(t *testing.T) {
+	// An explicit 0 is kept apart from an unset probability
+	var generator GeneratorConfig
+	require.NoError(t, yaml.Unmarshal([]byte("null_probability: 0\noptional_probability: 0"), &generator))
+	require.NotNil(t, generator.NullProbability)
+	assert.Equal(t, 0.0, *generator.NullProbability)
+	require.NotNil(t, generator.OptionalProbability)
+	assert.Equal(t, 0.0, *generator.OptionalProbability)
+
+	generator = GeneratorConfig{}
+	require.NoError(t, yaml.Unmarshal([]byte("seed: abc"), &generator))
+	assert.Nil(t, generator.NullProbability)
+	assert.Nil(t, generator.OptionalProbability)
+}
+
 func TestConfig_Validate(t *testing.T) {
 	openAPIPath, statePath := createTestFiles(t)
 
@@ -197,7 +285,7 @@ func TestConfig_Validate(t *testing.T) {
 				c.State.TTL = Duration{-1 * time.Hour}
 			},
 			wantError: true,
-			errorMsg:  "ttl must be greater than 0",
+			errorMsg:  "ttl must not be negative",
 		},
 		{
 			name: "invalid relationship type",
@@ -226,6 +314,284 @@ func TestConfig_Validate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "invalid rate limit format",
 		},
+		{
+			name: "invalid rate limit algorithm",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.RateLimit.Enabled = true
+				c.Behavior.RateLimit.Algorithm = "leaky"
+			},
+			wantError: true,
+			errorMsg:  "invalid rate limit algorithm",
+		},
+		{
+			name: "invalid retry after format",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.RateLimit.Enabled = true
+				c.Behavior.RateLimit.RetryAfterFormat = "minutes"
+			},
+			wantError: true,
+			errorMsg:  "invalid retry_after_format",
+		},
+		{
+			name: "invalid inject_invalid kind",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.InjectInvalid.Enabled = true
+				c.Behavior.InjectInvalid.Rate = 0.5
+				c.Behavior.InjectInvalid.Kinds = []string{"wrong_format"}
+			},
+			wantError: true,
+			errorMsg:  "invalid inject_invalid kind",
+		},
+		{
+			name: "invalid webhook url",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Webhooks = []WebhookConfig{{Event: "created", URL: "localhost:9000/hooks"}}
+			},
+			wantError: true,
+			errorMsg:  "invalid webhook url",
+		},
+		{
+			name: "invalid redaction strategy",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.RedactFields = map[string]string{"ssn": "hash"}
+			},
+			wantError: true,
+			errorMsg:  "invalid redaction strategy for ssn",
+		},
+		{
+			name: "invalid delivery queue policy",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.DeliveryQueue.Policy = "drop_newest"
+			},
+			wantError: true,
+			errorMsg:  "invalid delivery queue policy",
+		},
+		{
+			name: "invalid path parameter status",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.InvalidPathParamStatus = 422
+			},
+			wantError: true,
+			errorMsg:  "invalid path parameter status",
+		},
+		{
+			name: "negative compression threshold",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Compression.MinBytes = -1
+			},
+			wantError: true,
+			errorMsg:  "compression min_bytes must be non-negative",
+		},
+		{
+			name: "negative deprecated compression threshold",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.CompressionMinLength = -1
+			},
+			wantError: true,
+			errorMsg:  "compression_min_length must be non-negative",
+		},
+		{
+			name: "empty search field",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Search.Fields = map[string][]string{"articles": {"title", ""}}
+			},
+			wantError: true,
+			errorMsg:  "search fields for articles must not be empty",
+		},
+		{
+			name: "invalid network error mode",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Errors.Network = NetworkErrorConfig{Enabled: true, Rate: 0.5, Modes: []string{"drop"}}
+			},
+			wantError: true,
+			errorMsg:  "invalid network error mode: drop",
+		},
+		{
+			name: "invalid drip bytes",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Drip = DripConfig{Enabled: true, Bytes: -1, Interval: Duration{Duration: time.Second}}
+			},
+			wantError: true,
+			errorMsg:  "drip bytes must not be negative",
+		},
+		{
+			name: "relative base path",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Server.BasePath = "api/v1"
+			},
+			wantError: true,
+			errorMsg:  "invalid server base path: api/v1",
+		},
+		{
+			name: "invalid optional probability",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				probability := 1.5
+				c.Generator.OptionalProbability = &probability
+			},
+			wantError: true,
+			errorMsg:  "optional probability must be between 0 and 1",
+		},
+		{
+			name: "invalid null probability",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				probability := -0.5
+				c.Generator.NullProbability = &probability
+			},
+			wantError: true,
+			errorMsg:  "null probability must be between 0 and 1",
+		},
+		{
+			name: "invalid semantic field pattern",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Generator.SemanticFields = []SemanticFieldConfig{{Field: "^(iban", Pattern: "[A-Z]{2}[0-9]{20}"}}
+			},
+			wantError: true,
+			errorMsg:  "invalid semantic field pattern ^(iban",
+		},
+		{
+			name: "semantic field with several generators",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Generator.SemanticFields = []SemanticFieldConfig{{Field: "^vin$", Type: "code", Values: []string{"1HGCM82633A004352"}}}
+			},
+			wantError: true,
+			errorMsg:  "semantic field ^vin$ needs exactly one of pattern, type or values",
+		},
+		{
+			name: "negative max depth",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Generator.MaxDepth = -1
+			},
+			wantError: true,
+			errorMsg:  "max depth must not be negative",
+		},
+		{
+			name: "negative enum weight",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Generator.EnumWeights = map[string]map[string]float64{"status": {"active": 9, "inactive": -1}}
+			},
+			wantError: true,
+			errorMsg:  "enum weight of inactive for field status must not be negative",
+		},
+		{
+			name: "negative max validation depth",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.MaxValidationDepth = -1
+			},
+			wantError: true,
+			errorMsg:  "max validation depth must not be negative",
+		},
+		{
+			name: "invalid auto seed count range",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.State.AutoSeed.CountRanges = map[string]CountRangeConfig{"orders": {Min: 50, Max: 20}}
+			},
+			wantError: true,
+			errorMsg:  "invalid auto seed count range for orders: min 50, max 20",
+		},
+		{
+			name: "auto seed count and range for a resource",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.State.AutoSeed.CountsByResource = map[string]int{"orders": 50}
+				c.State.AutoSeed.CountRanges = map[string]CountRangeConfig{"orders": {Min: 20, Max: 50}}
+			},
+			wantError: true,
+			errorMsg:  "auto seed count for orders is set both as a count and as a range",
+		},
+		{
+			name: "invalid foreign key distribution",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.State.AutoSeed.FKDistribution = "zipf"
+			},
+			wantError: true,
+			errorMsg:  "invalid foreign key distribution: zipf",
+		},
+		{
+			name: "invalid eviction policy",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.State.EvictionPolicy = "lru"
+			},
+			wantError: true,
+			errorMsg:  "invalid eviction policy: lru",
+		},
+		{
+			name: "negative sweep interval",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.State.SweepInterval = Duration{-1 * time.Minute}
+			},
+			wantError: true,
+			errorMsg:  "sweep_interval must not be negative",
+		},
+		{
+			name: "jsonapi with envelope",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.JSONAPI = true
+				c.Behavior.Envelope.Enabled = true
+			},
+			wantError: true,
+			errorMsg:  "jsonapi and envelope can't both be enabled",
+		},
+		{
+			name: "invalid error format",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Errors.Format = "xml"
+			},
+			wantError: true,
+			errorMsg:  "invalid error format",
+		},
 		{
 			name: "invalid cache TTL",
 			modifyFn: func(c *Config) {
@@ -248,6 +614,27 @@ func TestConfig_Validate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "invalid HTTP method",
 		},
+		{
+			name: "action without fields",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.Actions = map[string]ActionConfig{"activateUser": {}}
+			},
+			wantError: true,
+			errorMsg:  "action activateUser must set at least one field",
+		},
+		{
+			name: "resource both allowed and denied",
+			modifyFn: func(c *Config) {
+				c.OpenAPI = openAPIPath
+				c.State.Persistence = statePath
+				c.Behavior.AllowedResources = []string{"users", "posts"}
+				c.Behavior.DeniedResources = []string{"posts"}
+			},
+			wantError: true,
+			errorMsg:  "resource posts cannot be both allowed and denied",
+		},
 	}
 
 	for _, tt := range tests {
@@ -200,7 +200,7 @@ func cachingMiddleware(next http.Handler, config CachingConfig) http.Handler {
 
 func applyMiddleware(handler http.Handler, cfg *Config) http.Handler {
 	// Apply middleware in the correct order
-	if cfg.Behavior.Compression {
+	if cfg.Behavior.Compression.Enabled {
 		handler = compressionMiddleware(handler)
 	}
 
@@ -2,11 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/openapi"
 )
 
 // Validate validates the configuration
@@ -27,16 +31,20 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid behavior configuration: %w", err)
 	}
 
+	if err := c.validateGenerator(); err != nil {
+		return fmt.Errorf("invalid generator configuration: %w", err)
+	}
+
 	return nil
 }
 
 // validatePaths validates all file paths in the configuration
 func (c *Config) validatePaths() error {
-	// Validate OpenAPI spec file
+	// Validate OpenAPI spec file, which may also be an http(s) URL
 	if c.OpenAPI == "" {
 		return fmt.Errorf("openapi spec file path is required")
 	}
-	if _, err := os.Stat(c.OpenAPI); err != nil {
+	if _, err := os.Stat(c.OpenAPI); err != nil && !openapi.IsURL(c.OpenAPI) {
 		return fmt.Errorf("openapi spec file not found: %s", c.OpenAPI)
 	}
 
@@ -70,9 +78,43 @@ func (c *Config) validateState() error {
 		return fmt.Errorf("max_items must be greater than 0, got %d", c.State.MaxItems)
 	}
 
+	// Validate eviction policy
+	switch c.State.EvictionPolicy {
+	case "", "fifo", "reject":
+	default:
+		return fmt.Errorf("invalid eviction policy: %s, valid policies are: fifo, reject", c.State.EvictionPolicy)
+	}
+
 	// Validate TTL
-	if c.State.TTL.Duration <= 0 {
-		return fmt.Errorf("ttl must be greater than 0, got %s", c.State.TTL.String())
+	if c.State.TTL.Duration < 0 {
+		return fmt.Errorf("ttl must not be negative, got %s", c.State.TTL.String())
+	}
+
+	// Validate sweep interval
+	if c.State.SweepInterval.Duration < 0 {
+		return fmt.Errorf("sweep_interval must not be negative, got %s", c.State.SweepInterval.String())
+	}
+
+	// Validate auto seed item counts
+	for resource, count := range c.State.AutoSeed.CountsByResource {
+		if count < 0 {
+			return fmt.Errorf("auto seed count for %s must not be negative, got %d", resource, count)
+		}
+	}
+	for resource, r := range c.State.AutoSeed.CountRanges {
+		if r.Min < 0 || r.Max < r.Min {
+			return fmt.Errorf("invalid auto seed count range for %s: min %d, max %d", resource, r.Min, r.Max)
+		}
+		if _, exists := c.State.AutoSeed.CountsByResource[resource]; exists {
+			return fmt.Errorf("auto seed count for %s is set both as a count and as a range", resource)
+		}
+	}
+
+	// Validate auto seed foreign key distribution
+	switch c.State.AutoSeed.FKDistribution {
+	case "", "even", "random", "skewed":
+	default:
+		return fmt.Errorf("invalid foreign key distribution: %s, valid distributions are: even, random, skewed", c.State.AutoSeed.FKDistribution)
 	}
 
 	// Validate relationships
@@ -117,6 +159,65 @@ func (c *Config) validateServer() error {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	// Validate base path
+	if c.Server.BasePath != "" && !strings.HasPrefix(c.Server.BasePath, "/") {
+		return fmt.Errorf("invalid server base path: %s, must start with /", c.Server.BasePath)
+	}
+
+	return nil
+}
+
+// validateGenerator validates the data generation configuration
+func (c *Config) validateGenerator() error {
+	// Validate optional property probability
+	if p := c.Generator.OptionalProbability; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("optional probability must be between 0 and 1")
+	}
+
+	// Validate null probability
+	if p := c.Generator.NullProbability; p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("null probability must be between 0 and 1")
+	}
+
+	// Validate nesting depth
+	if c.Generator.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative, got %d", c.Generator.MaxDepth)
+	}
+
+	// Validate semantic fields
+	for _, field := range c.Generator.SemanticFields {
+		if field.Field == "" {
+			return fmt.Errorf("semantic field pattern is required")
+		}
+		if _, err := regexp.Compile(field.Field); err != nil {
+			return fmt.Errorf("invalid semantic field pattern %s: %v", field.Field, err)
+		}
+
+		set := 0
+		for _, given := range []bool{field.Pattern != "", field.Type != "", len(field.Values) > 0} {
+			if given {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("semantic field %s needs exactly one of pattern, type or values", field.Field)
+		}
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return fmt.Errorf("invalid semantic field value pattern %s: %v", field.Pattern, err)
+			}
+		}
+	}
+
+	// Validate enum weights
+	for field, weights := range c.Generator.EnumWeights {
+		for value, weight := range weights {
+			if weight < 0 {
+				return fmt.Errorf("enum weight of %s for field %s must not be negative", value, field)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -132,6 +233,11 @@ func (c *Config) validateBehavior() error {
 		return err
 	}
 
+	// Validate drip simulation settings
+	if err := c.validateDrip(); err != nil {
+		return err
+	}
+
 	// Validate CORS settings
 	if err := c.validateCORS(); err != nil {
 		return err
@@ -147,10 +253,167 @@ func (c *Config) validateBehavior() error {
 		return err
 	}
 
+	// Validate action endpoints
+	if err := c.validateActions(); err != nil {
+		return err
+	}
+
+	// Validate resource access lists
+	if err := c.validateResourceAccess(); err != nil {
+		return err
+	}
+
+	// Validate invalid data injection settings
+	if err := c.validateInjectInvalid(); err != nil {
+		return err
+	}
+
+	// Validate webhook subscriptions
+	if err := c.validateWebhooks(); err != nil {
+		return err
+	}
+
+	// Validate redacted fields
+	if err := c.validateRedactFields(); err != nil {
+		return err
+	}
+
+	// Validate delivery queue settings
+	if err := c.validateDeliveryQueue(); err != nil {
+		return err
+	}
+
+	// Validate compression settings
+	if c.Behavior.Compression.MinBytes < 0 {
+		return fmt.Errorf("compression min_bytes must be non-negative")
+	}
+	if c.Behavior.CompressionMinLength < 0 {
+		return fmt.Errorf("compression_min_length must be non-negative")
+	}
+
+	// Validate JSON:API mode, which replaces the envelope
+	if c.Behavior.JSONAPI && c.Behavior.Envelope.Enabled {
+		return fmt.Errorf("jsonapi and envelope can't both be enabled")
+	}
+
+	// Validate search fields
+	for resource, fields := range c.Behavior.Search.Fields {
+		for _, field := range fields {
+			if field == "" {
+				return fmt.Errorf("search fields for %s must not be empty", resource)
+			}
+		}
+	}
+
+	// Validate invalid path parameter status
+	switch c.Behavior.InvalidPathParamStatus {
+	case 0, 400, 404:
+	default:
+		return fmt.Errorf("invalid path parameter status: %d, valid statuses are: 400, 404", c.Behavior.InvalidPathParamStatus)
+	}
+
+	// Validate validation depth
+	if c.Behavior.MaxValidationDepth < 0 {
+		return fmt.Errorf("max validation depth must not be negative, got %d", c.Behavior.MaxValidationDepth)
+	}
+
+	return nil
+}
+
+func (c *Config) validateActions() error {
+	for operationID, action := range c.Behavior.Actions {
+		if len(action.Set) == 0 {
+			return fmt.Errorf("action %s must set at least one field", operationID)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateResourceAccess() error {
+	for _, denied := range c.Behavior.DeniedResources {
+		for _, allowed := range c.Behavior.AllowedResources {
+			if denied == allowed {
+				return fmt.Errorf("resource %s cannot be both allowed and denied", denied)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateDeliveryQueue() error {
+	queue := c.Behavior.DeliveryQueue
+	if queue.Capacity < 0 {
+		return fmt.Errorf("delivery queue capacity must be non-negative")
+	}
+
+	switch queue.Policy {
+	case "", "drop_oldest", "block":
+	default:
+		return fmt.Errorf("invalid delivery queue policy: %s, valid policies are: drop_oldest, block", queue.Policy)
+	}
+	return nil
+}
+
+func (c *Config) validateRedactFields() error {
+	for field, strategy := range c.Behavior.RedactFields {
+		switch strategy {
+		case "full", "last4":
+		default:
+			return fmt.Errorf("invalid redaction strategy for %s: %s, valid strategies are: full, last4", field, strategy)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateWebhooks() error {
+	for _, webhook := range c.Behavior.Webhooks {
+		switch webhook.Event {
+		case "created", "updated", "deleted", "*":
+		default:
+			return fmt.Errorf("invalid webhook event: %s, valid events are: created, updated, deleted, *", webhook.Event)
+		}
+
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url: %s", webhook.URL)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateInjectInvalid() error {
+	inject := c.Behavior.InjectInvalid
+	if !inject.Enabled {
+		return nil
+	}
+
+	if inject.Rate < 0 || inject.Rate > 1 {
+		return fmt.Errorf("inject_invalid rate must be between 0 and 1, got %f", inject.Rate)
+	}
+
+	for _, pattern := range inject.Paths {
+		if _, err := path.Match(pattern, "/"); err != nil {
+			return fmt.Errorf("invalid inject_invalid path pattern: %s", pattern)
+		}
+	}
+
+	for _, kind := range inject.Kinds {
+		switch kind {
+		case "wrong_type", "missing_required", "out_of_range":
+		default:
+			return fmt.Errorf("invalid inject_invalid kind: %s, valid kinds are: wrong_type, missing_required, out_of_range", kind)
+		}
+	}
 	return nil
 }
 
 func (c *Config) validateErrors() error {
+	switch c.Behavior.Errors.Format {
+	case "", "legacy", "problem":
+	default:
+		return fmt.Errorf("invalid error format: %s, valid formats are: legacy, problem", c.Behavior.Errors.Format)
+	}
+
 	if c.Behavior.Errors.Enabled {
 		if c.Behavior.Errors.Rate < 0 || c.Behavior.Errors.Rate > 1 {
 			return fmt.Errorf("error rate must be between 0 and 1, got %f", c.Behavior.Errors.Rate)
@@ -183,6 +446,21 @@ func (c *Config) validateErrors() error {
 			}
 		}
 	}
+
+	network := c.Behavior.Errors.Network
+	if network.Enabled {
+		if network.Rate < 0 || network.Rate > 1 {
+			return fmt.Errorf("network error rate must be between 0 and 1, got %f", network.Rate)
+		}
+
+		for _, mode := range network.Modes {
+			switch mode {
+			case "reset", "timeout":
+			default:
+				return fmt.Errorf("invalid network error mode: %s, valid modes are: reset, timeout", mode)
+			}
+		}
+	}
 	return nil
 }
 
@@ -200,6 +478,19 @@ func (c *Config) validateLatency() error {
 	return nil
 }
 
+func (c *Config) validateDrip() error {
+	if c.Behavior.Drip.Enabled {
+		if c.Behavior.Drip.Bytes < 0 {
+			return fmt.Errorf("drip bytes must not be negative, got %d", c.Behavior.Drip.Bytes)
+		}
+
+		if c.Behavior.Drip.Interval.Duration < 0 {
+			return fmt.Errorf("drip interval must not be negative")
+		}
+	}
+	return nil
+}
+
 func (c *Config) validateCORS() error {
 	if c.Behavior.CORS.Enabled {
 		if len(c.Behavior.CORS.AllowedOrigins) == 0 {
@@ -261,6 +552,22 @@ func (c *Config) validateRateLimit() error {
 		if !validUnits[unit] {
 			return fmt.Errorf("invalid rate limit unit: %s, valid units are: second, minute, hour, day", unit)
 		}
+
+		switch c.Behavior.RateLimit.Algorithm {
+		case "", "fixed", "sliding", "token_bucket":
+		default:
+			return fmt.Errorf("invalid rate limit algorithm: %s, valid algorithms are: fixed, sliding, token_bucket", c.Behavior.RateLimit.Algorithm)
+		}
+
+		if c.Behavior.RateLimit.Burst < 0 {
+			return fmt.Errorf("rate limit burst must be non-negative, got %d", c.Behavior.RateLimit.Burst)
+		}
+
+		switch c.Behavior.RateLimit.RetryAfterFormat {
+		case "", "seconds", "http-date":
+		default:
+			return fmt.Errorf("invalid retry_after_format: %s, valid formats are: seconds, http-date", c.Behavior.RateLimit.RetryAfterFormat)
+		}
 	}
 	return nil
 }
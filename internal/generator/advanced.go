@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -9,6 +10,8 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
+	"github.com/felipevolpatto/meridian/internal/validation"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jaswdr/faker"
 )
@@ -74,66 +77,139 @@ const (
 	SemanticCreditCard
 )
 
-var semanticPatterns = map[*regexp.Regexp]SemanticFieldType{
-	regexp.MustCompile(`(?i)^id$|_id$|Id$`):                             SemanticID,
-	regexp.MustCompile(`(?i)^first_?name$|^given_?name$`):               SemanticFirstName,
-	regexp.MustCompile(`(?i)^last_?name$|^family_?name$|^surname$`):     SemanticLastName,
-	regexp.MustCompile(`(?i)^full_?name$|^display_?name$`):              SemanticFullName,
-	regexp.MustCompile(`(?i)^name$`):                                    SemanticName,
-	regexp.MustCompile(`(?i)^e?mail$|^email_?address$`):                 SemanticEmail,
-	regexp.MustCompile(`(?i)^phone$|^phone_?number$|^mobile$|^tel$`):    SemanticPhone,
-	regexp.MustCompile(`(?i)^address$|^full_?address$`):                 SemanticAddress,
-	regexp.MustCompile(`(?i)^street$|^street_?address$|^line1$`):        SemanticStreet,
-	regexp.MustCompile(`(?i)^city$|^town$`):                             SemanticCity,
-	regexp.MustCompile(`(?i)^state$|^province$|^region$`):               SemanticState,
-	regexp.MustCompile(`(?i)^country$|^nation$`):                        SemanticCountry,
-	regexp.MustCompile(`(?i)^zip$|^zip_?code$`):                         SemanticZipCode,
-	regexp.MustCompile(`(?i)^postal_?code$|^postcode$`):                 SemanticPostalCode,
-	regexp.MustCompile(`(?i)^url$|^link$|^href$`):                       SemanticURL,
-	regexp.MustCompile(`(?i)^website$|^homepage$|^site$`):               SemanticWebsite,
-	regexp.MustCompile(`(?i)^user_?name$|^login$|^handle$`):             SemanticUsername,
-	regexp.MustCompile(`(?i)^password$|^pass$|^pwd$|^secret$`):          SemanticPassword,
-	regexp.MustCompile(`(?i)^title$|^headline$|^subject$`):              SemanticTitle,
-	regexp.MustCompile(`(?i)^description$|^desc$|^summary$|^bio$`):      SemanticDescription,
-	regexp.MustCompile(`(?i)^content$|^text$|^body$`):                   SemanticContent,
-	regexp.MustCompile(`(?i)^message$|^comment$|^note$`):                SemanticMessage,
-	regexp.MustCompile(`(?i)^company$|^business$|^employer$`):           SemanticCompany,
-	regexp.MustCompile(`(?i)^organization$|^org$|^institution$`):        SemanticOrganization,
-	regexp.MustCompile(`(?i)^price$|^cost$|^fee$`):                      SemanticPrice,
-	regexp.MustCompile(`(?i)^amount$|^total$|^sum$|^balance$`):          SemanticAmount,
-	regexp.MustCompile(`(?i)^quantity$|^qty$`):                          SemanticQuantity,
-	regexp.MustCompile(`(?i)^count$|^num$|^number$`):                    SemanticCount,
-	regexp.MustCompile(`(?i)^age$`):                                     SemanticAge,
-	regexp.MustCompile(`(?i)^date$`):                                    SemanticDate,
-	regexp.MustCompile(`(?i)^created_?at$|^creation_?date$`):            SemanticCreatedAt,
-	regexp.MustCompile(`(?i)^updated_?at$|^modified_?at$|^edit_?date$`): SemanticUpdatedAt,
-	regexp.MustCompile(`(?i)^birthday$|^birth_?date$|^dob$`):            SemanticBirthday,
-	regexp.MustCompile(`(?i)^image$|^img$|^picture$`):                   SemanticImage,
-	regexp.MustCompile(`(?i)^avatar$|^profile_?image$|^photo$`):         SemanticAvatar,
-	regexp.MustCompile(`(?i)^color$|^colour$`):                          SemanticColor,
-	regexp.MustCompile(`(?i)^status$`):                                  SemanticStatus,
-	regexp.MustCompile(`(?i)^type$|^kind$`):                             SemanticType,
-	regexp.MustCompile(`(?i)^category$|^cat$`):                          SemanticCategory,
-	regexp.MustCompile(`(?i)^tag$|^label$`):                             SemanticTag,
-	regexp.MustCompile(`(?i)^slug$|^permalink$`):                        SemanticSlug,
-	regexp.MustCompile(`(?i)^code$`):                                    SemanticCode,
-	regexp.MustCompile(`(?i)^sku$|^product_?code$`):                     SemanticSKU,
-	regexp.MustCompile(`(?i)^isbn$`):                                    SemanticISBN,
-	regexp.MustCompile(`(?i)^lat$|^latitude$`):                          SemanticLatitude,
-	regexp.MustCompile(`(?i)^lng$|^lon$|^longitude$`):                   SemanticLongitude,
-	regexp.MustCompile(`(?i)^currency$|^currency_?code$`):               SemanticCurrency,
-	regexp.MustCompile(`(?i)^language$|^lang$|^locale$`):                SemanticLanguage,
-	regexp.MustCompile(`(?i)^timezone$|^tz$|^time_?zone$`):              SemanticTimezone,
-	regexp.MustCompile(`(?i)^ip$|^ip_?address$`):                        SemanticIPAddress,
-	regexp.MustCompile(`(?i)^user_?agent$|^ua$`):                        SemanticUserAgent,
-	regexp.MustCompile(`(?i)^credit_?card$|^card_?number$|^cc$`):        SemanticCreditCard,
+// semanticPattern maps field names matching pattern to a semantic type
+type semanticPattern struct {
+	pattern      *regexp.Regexp
+	semanticType SemanticFieldType
+}
+
+// semanticPatterns are evaluated in order and a field takes the type of the
+// first pattern it matches, so a name always gets the same type.
+var semanticPatterns = []semanticPattern{
+	{regexp.MustCompile(`(?i)^user_?agent$|^ua$`), SemanticUserAgent},
+	{regexp.MustCompile(`(?i)^currency$|^currency_?code$`), SemanticCurrency},
+	{regexp.MustCompile(`(?i)^sku$|^product_?code$`), SemanticSKU},
+	{regexp.MustCompile(`(?i)^zip$|^zip_?code$`), SemanticZipCode},
+	{regexp.MustCompile(`(?i)^postal_?code$|^postcode$`), SemanticPostalCode},
+	{regexp.MustCompile(`(?i)^e?mail$|^email_?address$`), SemanticEmail},
+	{regexp.MustCompile(`(?i)^ip$|^ip_?address$`), SemanticIPAddress},
+	{regexp.MustCompile(`(?i)^credit_?card$|^card_?number$|^cc$`), SemanticCreditCard},
+	{regexp.MustCompile(`(?i)^user_?name$|^login$|^handle$`), SemanticUsername},
+	{regexp.MustCompile(`(?i)^first_?name$|^given_?name$`), SemanticFirstName},
+	{regexp.MustCompile(`(?i)^last_?name$|^family_?name$|^surname$`), SemanticLastName},
+	{regexp.MustCompile(`(?i)^full_?name$|^display_?name$`), SemanticFullName},
+	{regexp.MustCompile(`(?i)^name$`), SemanticName},
+	{regexp.MustCompile(`(?i)^phone$|^phone_?number$|^mobile$|^tel$`), SemanticPhone},
+	{regexp.MustCompile(`(?i)^address$|^full_?address$`), SemanticAddress},
+	{regexp.MustCompile(`(?i)^street$|^street_?address$|^line1$`), SemanticStreet},
+	{regexp.MustCompile(`(?i)^city$|^town$`), SemanticCity},
+	{regexp.MustCompile(`(?i)^state$|^province$|^region$`), SemanticState},
+	{regexp.MustCompile(`(?i)^country$|^nation$`), SemanticCountry},
+	{regexp.MustCompile(`(?i)^url$|^link$|^href$`), SemanticURL},
+	{regexp.MustCompile(`(?i)^website$|^homepage$|^site$`), SemanticWebsite},
+	{regexp.MustCompile(`(?i)^password$|^pass$|^pwd$|^secret$`), SemanticPassword},
+	{regexp.MustCompile(`(?i)^title$|^headline$|^subject$`), SemanticTitle},
+	{regexp.MustCompile(`(?i)^description$|^desc$|^summary$|^bio$`), SemanticDescription},
+	{regexp.MustCompile(`(?i)^content$|^text$|^body$`), SemanticContent},
+	{regexp.MustCompile(`(?i)^message$|^comment$|^note$`), SemanticMessage},
+	{regexp.MustCompile(`(?i)^company$|^business$|^employer$`), SemanticCompany},
+	{regexp.MustCompile(`(?i)^organization$|^org$|^institution$`), SemanticOrganization},
+	{regexp.MustCompile(`(?i)^price$|^cost$|^fee$`), SemanticPrice},
+	{regexp.MustCompile(`(?i)^amount$|^total$|^sum$|^balance$`), SemanticAmount},
+	{regexp.MustCompile(`(?i)^quantity$|^qty$`), SemanticQuantity},
+	{regexp.MustCompile(`(?i)^count$|^num$|^number$`), SemanticCount},
+	{regexp.MustCompile(`(?i)^age$`), SemanticAge},
+	{regexp.MustCompile(`(?i)^date$`), SemanticDate},
+	{regexp.MustCompile(`(?i)^created_?at$|^creation_?date$`), SemanticCreatedAt},
+	{regexp.MustCompile(`(?i)^updated_?at$|^modified_?at$|^edit_?date$`), SemanticUpdatedAt},
+	{regexp.MustCompile(`(?i)^birthday$|^birth_?date$|^dob$`), SemanticBirthday},
+	{regexp.MustCompile(`(?i)^image$|^img$|^picture$`), SemanticImage},
+	{regexp.MustCompile(`(?i)^avatar$|^profile_?image$|^photo$`), SemanticAvatar},
+	{regexp.MustCompile(`(?i)^color$|^colour$`), SemanticColor},
+	{regexp.MustCompile(`(?i)^status$`), SemanticStatus},
+	{regexp.MustCompile(`(?i)^type$|^kind$`), SemanticType},
+	{regexp.MustCompile(`(?i)^category$|^cat$`), SemanticCategory},
+	{regexp.MustCompile(`(?i)^tag$|^label$`), SemanticTag},
+	{regexp.MustCompile(`(?i)^slug$|^permalink$`), SemanticSlug},
+	{regexp.MustCompile(`(?i)^code$`), SemanticCode},
+	{regexp.MustCompile(`(?i)^isbn$`), SemanticISBN},
+	{regexp.MustCompile(`(?i)^lat$|^latitude$`), SemanticLatitude},
+	{regexp.MustCompile(`(?i)^lng$|^lon$|^longitude$`), SemanticLongitude},
+	{regexp.MustCompile(`(?i)^language$|^lang$|^locale$`), SemanticLanguage},
+	{regexp.MustCompile(`(?i)^timezone$|^tz$|^time_?zone$`), SemanticTimezone},
+	{regexp.MustCompile(`(?i:^id$|_id$)|[a-z0-9]I[Dd]$`), SemanticID},
+}
+
+// semanticTypeNames names the semantic types for use in configuration
+var semanticTypeNames = map[string]SemanticFieldType{
+	"id":           SemanticID,
+	"name":         SemanticName,
+	"first_name":   SemanticFirstName,
+	"last_name":    SemanticLastName,
+	"full_name":    SemanticFullName,
+	"email":        SemanticEmail,
+	"phone":        SemanticPhone,
+	"address":      SemanticAddress,
+	"street":       SemanticStreet,
+	"city":         SemanticCity,
+	"state":        SemanticState,
+	"country":      SemanticCountry,
+	"zip_code":     SemanticZipCode,
+	"postal_code":  SemanticPostalCode,
+	"url":          SemanticURL,
+	"website":      SemanticWebsite,
+	"username":     SemanticUsername,
+	"password":     SemanticPassword,
+	"title":        SemanticTitle,
+	"description":  SemanticDescription,
+	"content":      SemanticContent,
+	"body":         SemanticBody,
+	"message":      SemanticMessage,
+	"company":      SemanticCompany,
+	"organization": SemanticOrganization,
+	"price":        SemanticPrice,
+	"amount":       SemanticAmount,
+	"quantity":     SemanticQuantity,
+	"count":        SemanticCount,
+	"age":          SemanticAge,
+	"date":         SemanticDate,
+	"created_at":   SemanticCreatedAt,
+	"updated_at":   SemanticUpdatedAt,
+	"birthday":     SemanticBirthday,
+	"image":        SemanticImage,
+	"avatar":       SemanticAvatar,
+	"photo":        SemanticPhoto,
+	"color":        SemanticColor,
+	"status":       SemanticStatus,
+	"type":         SemanticType,
+	"category":     SemanticCategory,
+	"tag":          SemanticTag,
+	"slug":         SemanticSlug,
+	"code":         SemanticCode,
+	"sku":          SemanticSKU,
+	"isbn":         SemanticISBN,
+	"latitude":     SemanticLatitude,
+	"longitude":    SemanticLongitude,
+	"currency":     SemanticCurrency,
+	"language":     SemanticLanguage,
+	"timezone":     SemanticTimezone,
+	"ip_address":   SemanticIPAddress,
+	"user_agent":   SemanticUserAgent,
+	"credit_card":  SemanticCreditCard,
+}
+
+// SemanticTypeByName returns the semantic type with a snake_case name such as
+// credit_card
+func SemanticTypeByName(name string) (SemanticFieldType, bool) {
+	semanticType, ok := semanticTypeNames[strings.ToLower(name)]
+	return semanticType, ok
 }
 
 // DetectSemanticType detects the semantic type of a field based on its name
 func DetectSemanticType(fieldName string) SemanticFieldType {
-	for pattern, semanticType := range semanticPatterns {
-		if pattern.MatchString(fieldName) {
-			return semanticType
+	for _, p := range semanticPatterns {
+		if p.pattern.MatchString(fieldName) {
+			return p.semanticType
 		}
 	}
 	return SemanticUnknown
@@ -145,7 +221,7 @@ func GenerateBySemanticType(semanticType SemanticFieldType, schema *openapi3.Sch
 
 	switch semanticType {
 	case SemanticID:
-		return f.UUID().V4()
+		return newUUID(f, "id")
 	case SemanticFirstName:
 		return f.Person().FirstName()
 	case SemanticLastName:
@@ -153,7 +229,7 @@ func GenerateBySemanticType(semanticType SemanticFieldType, schema *openapi3.Sch
 	case SemanticFullName, SemanticName:
 		return f.Person().Name()
 	case SemanticEmail:
-		return f.Internet().Email()
+		return generateEmail(f)
 	case SemanticPhone:
 		return f.Phone().Number()
 	case SemanticAddress:
@@ -191,9 +267,9 @@ func GenerateBySemanticType(semanticType SemanticFieldType, schema *openapi3.Sch
 	case SemanticAge:
 		return f.IntBetween(18, 80)
 	case SemanticDate:
-		return f.Time().Time(time.Now()).Format("2006-01-02")
+		return f.Time().Time(now()).Format("2006-01-02")
 	case SemanticCreatedAt, SemanticUpdatedAt:
-		return f.Time().Time(time.Now()).Format(time.RFC3339)
+		return f.Time().Time(now()).Format(time.RFC3339)
 	case SemanticBirthday:
 		year := f.IntBetween(1950, 2005)
 		month := f.IntBetween(1, 12)
@@ -569,32 +645,134 @@ func (g *patternGenerator) randomPrintable() byte {
 	return printable[g.rand.Intn(len(printable))]
 }
 
+// maxPatternAttempts bounds how often a pattern is regenerated to satisfy the
+// schema's length bounds
+const maxPatternAttempts = 50
+
+// generateFromPatternWithLength generates a string from the schema's pattern
+// that also satisfies minLength and maxLength. Values are regenerated a bounded
+// number of times; if none fit, the last one is padded or truncated as long as
+// the result still matches the pattern.
+func generateFromPatternWithLength(schema *openapi3.Schema) (string, error) {
+	var generated string
+	for attempt := 0; attempt < maxPatternAttempts; attempt++ {
+		value, err := GenerateFromPattern(schema.Pattern)
+		if err != nil {
+			return "", err
+		}
+		if withinLength(value, schema) {
+			return value, nil
+		}
+		generated = value
+	}
+
+	re, err := regexp.Compile(schema.Pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern %q: %w", schema.Pattern, err)
+	}
+
+	adjusted := generated
+	if schema.MaxLength != nil && len(adjusted) > int(*schema.MaxLength) {
+		adjusted = adjusted[:*schema.MaxLength]
+	}
+	if len(adjusted) > 0 && len(adjusted) < int(schema.MinLength) {
+		adjusted += strings.Repeat(adjusted[len(adjusted)-1:], int(schema.MinLength)-len(adjusted))
+	}
+
+	if withinLength(adjusted, schema) && re.MatchString(adjusted) {
+		return adjusted, nil
+	}
+
+	return "", fmt.Errorf("cannot generate a value for pattern %q within length bounds", schema.Pattern)
+}
+
+// generateFromPatternAndFormat generates a string that matches the schema's
+// pattern and also satisfies its format. Pattern values are tried first, then
+// values of the format, each a bounded number of times. If no value satisfies
+// both, a value matching the pattern is returned, as the pattern is usually
+// the stricter of the two.
+func generateFromPatternAndFormat(schema *openapi3.Schema) (string, error) {
+	re, err := regexp.Compile(schema.Pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern %q: %w", schema.Pattern, err)
+	}
+
+	var fallback string
+	for attempt := 0; attempt < maxPatternAttempts; attempt++ {
+		value, err := generateFromPatternWithLength(schema)
+		if err != nil {
+			return "", err
+		}
+		if validation.MatchesFormat(schema.Format, value) {
+			return value, nil
+		}
+		fallback = value
+	}
+
+	formatOnly := *schema
+	formatOnly.Pattern = ""
+	for attempt := 0; attempt < maxPatternAttempts; attempt++ {
+		value := generateString(&formatOnly)
+		if re.MatchString(value) && withinLength(value, schema) {
+			return value, nil
+		}
+	}
+
+	return fallback, nil
+}
+
+// withinLength reports whether value satisfies the schema's length bounds
+func withinLength(value string, schema *openapi3.Schema) bool {
+	if len(value) < int(schema.MinLength) {
+		return false
+	}
+	if schema.MaxLength != nil && len(value) > int(*schema.MaxLength) {
+		return false
+	}
+	return true
+}
+
 func isQuantifier(b byte) bool {
 	return b == '*' || b == '+' || b == '?' || b == '{'
 }
 
 // GenerateFromOneOf generates data from oneOf schema
 func GenerateFromOneOf(schemas []*openapi3.SchemaRef) (interface{}, error) {
+	return generateFromOneOf(schemas, nil)
+}
+
+// generateFromOneOf generates data from a oneOf schema within parents
+func generateFromOneOf(schemas []*openapi3.SchemaRef, parents nesting) (interface{}, error) {
 	if len(schemas) == 0 {
 		return nil, fmt.Errorf("oneOf requires at least one schema")
 	}
 
 	idx := rand.Intn(len(schemas))
-	return GenerateData(schemas[idx])
+	return generateData(schemas[idx], "", parents)
 }
 
 // GenerateFromAnyOf generates data from anyOf schema
 func GenerateFromAnyOf(schemas []*openapi3.SchemaRef) (interface{}, error) {
+	return generateFromAnyOf(schemas, nil)
+}
+
+// generateFromAnyOf generates data from an anyOf schema within parents
+func generateFromAnyOf(schemas []*openapi3.SchemaRef, parents nesting) (interface{}, error) {
 	if len(schemas) == 0 {
 		return nil, fmt.Errorf("anyOf requires at least one schema")
 	}
 
 	idx := rand.Intn(len(schemas))
-	return GenerateData(schemas[idx])
+	return generateData(schemas[idx], "", parents)
 }
 
 // GenerateFromAllOf generates data merging all schemas in allOf
 func GenerateFromAllOf(schemas []*openapi3.SchemaRef) (interface{}, error) {
+	return generateFromAllOf(schemas, nil)
+}
+
+// generateFromAllOf generates data from an allOf schema within parents
+func generateFromAllOf(schemas []*openapi3.SchemaRef, parents nesting) (interface{}, error) {
 	if len(schemas) == 0 {
 		return nil, fmt.Errorf("allOf requires at least one schema")
 	}
@@ -606,7 +784,7 @@ func GenerateFromAllOf(schemas []*openapi3.SchemaRef) (interface{}, error) {
 			continue
 		}
 
-		data, err := GenerateData(schemaRef)
+		data, err := generateData(schemaRef, "", parents)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate allOf component: %w", err)
 		}
@@ -621,41 +799,75 @@ func GenerateFromAllOf(schemas []*openapi3.SchemaRef) (interface{}, error) {
 	return result, nil
 }
 
-// GenerateAdvancedData generates data with advanced features
+// GenerateAdvancedData generates data with advanced features. Failures are
+// returned as a *GenerationError naming the field that caused them.
 func GenerateAdvancedData(schema *openapi3.SchemaRef, fieldName string) (interface{}, error) {
+	return generateAdvanced(schema, fieldName, fieldName, nil)
+}
+
+// generateAdvanced generates data for the schema of the field at path, nested
+// in parents
+func generateAdvanced(schema *openapi3.SchemaRef, fieldName, path string, parents nesting) (interface{}, error) {
 	if schema == nil || schema.Value == nil {
-		return nil, fmt.Errorf("schema is nil")
+		return nil, wrapGenerationError(fmt.Errorf("schema is nil"), path, "")
 	}
 
+	data, err := generateAdvancedValue(schema, fieldName, path, parents)
+	if err != nil {
+		return nil, wrapGenerationError(err, path, schema.Value.Type)
+	}
+	return data, nil
+}
+
+func generateAdvancedValue(schema *openapi3.SchemaRef, fieldName, path string, parents nesting) (interface{}, error) {
 	s := schema.Value
 
 	if s.Example != nil {
 		return s.Example, nil
 	}
 
+	// Nullable values are sometimes null, to exercise null handling
+	if s.Nullable && generateNull() {
+		return nil, nil
+	}
+
+	if value, truncated := truncate(s, parents); truncated {
+		return value, nil
+	}
+
 	if len(s.OneOf) > 0 {
-		return GenerateFromOneOf(s.OneOf)
+		return generateFromOneOf(s.OneOf, parents)
 	}
 
 	if len(s.AnyOf) > 0 {
-		return GenerateFromAnyOf(s.AnyOf)
+		return generateFromAnyOf(s.AnyOf, parents)
 	}
 
 	if len(s.AllOf) > 0 {
-		return GenerateFromAllOf(s.AllOf)
+		return generateFromAllOf(s.AllOf, parents)
 	}
 
 	if len(s.Enum) > 0 {
-		return s.Enum[rand.Intn(len(s.Enum))], nil
+		return pickEnum(fieldName, s.Enum), nil
 	}
 
 	if s.Type == "string" && s.Pattern != "" {
-		generated, err := GenerateFromPattern(s.Pattern)
+		generate := generateFromPatternWithLength
+		if s.Format != "" {
+			generate = generateFromPatternAndFormat
+		}
+		generated, err := generate(s)
 		if err == nil {
 			return generated, nil
 		}
 	}
 
+	if fieldName != "" {
+		if value, ok := generateCustomSemantic(fieldName, s); ok {
+			return value, nil
+		}
+	}
+
 	if s.Type == "string" && fieldName != "" {
 		semanticType := DetectSemanticType(fieldName)
 		if semanticType != SemanticUnknown {
@@ -666,24 +878,35 @@ func GenerateAdvancedData(schema *openapi3.SchemaRef, fieldName string) (interfa
 	}
 
 	if s.Type == "object" {
-		return generateAdvancedObject(s)
+		return generateAdvancedObject(s, path, parents)
 	}
 
 	if s.Type == "array" {
-		return generateAdvancedArray(s)
+		return generateAdvancedArray(s, path, parents)
 	}
 
-	return GenerateData(schema)
+	return generateData(schema, "", parents)
 }
 
-func generateAdvancedObject(schema *openapi3.Schema) (map[string]interface{}, error) {
+func generateAdvancedObject(schema *openapi3.Schema, path string, parents nesting) (map[string]interface{}, error) {
 	obj := make(map[string]interface{})
 	f := faker.New()
 
 	for name, propSchema := range schema.Properties {
-		data, err := GenerateAdvancedData(propSchema, name)
+		// Generated data stands for what the server returns, which never
+		// includes writeOnly properties such as passwords
+		if isWriteOnly(propSchema) {
+			continue
+		}
+
+		// Like real payloads, objects leave out some optional properties
+		if !isRequired(schema, name) && !includeOptional() {
+			continue
+		}
+
+		data, err := generateAdvanced(propSchema, name, joinPath(path, name), parents.enter(schema))
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate property %s: %w", name, err)
+			return nil, err
 		}
 		obj[name] = data
 	}
@@ -692,7 +915,7 @@ func generateAdvancedObject(schema *openapi3.Schema) (map[string]interface{}, er
 		if allOfSchema.Value == nil {
 			continue
 		}
-		allOfObj, err := generateAdvancedObject(allOfSchema.Value)
+		allOfObj, err := generateAdvancedObject(allOfSchema.Value, path, parents)
 		if err != nil {
 			return nil, err
 		}
@@ -701,10 +924,18 @@ func generateAdvancedObject(schema *openapi3.Schema) (map[string]interface{}, er
 		}
 	}
 
+	// Properties whose presence requires others must bring them along, or
+	// the object would fail dependentRequired
+	if err := addDependentProperties(schema, obj, path, parents); err != nil {
+		return nil, err
+	}
+
+	deriveCompoundFields(schema, obj)
+
 	if schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has {
 		for i := 0; i < f.IntBetween(1, 3); i++ {
 			key := f.Lorem().Word()
-			val, err := GenerateAdvancedData(schema.AdditionalProperties.Schema, key)
+			val, err := generateAdvanced(schema.AdditionalProperties.Schema, key, joinPath(path, key), parents.enter(schema))
 			if err != nil {
 				return nil, err
 			}
@@ -715,7 +946,135 @@ func generateAdvancedObject(schema *openapi3.Schema) (map[string]interface{}, er
 	return obj, nil
 }
 
-func generateAdvancedArray(schema *openapi3.Schema) ([]interface{}, error) {
+// isRequired reports whether a schema lists a property as required
+func isRequired(schema *openapi3.Schema, name string) bool {
+	for _, required := range schema.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isWriteOnly reports whether a property is only sent in requests
+func isWriteOnly(schema *openapi3.SchemaRef) bool {
+	return schema != nil && schema.Value != nil && schema.Value.WriteOnly
+}
+
+// addDependentProperties generates the properties that dependentRequired
+// makes required by those already in obj, until every dependency holds.
+// Dependencies declared in allOf subschemas apply as well.
+func addDependentProperties(schema *openapi3.Schema, obj map[string]interface{}, path string, parents nesting) error {
+	dependencies := dependentRequired(schema)
+	for changed := true; changed; {
+		changed = false
+		for name, required := range dependencies {
+			if _, ok := obj[name]; !ok {
+				continue
+			}
+			for _, dependent := range required {
+				if _, ok := obj[dependent]; ok {
+					continue
+				}
+
+				// Undeclared properties get a value for their name
+				propSchema := propertySchema(schema, dependent)
+				if propSchema == nil || propSchema.Value == nil {
+					propSchema = openapi3.NewStringSchema().NewRef()
+				}
+				data, err := generateAdvanced(propSchema, dependent, joinPath(path, dependent), parents.enter(schema))
+				if err != nil {
+					return err
+				}
+				obj[dependent] = data
+				changed = true
+			}
+		}
+	}
+	return nil
+}
+
+// dependentRequired collects the dependentRequired entries of a schema and
+// its allOf subschemas
+func dependentRequired(schema *openapi3.Schema) map[string][]string {
+	dependencies := openapi.DependentRequired(schema)
+	for _, allOfSchema := range schema.AllOf {
+		if allOfSchema.Value == nil {
+			continue
+		}
+		for name, required := range dependentRequired(allOfSchema.Value) {
+			if dependencies == nil {
+				dependencies = make(map[string][]string)
+			}
+			dependencies[name] = append(dependencies[name], required...)
+		}
+	}
+	return dependencies
+}
+
+// propertySchema finds the schema of a property declared by a schema or its
+// allOf subschemas
+func propertySchema(schema *openapi3.Schema, name string) *openapi3.SchemaRef {
+	if propSchema, ok := schema.Properties[name]; ok {
+		return propSchema
+	}
+	for _, allOfSchema := range schema.AllOf {
+		if allOfSchema.Value == nil {
+			continue
+		}
+		if propSchema := propertySchema(allOfSchema.Value, name); propSchema != nil {
+			return propSchema
+		}
+	}
+	return nil
+}
+
+// deriveCompoundFields makes fields that combine other fields agree with
+// them: a full name is built from the first and last names, and a slug from
+// the title. A field keeps its generated value when it has an example or the
+// derived value would break its own schema, such as its maxLength or enum.
+func deriveCompoundFields(schema *openapi3.Schema, obj map[string]interface{}) {
+	fields := make(map[SemanticFieldType]string)
+	for name, value := range obj {
+		if _, ok := value.(string); ok {
+			fields[DetectSemanticType(name)] = name
+		}
+	}
+
+	derive := func(name, value string) {
+		if propSchema := propertySchema(schema, name); propSchema != nil && propSchema.Value != nil {
+			if propSchema.Value.Example != nil {
+				return
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil || len(validation.ValidateSchema(propSchema, encoded).Errors()) > 0 {
+				return
+			}
+		}
+		obj[name] = value
+	}
+
+	first, hasFirst := fields[SemanticFirstName]
+	last, hasLast := fields[SemanticLastName]
+	if full, ok := fields[SemanticFullName]; ok && hasFirst && hasLast {
+		derive(full, obj[first].(string)+" "+obj[last].(string))
+	}
+
+	if slug, ok := fields[SemanticSlug]; ok {
+		if title, ok := fields[SemanticTitle]; ok {
+			derive(slug, slugify(obj[title].(string)))
+		}
+	}
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases s and joins its words with hyphens
+func slugify(s string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+func generateAdvancedArray(schema *openapi3.Schema, path string, parents nesting) ([]interface{}, error) {
 	f := faker.New()
 	minItems := int(schema.MinItems)
 	maxItems := 0
@@ -739,7 +1098,7 @@ func generateAdvancedArray(schema *openapi3.Schema) ([]interface{}, error) {
 
 	arr := make([]interface{}, count)
 	for i := 0; i < count; i++ {
-		item, err := GenerateAdvancedData(schema.Items, "")
+		item, err := generateAdvanced(schema.Items, "", path+"[]", parents.enter(schema))
 		if err != nil {
 			return nil, err
 		}
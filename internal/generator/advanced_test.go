@@ -1,10 +1,15 @@
 package generator
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/felipevolpatto/meridian/internal/validation"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
@@ -16,6 +21,12 @@ func TestDetectSemanticType(t *testing.T) {
 		{"id", SemanticID},
 		{"user_id", SemanticID},
 		{"userId", SemanticID},
+		{"userID", SemanticID},
+		{"USER_ID", SemanticID},
+		{"humid", SemanticUnknown},
+		{"valid", SemanticUnknown},
+		{"android", SemanticUnknown},
+		{"rapid", SemanticUnknown},
 		{"email", SemanticEmail},
 		{"email_address", SemanticEmail},
 		{"first_name", SemanticFirstName},
@@ -60,6 +71,14 @@ func TestDetectSemanticType(t *testing.T) {
 		{"language", SemanticLanguage},
 		{"timezone", SemanticTimezone},
 		{"ip_address", SemanticIPAddress},
+		{"user_agent", SemanticUserAgent},
+		{"userAgent", SemanticUserAgent},
+		{"user_name", SemanticUsername},
+		{"currency_code", SemanticCurrency},
+		{"product_code", SemanticSKU},
+		{"code", SemanticCode},
+		{"email_address", SemanticEmail},
+		{"card_number", SemanticCreditCard},
 		{"random_field", SemanticUnknown},
 		{"xyz", SemanticUnknown},
 	}
@@ -74,6 +93,253 @@ func TestDetectSemanticType(t *testing.T) {
 	}
 }
 
+func TestDetectSemanticType_Deterministic(t *testing.T) {
+	// Names that more than one pattern could claim keep the same type on
+	// every call
+	for _, name := range []string{"user_agent", "user_id", "currency_code", "product_code", "zip_code"} {
+		first := DetectSemanticType(name)
+		for i := 0; i < 100; i++ {
+			if got := DetectSemanticType(name); got != first {
+				t.Fatalf("DetectSemanticType(%q) = %v, then %v", name, first, got)
+			}
+		}
+	}
+}
+
+func TestRegisterSemantic(t *testing.T) {
+	defer ResetSemantics()
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	iban := regexp.MustCompile(`^PT50[0-9]{21}$`)
+	err := RegisterSemantic(`(?i)^iban$`, func(*openapi3.Schema) interface{} {
+		value, _ := GenerateFromPattern(`PT50[0-9]{21}`)
+		return value
+	})
+	if err != nil {
+		t.Fatalf("RegisterSemantic error: %v", err)
+	}
+	vin, err := SemanticGeneratorFor("", "", []string{"1HGCM82633A004352"})
+	if err != nil {
+		t.Fatalf("SemanticGeneratorFor error: %v", err)
+	}
+	if err := RegisterSemantic(`^vin$`, vin); err != nil {
+		t.Fatalf("RegisterSemantic error: %v", err)
+	}
+
+	// Custom mappings take precedence over the built-in types
+	code, err := SemanticGeneratorFor(`[A-Z]{3}`, "", nil)
+	if err != nil {
+		t.Fatalf("SemanticGeneratorFor error: %v", err)
+	}
+	if err := RegisterSemantic(`^email$`, code); err != nil {
+		t.Fatalf("RegisterSemantic error: %v", err)
+	}
+
+	stringProp := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"IBAN":  stringProp,
+				"vin":   stringProp,
+				"email": stringProp,
+			},
+		},
+	}
+
+	for _, generate := range []func() (interface{}, error){
+		func() (interface{}, error) { return GenerateData(schema) },
+		func() (interface{}, error) { return GenerateAdvancedData(schema, "") },
+	} {
+		result, err := generate()
+		if err != nil {
+			t.Fatalf("Generation error: %v", err)
+		}
+		obj := result.(map[string]interface{})
+
+		if value, ok := obj["IBAN"].(string); !ok || !iban.MatchString(value) {
+			t.Errorf("Expected IBAN from the custom generator, got %v", obj["IBAN"])
+		}
+		if obj["vin"] != "1HGCM82633A004352" {
+			t.Errorf("Expected vin from the configured values, got %v", obj["vin"])
+		}
+		if value, ok := obj["email"].(string); !ok || !regexp.MustCompile(`^[A-Z]{3}$`).MatchString(value) {
+			t.Errorf("Expected email from the custom mapping, got %v", obj["email"])
+		}
+	}
+
+	ResetSemantics()
+	if value, _ := GenerateAdvancedData(stringProp, "vin"); value == "1HGCM82633A004352" {
+		t.Errorf("Expected reset to remove custom mappings")
+	}
+}
+
+func TestSemanticGeneratorFor_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		typeName string
+		values   []string
+	}{
+		{name: "Nothing", pattern: "", typeName: "", values: nil},
+		{name: "Several", pattern: "[0-9]+", typeName: "code", values: nil},
+		{name: "Unknown type", typeName: "iban"},
+		{name: "Invalid pattern", pattern: "[0-9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SemanticGeneratorFor(tt.pattern, tt.typeName, tt.values); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	if err := RegisterSemantic("^(iban", nil); err == nil {
+		t.Error("Expected an error for an invalid field pattern")
+	}
+}
+
+func TestGenerateAdvancedData_EnumWeights(t *testing.T) {
+	SetEnumWeights(map[string]map[string]float64{"status": {"active": 90, "inactive": 5, "pending": 5}})
+	defer SetEnumWeights(nil)
+
+	enum := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{Type: "string", Enum: []interface{}{"inactive", "active", "pending"}},
+	}
+
+	counts := make(map[interface{}]int)
+	for i := 0; i < 1000; i++ {
+		result, err := GenerateAdvancedData(enum, "status")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+		counts[result]++
+	}
+	if counts["active"] < 800 {
+		t.Errorf("Expected active to dominate, got %v", counts)
+	}
+	if counts["active"] == 1000 {
+		t.Errorf("Expected other values to be generated too, got %v", counts)
+	}
+
+	// Values without a weight are never picked
+	SetEnumWeights(map[string]map[string]float64{"status": {"active": 1}})
+	for i := 0; i < 100; i++ {
+		if result, _ := GenerateAdvancedData(enum, "status"); result != "active" {
+			t.Fatalf("Expected only active, got %v", result)
+		}
+	}
+
+	// Other fields are picked uniformly
+	counts = make(map[interface{}]int)
+	for i := 0; i < 300; i++ {
+		result, _ := GenerateAdvancedData(enum, "state")
+		counts[result]++
+	}
+	if len(counts) != 3 {
+		t.Errorf("Expected every value for an unweighted field, got %v", counts)
+	}
+}
+
+func TestGenerateAdvancedData_RecursiveSchema(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Categories API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Category:
+      type: object
+      required: [name, parent, children]
+      properties:
+        name:
+          type: string
+        parent:
+          $ref: '#/components/schemas/Category'
+        children:
+          type: array
+          minItems: 1
+          items:
+            $ref: '#/components/schemas/Category'
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	category := spec.Components.Schemas["Category"]
+
+	// depth counts the categories nested through parent
+	var depth func(value interface{}) int
+	depth = func(value interface{}) int {
+		obj, ok := value.(map[string]interface{})
+		if !ok || len(obj) == 0 {
+			return 0
+		}
+		return 1 + depth(obj["parent"])
+	}
+
+	SetMaxDepth(3)
+	defer SetMaxDepth(0)
+
+	for name, generate := range map[string]func() (interface{}, error){
+		"Advanced": func() (interface{}, error) { return GenerateAdvancedData(category, "") },
+		"Basic":    func() (interface{}, error) { return GenerateData(category) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := generate()
+			if err != nil {
+				t.Fatalf("Generation error: %v", err)
+			}
+			if got := depth(result); got != 3 {
+				t.Errorf("Expected 3 nested categories through parent, got %d", got)
+			}
+		})
+	}
+}
+
+func TestGenerateAdvancedData_DeepSchema(t *testing.T) {
+	// Schemas nested deeper than the maximum depth without recursing are
+	// generated in full
+	leaf := openapi3.NewObjectSchema().WithProperty("value", openapi3.NewStringSchema())
+	leaf.Required = []string{"value"}
+	schema := leaf
+	for i := 0; i < 8; i++ {
+		items := openapi3.NewArraySchema().WithItems(schema).WithMinItems(1)
+		schema = openapi3.NewObjectSchema().WithProperty("items", items)
+		schema.Required = []string{"items"}
+	}
+
+	SetMaxDepth(2)
+	defer SetMaxDepth(0)
+
+	for name, generate := range map[string]func() (interface{}, error){
+		"Advanced": func() (interface{}, error) { return GenerateAdvancedData(schema.NewRef(), "") },
+		"Basic":    func() (interface{}, error) { return GenerateData(schema.NewRef()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := generate()
+			if err != nil {
+				t.Fatalf("Generation error: %v", err)
+			}
+			value := result
+			for i := 0; i < 8; i++ {
+				items, _ := value.(map[string]interface{})["items"].([]interface{})
+				if len(items) == 0 {
+					t.Fatalf("Expected items at level %d, got %v", i, value)
+				}
+				value = items[0]
+			}
+			if _, ok := value.(map[string]interface{})["value"].(string); !ok {
+				t.Errorf("Expected a value in the innermost object, got %v", value)
+			}
+		})
+	}
+}
+
 func TestGenerateBySemanticType(t *testing.T) {
 	schema := &openapi3.Schema{Type: "string"}
 
@@ -334,6 +600,147 @@ func TestGenerateFromAllOf_Empty(t *testing.T) {
 	}
 }
 
+func TestGenerateData_Tuple(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type:     "array",
+		MinItems: 4,
+		Extensions: map[string]interface{}{
+			"prefixItems": []interface{}{
+				map[string]interface{}{"type": "integer"},
+				map[string]interface{}{"type": "string"},
+			},
+			"additionalItems": false,
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		result, err := GenerateData(&openapi3.SchemaRef{Value: schema})
+		if err != nil {
+			t.Fatalf("GenerateData error: %v", err)
+		}
+
+		arr, ok := result.([]interface{})
+		if !ok {
+			t.Fatalf("Expected array, got %T", result)
+		}
+		if len(arr) != 2 {
+			t.Fatalf("Expected exactly 2 items, got %d", len(arr))
+		}
+		if _, ok := arr[1].(string); !ok {
+			t.Errorf("Expected string at position 1, got %T", arr[1])
+		}
+
+		generated, err := New().Generate(schema, &GenerationContext{})
+		if err != nil {
+			t.Fatalf("Generate error: %v", err)
+		}
+		if items, ok := generated.([]interface{}); !ok || len(items) != 2 {
+			t.Errorf("Expected Generator to produce 2 items, got %v", generated)
+		}
+	}
+}
+
+func TestGenerateData_IntegerMultipleOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+		factor   float64
+	}{
+		{name: "positive range", min: 1, max: 100, factor: 5},
+		{name: "negative range", min: -50, max: -10, factor: 7},
+		{name: "single multiple", min: 11, max: 16, factor: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &openapi3.Schema{
+				Type:       "integer",
+				Min:        openapi3.Float64Ptr(tt.min),
+				Max:        openapi3.Float64Ptr(tt.max),
+				MultipleOf: openapi3.Float64Ptr(tt.factor),
+			}
+
+			for i := 0; i < 20; i++ {
+				result, err := GenerateData(&openapi3.SchemaRef{Value: schema})
+				if err != nil {
+					t.Fatalf("GenerateData error: %v", err)
+				}
+				generated, err := New().Generate(schema, &GenerationContext{})
+				if err != nil {
+					t.Fatalf("Generate error: %v", err)
+				}
+
+				for _, value := range []interface{}{result, generated} {
+					n, ok := value.(int64)
+					if !ok {
+						t.Fatalf("Expected int64, got %T", value)
+					}
+					if n%int64(tt.factor) != 0 || float64(n) < tt.min || float64(n) > tt.max {
+						t.Errorf("Expected a multiple of %v in [%v, %v], got %d", tt.factor, tt.min, tt.max, n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateData_ExclusiveBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+	}{
+		{name: "integer", schema: &openapi3.Schema{Type: "integer", Min: openapi3.Float64Ptr(0), Max: openapi3.Float64Ptr(2), ExclusiveMin: true, ExclusiveMax: true}},
+		{name: "number", schema: &openapi3.Schema{Type: "number", Min: openapi3.Float64Ptr(0), Max: openapi3.Float64Ptr(0.02), ExclusiveMin: true, ExclusiveMax: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				result, err := GenerateData(&openapi3.SchemaRef{Value: tt.schema})
+				if err != nil {
+					t.Fatalf("GenerateData error: %v", err)
+				}
+				generated, err := New().Generate(tt.schema, &GenerationContext{})
+				if err != nil {
+					t.Fatalf("Generate error: %v", err)
+				}
+
+				for _, value := range []interface{}{result, generated} {
+					var n float64
+					switch v := value.(type) {
+					case int64:
+						n = float64(v)
+					case float64:
+						n = v
+					default:
+						t.Fatalf("Expected a number, got %T", value)
+					}
+					if n <= *tt.schema.Min || n >= *tt.schema.Max {
+						t.Errorf("Expected a value in (%v, %v), got %v", *tt.schema.Min, *tt.schema.Max, n)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateData_IntegerMultipleOfEmptyRange(t *testing.T) {
+	schema := &openapi3.Schema{
+		Type:       "integer",
+		Min:        openapi3.Float64Ptr(11),
+		Max:        openapi3.Float64Ptr(14),
+		MultipleOf: openapi3.Float64Ptr(10),
+	}
+
+	result, err := GenerateData(&openapi3.SchemaRef{Value: schema})
+	if err != nil {
+		t.Fatalf("GenerateData error: %v", err)
+	}
+	if n := result.(int64); n < 11 || n > 14 {
+		t.Errorf("Expected a value in [11, 14] when no multiple fits, got %d", n)
+	}
+}
+
 func TestGenerateAdvancedData_WithSemanticDetection(t *testing.T) {
 	schema := &openapi3.SchemaRef{
 		Value: &openapi3.Schema{Type: "string"},
@@ -378,6 +785,88 @@ func TestGenerateAdvancedData_WithPattern(t *testing.T) {
 	}
 }
 
+func TestGenerateAdvancedData_PatternAndFormat(t *testing.T) {
+	// Dots in the local part make many pattern values invalid addresses, such
+	// as ".ab@example.com" or "a..b@example.com"
+	pattern := `^[a-z.]{1,10}@example\.com$`
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Format: "email", Pattern: pattern}}
+	re := regexp.MustCompile(pattern)
+
+	for i := 0; i < 100; i++ {
+		result, err := GenerateAdvancedData(schema, "contact")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+
+		str, ok := result.(string)
+		if !ok {
+			t.Fatalf("Expected string, got %T", result)
+		}
+		if !re.MatchString(str) {
+			t.Errorf("%q does not match pattern %s", str, pattern)
+		}
+		if address, err := mail.ParseAddress(str); err != nil || address.Address != str {
+			t.Errorf("%q is not a valid email: %v", str, err)
+		}
+	}
+}
+
+func TestGenerateAdvancedData_PatternWithLength(t *testing.T) {
+	maxLength := uint64(12)
+
+	tests := []struct {
+		name    string
+		schema  *openapi3.Schema
+		minLen  int
+		maxLen  int
+		pattern string
+	}{
+		{
+			name:    "pads short values",
+			schema:  &openapi3.Schema{Type: "string", Pattern: `^\d+$`, MinLength: 8, MaxLength: &maxLength},
+			minLen:  8,
+			maxLen:  12,
+			pattern: `^\d+$`,
+		},
+		{
+			name:    "truncates long values",
+			schema:  &openapi3.Schema{Type: "string", Pattern: `^[a-z]{15,20}$|^[a-z]+$`, MaxLength: &maxLength},
+			minLen:  1,
+			maxLen:  12,
+			pattern: `^[a-z]+$`,
+		},
+		{
+			name:    "regenerates within bounds",
+			schema:  &openapi3.Schema{Type: "string", Pattern: `^[A-Z]{2,14}$`, MinLength: 10, MaxLength: &maxLength},
+			minLen:  10,
+			maxLen:  12,
+			pattern: `^[A-Z]{10,12}$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				result, err := GenerateAdvancedData(&openapi3.SchemaRef{Value: tt.schema}, "")
+				if err != nil {
+					t.Fatalf("GenerateAdvancedData error: %v", err)
+				}
+
+				str, ok := result.(string)
+				if !ok {
+					t.Fatalf("Expected string, got %T", result)
+				}
+				if len(str) < tt.minLen || len(str) > tt.maxLen {
+					t.Errorf("Expected length between %d and %d, got %q", tt.minLen, tt.maxLen, str)
+				}
+				if matched, _ := regexp.MatchString(tt.pattern, str); !matched {
+					t.Errorf("Expected %q to match %s", str, tt.pattern)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateAdvancedData_WithOneOf(t *testing.T) {
 	schema := &openapi3.SchemaRef{
 		Value: &openapi3.Schema{
@@ -399,6 +888,10 @@ func TestGenerateAdvancedData_WithOneOf(t *testing.T) {
 }
 
 func TestGenerateAdvancedData_Object(t *testing.T) {
+	// Every optional property is generated
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
 	schema := &openapi3.SchemaRef{
 		Value: &openapi3.Schema{
 			Type: "object",
@@ -431,6 +924,323 @@ func TestGenerateAdvancedData_Object(t *testing.T) {
 	}
 }
 
+func TestGenerateAdvancedData_CompoundFields(t *testing.T) {
+	// Every optional property is generated
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	stringProp := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"first_name": stringProp,
+				"last_name":  stringProp,
+				"full_name":  stringProp,
+				"title":      stringProp,
+				"slug":       stringProp,
+			},
+		},
+	}
+
+	result, err := GenerateAdvancedData(schema, "")
+	if err != nil {
+		t.Fatalf("GenerateAdvancedData error: %v", err)
+	}
+	obj := result.(map[string]interface{})
+
+	expected := fmt.Sprintf("%s %s", obj["first_name"], obj["last_name"])
+	if obj["full_name"] != expected {
+		t.Errorf("Expected full_name %q, got %v", expected, obj["full_name"])
+	}
+
+	if obj["slug"] != slugify(obj["title"].(string)) {
+		t.Errorf("Expected slug derived from title %q, got %v", obj["title"], obj["slug"])
+	}
+}
+
+func TestGenerateAdvancedData_CompoundFieldsKeepConstraints(t *testing.T) {
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	stringProp := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"first_name": stringProp,
+				"last_name":  stringProp,
+				"full_name":  &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Enum: []interface{}{"Ada Lovelace"}}},
+				"title":      stringProp,
+				"slug":       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Example: "launch-notes"}},
+			},
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		result, err := GenerateAdvancedData(schema, "")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+		obj := result.(map[string]interface{})
+
+		// A derived full name outside the enum would be invalid
+		if obj["full_name"] != "Ada Lovelace" {
+			t.Errorf("Expected full_name from its enum, got %v", obj["full_name"])
+		}
+		if obj["slug"] != "launch-notes" {
+			t.Errorf("Expected slug to keep its example, got %v", obj["slug"])
+		}
+	}
+
+	// Pattern constraints hold as well
+	schema.Value.Properties["slug"] = &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Pattern: `^[A-Z]{4}$`}}
+	result, err := GenerateAdvancedData(schema, "")
+	if err != nil {
+		t.Fatalf("GenerateAdvancedData error: %v", err)
+	}
+	if slug, _ := result.(map[string]interface{})["slug"].(string); !regexp.MustCompile(`^[A-Z]{4}$`).MatchString(slug) {
+		t.Errorf("Expected slug matching its pattern, got %q", slug)
+	}
+}
+
+func TestGenerateAdvancedData_DependentRequired(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Payments API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Payment:
+      type: object
+      properties:
+        amount:
+          type: number
+        credit_card:
+          type: string
+        cardholder:
+          type: object
+          properties:
+            name:
+              type: string
+      dependentRequired:
+        credit_card: [email, cardholder]
+        email: [card_expiry]
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	payment := spec.Components.Schemas["Payment"]
+
+	// Every object has the optional credit card and its dependents
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	dependencies := map[string][]string{
+		"credit_card": {"email", "cardholder"},
+		"email":       {"card_expiry"},
+	}
+
+	for i := 0; i < 50; i++ {
+		result, err := GenerateAdvancedData(payment, "")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+		obj := result.(map[string]interface{})
+
+		for name, required := range dependencies {
+			if _, ok := obj[name]; !ok {
+				continue
+			}
+			for _, dependent := range required {
+				if _, ok := obj[dependent]; !ok {
+					t.Fatalf("Expected %s to come with %s, got %v", name, dependent, obj)
+				}
+			}
+		}
+
+		// Undeclared dependents are generated for their name
+		if email, ok := obj["email"].(string); !ok || !strings.Contains(email, "@") {
+			t.Errorf("Expected email to be an email, got %v", obj["email"])
+		}
+		if _, ok := obj["cardholder"].(map[string]interface{}); !ok {
+			t.Errorf("Expected cardholder from its declared schema, got %T", obj["cardholder"])
+		}
+	}
+}
+
+func TestGenerateAdvancedData_OptionalProbability(t *testing.T) {
+	SetOptionalProbability(0.5)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	stringProp := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"id":       stringProp,
+				"name":     stringProp,
+				"nickname": stringProp,
+				"bio":      stringProp,
+			},
+			Required: []string{"id", "name"},
+		},
+	}
+
+	missing := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		result, err := GenerateAdvancedData(schema, "")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+		obj := result.(map[string]interface{})
+		for name := range schema.Value.Properties {
+			if _, ok := obj[name]; !ok {
+				missing[name]++
+			}
+		}
+	}
+
+	for _, name := range []string{"id", "name"} {
+		if missing[name] > 0 {
+			t.Errorf("Expected required %s in every object, missing from %d", name, missing[name])
+		}
+	}
+	for _, name := range []string{"nickname", "bio"} {
+		if missing[name] == 0 || missing[name] == 200 {
+			t.Errorf("Expected optional %s in some objects only, missing from %d of 200", name, missing[name])
+		}
+	}
+}
+
+func TestSetOptionalProbability(t *testing.T) {
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	SetOptionalProbability(0)
+	for i := 0; i < 100; i++ {
+		if includeOptional() {
+			t.Fatal("Expected no optional properties with probability 0")
+		}
+	}
+
+	SetOptionalProbability(1)
+	for i := 0; i < 100; i++ {
+		if !includeOptional() {
+			t.Fatal("Expected every optional property with probability 1")
+		}
+	}
+}
+
+func TestGenerateData_OmitsWriteOnly(t *testing.T) {
+	stringProp := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}}
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"id":       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", ReadOnly: true}},
+				"name":     stringProp,
+				"password": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", WriteOnly: true}},
+			},
+			Required: []string{"id", "name", "password"},
+		},
+	}
+
+	generators := map[string]func() (interface{}, error){
+		"GenerateData":         func() (interface{}, error) { return GenerateData(schema) },
+		"GenerateAdvancedData": func() (interface{}, error) { return GenerateAdvancedData(schema, "") },
+	}
+	for name, generate := range generators {
+		result, err := generate()
+		if err != nil {
+			t.Fatalf("%s error: %v", name, err)
+		}
+		obj := result.(map[string]interface{})
+
+		if _, ok := obj["password"]; ok {
+			t.Errorf("%s: expected writeOnly password to be omitted, got %v", name, obj)
+		}
+		if _, ok := obj["id"]; !ok {
+			t.Errorf("%s: expected readOnly id, got %v", name, obj)
+		}
+	}
+}
+
+func TestSetNullProbability(t *testing.T) {
+	defer SetNullProbability(DefaultNullProbability)
+
+	SetNullProbability(0)
+	for i := 0; i < 100; i++ {
+		if generateNull() {
+			t.Fatal("Expected no nulls with probability 0")
+		}
+	}
+
+	SetNullProbability(1)
+	for i := 0; i < 100; i++ {
+		if !generateNull() {
+			t.Fatal("Expected only nulls with probability 1")
+		}
+	}
+}
+
+func TestGenerateAdvancedData_Nullable(t *testing.T) {
+	SetNullProbability(0.3)
+	defer SetNullProbability(DefaultNullProbability)
+
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"nickname": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Nullable: true}},
+				"name":     &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}},
+			},
+			Required: []string{"nickname", "name"},
+		},
+	}
+
+	nulls := 0
+	for i := 0; i < 200; i++ {
+		result, err := GenerateAdvancedData(schema, "")
+		if err != nil {
+			t.Fatalf("GenerateAdvancedData error: %v", err)
+		}
+		obj := result.(map[string]interface{})
+
+		if obj["name"] == nil {
+			t.Fatalf("Expected non-nullable name to never be null, got %v", obj)
+		}
+		if obj["nickname"] == nil {
+			nulls++
+		}
+
+		data, _ := json.Marshal(obj)
+		if errs := validation.ValidateSchema(schema, data); len(errs) > 0 {
+			t.Fatalf("Expected generated object to be valid, got %v", errs)
+		}
+	}
+
+	if nulls == 0 || nulls == 200 {
+		t.Errorf("Expected nullable nickname to be null in some objects only, null in %d of 200", nulls)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":             "hello-world",
+		"Go 1.21: What's New?":    "go-1-21-what-s-new",
+		"  Leading and trailing ": "leading-and-trailing",
+	}
+
+	for input, expected := range tests {
+		if got := slugify(input); got != expected {
+			t.Errorf("slugify(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
 func TestMakeUnique(t *testing.T) {
 	arr := []interface{}{"a", "b", "a", "c", "b", "d"}
 	result := makeUnique(arr)
@@ -473,3 +1283,132 @@ func TestToSnakeCase(t *testing.T) {
 func ptr(f float64) *float64 {
 	return &f
 }
+
+func TestDeterministicUUIDs(t *testing.T) {
+	defer DisableDeterministicUUIDs()
+
+	schema := &openapi3.SchemaRef{
+		Value: &openapi3.Schema{
+			Type: "object",
+			Properties: openapi3.Schemas{
+				"id":       &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string"}},
+				"tracking": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Format: "uuid"}},
+			},
+		},
+	}
+
+	run := func(seed string) []interface{} {
+		SetDeterministicUUIDs(seed)
+		var values []interface{}
+		for i := 0; i < 3; i++ {
+			data, err := GenerateDataWithFieldName(schema, "")
+			if err != nil {
+				t.Fatalf("GenerateDataWithFieldName error: %v", err)
+			}
+			obj := data.(map[string]interface{})
+			values = append(values, obj["id"], obj["tracking"])
+		}
+		return values
+	}
+
+	first := run("golden")
+	second := run("golden")
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Expected identical UUIDs across seeded runs, got %v and %v", first[i], second[i])
+		}
+	}
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[interface{}]bool)
+	for _, v := range first {
+		if !uuidPattern.MatchString(v.(string)) {
+			t.Errorf("Expected v5 UUID, got %v", v)
+		}
+		if seen[v] {
+			t.Errorf("Expected unique UUIDs within a run, got %v twice", v)
+		}
+		seen[v] = true
+	}
+
+	if other := run("other"); other[0] == first[0] {
+		t.Errorf("Expected different seeds to produce different UUIDs, got %v", other[0])
+	}
+}
+
+func TestGenerateData_ExtendedEmail(t *testing.T) {
+	defer SetExtendedEmail(false)
+	SetExtendedEmail(true)
+
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "string", Format: "email"}}
+
+	displayNames := 0
+	for i := 0; i < 50; i++ {
+		result, err := GenerateData(schema)
+		if err != nil {
+			t.Fatalf("GenerateData error: %v", err)
+		}
+
+		addresses, err := mail.ParseAddressList(result.(string))
+		if err != nil {
+			t.Fatalf("Expected RFC 5322 address list, got %q: %v", result, err)
+		}
+		for _, address := range addresses {
+			if address.Name != "" {
+				displayNames++
+			}
+		}
+	}
+
+	if displayNames == 0 {
+		t.Error("Expected some generated emails to have display names")
+	}
+}
+
+func TestGenerateAdvancedData_GenerationErrorPath(t *testing.T) {
+	// Every optional property is generated
+	SetOptionalProbability(1)
+	defer SetOptionalProbability(DefaultOptionalProbability)
+
+	zipSchema := &openapi3.Schema{Type: "file"}
+	addressSchema := &openapi3.Schema{
+		Type: "object",
+		Properties: openapi3.Schemas{
+			"city": {Value: &openapi3.Schema{Type: "string"}},
+			"zip":  {Value: zipSchema},
+		},
+	}
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type: "object",
+		Properties: openapi3.Schemas{
+			"customer": {Value: &openapi3.Schema{
+				Type: "object",
+				Properties: openapi3.Schemas{
+					"addresses": {Value: &openapi3.Schema{
+						Type:  "array",
+						Items: &openapi3.SchemaRef{Value: addressSchema},
+					}},
+				},
+			}},
+		},
+	}}
+
+	_, err := GenerateAdvancedData(schema, "")
+	if err == nil {
+		t.Fatal("Expected an error for the unsupported schema type")
+	}
+
+	var genErr *GenerationError
+	if !errors.As(err, &genErr) {
+		t.Fatalf("Expected a *GenerationError, got %T: %v", err, err)
+	}
+	if genErr.Path != "customer.addresses[].zip" {
+		t.Errorf("Expected path customer.addresses[].zip, got %q", genErr.Path)
+	}
+	if genErr.Type != "file" {
+		t.Errorf("Expected type file, got %q", genErr.Type)
+	}
+	if !strings.Contains(err.Error(), "customer.addresses[].zip (file)") {
+		t.Errorf("Expected the error to name the field, got %q", err.Error())
+	}
+}
@@ -2,6 +2,8 @@ package generator
 
 import (
 	"fmt"
+	"math/rand"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -13,18 +15,56 @@ import (
 type AutoSeedConfig struct {
 	// Number of items to generate per resource
 	ItemsPerResource int
+	// Number of items to generate for specific resources, instead of
+	// ItemsPerResource
+	CountsByResource map[string]int
+	// Bounds of a random number of items to generate for specific
+	// resources, instead of ItemsPerResource
+	CountRanges map[string]CountRange
 	// Resources to include (empty means all)
 	IncludeResources []string
 	// Resources to exclude
 	ExcludeResources []string
+	// Relationship types keyed by resource and related resource
+	Relationships map[string]map[string]string
+	// How children are spread over their parents: FKDistributionEven
+	// (default), FKDistributionRandom or FKDistributionSkewed
+	FKDistribution string
+}
+
+// CountRange bounds the number of items generated for a resource
+type CountRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Foreign key distributions
+const (
+	// FKDistributionEven assigns parents in turn, so every parent gets the
+	// same number of children
+	FKDistributionEven = "even"
+	// FKDistributionRandom picks a parent uniformly at random for each child
+	FKDistributionRandom = "random"
+	// FKDistributionSkewed picks parents at random, favoring the first ones,
+	// so a few parents get many children and others get none
+	FKDistributionSkewed = "skewed"
+)
+
+// IsFKDistribution reports whether name is a known foreign key distribution
+func IsFKDistribution(name string) bool {
+	switch name {
+	case "", FKDistributionEven, FKDistributionRandom, FKDistributionSkewed:
+		return true
+	}
+	return false
 }
 
 // ResourceDependency represents a dependency between resources
 type ResourceDependency struct {
-	Resource       string
-	DependsOn      string
+	Resource        string
+	DependsOn       string
 	ForeignKeyField string
-	IsRequired     bool
+	IsRequired      bool
 }
 
 // AutoSeeder generates seed data respecting relationships
@@ -34,6 +74,7 @@ type AutoSeeder struct {
 	faker        faker.Faker
 	dependencies []ResourceDependency
 	resources    map[string]*openapi3.SchemaRef
+	examples     map[string][]map[string]interface{}
 	generated    map[string][]map[string]interface{}
 }
 
@@ -48,6 +89,7 @@ func NewAutoSeeder(spec *openapi3.T, config AutoSeedConfig) *AutoSeeder {
 		faker:     faker.New(),
 		generated: make(map[string][]map[string]interface{}),
 		resources: make(map[string]*openapi3.SchemaRef),
+		examples:  make(map[string][]map[string]interface{}),
 	}
 }
 
@@ -56,9 +98,17 @@ func (s *AutoSeeder) Generate() (map[string][]interface{}, error) {
 	// Extract resources from paths
 	s.extractResources()
 
+	// Collect documented examples to seed the first items with
+	s.extractExamples()
+
 	// Detect dependencies between resources
 	s.detectDependencies()
 
+	// Resources that require each other can't be generated in any order
+	if cycle := s.requiredCycle(); cycle != nil {
+		return nil, fmt.Errorf("circular required dependency: %s", strings.Join(cycle, " -> "))
+	}
+
 	// Sort resources by dependency order
 	sortedResources := s.topologicalSort()
 
@@ -137,6 +187,83 @@ func (s *AutoSeeder) extractResources() {
 	}
 }
 
+// extractExamples collects the named examples of each resource's request
+// bodies and responses, in path and example name order. Array examples of
+// list responses contribute each of their items.
+func (s *AutoSeeder) extractExamples() {
+	paths := s.spec.Paths.Map()
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	for _, path := range pathNames {
+		resourceName := extractResourceName(path)
+		if _, ok := s.resources[resourceName]; !ok {
+			continue
+		}
+
+		pathItem := paths[path]
+		var mediaTypes []*openapi3.MediaType
+		if pathItem.Post != nil && pathItem.Post.RequestBody != nil && pathItem.Post.RequestBody.Value != nil {
+			mediaTypes = append(mediaTypes, pathItem.Post.RequestBody.Value.Content.Get("application/json"))
+		}
+		if pathItem.Get != nil && pathItem.Get.Responses != nil {
+			if _, resp := firstSuccess(pathItem.Get.Responses); resp != nil {
+				mediaTypes = append(mediaTypes, resp.Content.Get("application/json"))
+			}
+		}
+
+		for _, mt := range mediaTypes {
+			if mt == nil {
+				continue
+			}
+			names := make([]string, 0, len(mt.Examples))
+			for name := range mt.Examples {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				example := mt.Examples[name]
+				if example == nil || example.Value == nil {
+					continue
+				}
+				s.addExample(resourceName, example.Value.Value)
+			}
+		}
+	}
+}
+
+// addExample records an object example, or each object in an array example.
+// Examples repeated across operations are recorded once.
+func (s *AutoSeeder) addExample(resourceName string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, existing := range s.examples[resourceName] {
+			if reflect.DeepEqual(existing, v) {
+				return
+			}
+		}
+		s.examples[resourceName] = append(s.examples[resourceName], v)
+	case []interface{}:
+		for _, item := range v {
+			s.addExample(resourceName, item)
+		}
+	}
+}
+
+// firstSuccess returns the lowest 2xx response
+func firstSuccess(responses *openapi3.Responses) (int, *openapi3.Response) {
+	for code := 200; code < 300; code++ {
+		if resp := responses.Status(code); resp != nil && resp.Value != nil {
+			return code, resp.Value
+		}
+	}
+	return 0, nil
+}
+
 // detectDependencies finds foreign key relationships between resources
 func (s *AutoSeeder) detectDependencies() {
 	s.dependencies = nil
@@ -165,7 +292,7 @@ func (s *AutoSeeder) detectForeignKey(resourceName, propName string, propSchema
 	if strings.HasSuffix(propName, "_id") {
 		relatedResource := strings.TrimSuffix(propName, "_id")
 		// Convert to plural form
-		relatedResourcePlural := pluralize(relatedResource)
+		relatedResourcePlural := Pluralize(relatedResource)
 
 		// Check if this resource exists
 		if _, exists := s.resources[relatedResourcePlural]; exists {
@@ -189,7 +316,7 @@ func (s *AutoSeeder) detectForeignKey(resourceName, propName string, propSchema
 	// Check for schema references
 	if propSchema.Ref != "" {
 		refName := getSchemaRefName(propSchema.Ref)
-		relatedResource := pluralize(strings.ToLower(refName))
+		relatedResource := Pluralize(strings.ToLower(refName))
 		if _, exists := s.resources[relatedResource]; exists {
 			return &ResourceDependency{
 				Resource:        resourceName,
@@ -247,7 +374,9 @@ func (s *AutoSeeder) topologicalSort() []string {
 		sort.Strings(queue)
 	}
 
-	// Add any remaining resources (circular dependencies)
+	// Add any remaining resources (circular dependencies through optional
+	// references, which are left empty until their target is generated)
+	var remaining []string
 	for name := range s.resources {
 		found := false
 		for _, r := range result {
@@ -257,18 +386,78 @@ func (s *AutoSeeder) topologicalSort() []string {
 			}
 		}
 		if !found {
-			result = append(result, name)
+			remaining = append(remaining, name)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(result, remaining...)
+}
+
+// requiredCycle returns a cycle of required references, such as
+// [orders customers orders] when each requires the other, or nil if there
+// is none
+func (s *AutoSeeder) requiredCycle() []string {
+	requires := make(map[string][]string)
+	for _, dep := range s.dependencies {
+		if dep.IsRequired {
+			requires[dep.Resource] = append(requires[dep.Resource], dep.DependsOn)
+		}
+	}
+
+	names := make([]string, 0, len(requires))
+	for name, parents := range requires {
+		names = append(names, name)
+		sort.Strings(parents)
+	}
+	sort.Strings(names)
+
+	// Depth-first search, where a resource reached again while still on the
+	// path closes a cycle
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, parent := range requires[name] {
+			if cycle := visit(parent); cycle != nil {
+				return cycle
+			}
 		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
 	}
 
-	return result
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
 }
 
 // generateResourceItems generates items for a single resource
 func (s *AutoSeeder) generateResourceItems(resourceName string, schema *openapi3.SchemaRef) ([]map[string]interface{}, error) {
 	var items []map[string]interface{}
 
-	for i := 0; i < s.config.ItemsPerResource; i++ {
+	for i := 0; i < s.itemCount(resourceName); i++ {
 		item, err := s.generateItem(resourceName, schema, i)
 		if err != nil {
 			return nil, err
@@ -279,21 +468,44 @@ func (s *AutoSeeder) generateResourceItems(resourceName string, schema *openapi3
 	return items, nil
 }
 
+// itemCount returns the number of items to generate for a resource: a
+// random number within its range, its own count, or ItemsPerResource
+func (s *AutoSeeder) itemCount(resourceName string) int {
+	if r, ok := s.config.CountRanges[resourceName]; ok && r.Max >= r.Min {
+		return r.Min + rand.Intn(r.Max-r.Min+1)
+	}
+	if count, ok := s.config.CountsByResource[resourceName]; ok {
+		return count
+	}
+	return s.config.ItemsPerResource
+}
+
 // generateItem generates a single item with foreign key references
 func (s *AutoSeeder) generateItem(resourceName string, schema *openapi3.SchemaRef, index int) (map[string]interface{}, error) {
-	// Generate base data
-	data, err := GenerateDataWithFieldName(schema, "")
-	if err != nil {
-		return nil, err
-	}
+	var item map[string]interface{}
+
+	if examples := s.examples[resourceName]; index < len(examples) {
+		// The first items showcase the documented examples
+		item = make(map[string]interface{}, len(examples[index]))
+		for k, v := range examples[index] {
+			item[k] = v
+		}
+	} else {
+		// Generate base data
+		data, err := GenerateDataWithFieldName(schema, "")
+		if err != nil {
+			return nil, err
+		}
 
-	item, ok := data.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected object, got %T", data)
+		var ok bool
+		item, ok = data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected object, got %T", data)
+		}
 	}
 
 	// Generate a stable ID
-	item["id"] = fmt.Sprintf("%s-%03d", singularize(resourceName), index+1)
+	item["id"] = fmt.Sprintf("%s-%03d", Singularize(resourceName), index+1)
 
 	// Fill in foreign key references
 	for _, dep := range s.dependencies {
@@ -309,9 +521,7 @@ func (s *AutoSeeder) generateItem(resourceName string, schema *openapi3.SchemaRe
 			continue
 		}
 
-		// Assign a parent ID (distribute evenly or randomly)
-		parentIndex := index % len(parentItems)
-		parentItem := parentItems[parentIndex]
+		parentItem := parentItems[s.parentIndex(index, len(parentItems))]
 		if parentID, ok := parentItem["id"]; ok {
 			item[dep.ForeignKeyField] = parentID
 		}
@@ -320,6 +530,20 @@ func (s *AutoSeeder) generateItem(resourceName string, schema *openapi3.SchemaRe
 	return item, nil
 }
 
+// parentIndex picks the parent of the child at index among count parents,
+// following the configured distribution
+func (s *AutoSeeder) parentIndex(index, count int) int {
+	switch s.config.FKDistribution {
+	case FKDistributionRandom:
+		return rand.Intn(count)
+	case FKDistributionSkewed:
+		// Squaring a uniform value concentrates picks on low indexes
+		r := rand.Float64()
+		return int(r * r * float64(count))
+	}
+	return index % count
+}
+
 // shouldInclude checks if a resource should be included
 func (s *AutoSeeder) shouldInclude(resourceName string) bool {
 	// Check exclusions first
@@ -344,6 +568,27 @@ func (s *AutoSeeder) shouldInclude(resourceName string) bool {
 	return false
 }
 
+// Relations returns the configured relationships between generated resources,
+// keyed by resource and related resource. It must be called after Generate.
+func (s *AutoSeeder) Relations() map[string]map[string]string {
+	relations := make(map[string]map[string]string)
+	for resource, related := range s.config.Relationships {
+		if len(s.generated[resource]) == 0 {
+			continue
+		}
+		for relatedResource, relationType := range related {
+			if len(s.generated[relatedResource]) == 0 {
+				continue
+			}
+			if relations[resource] == nil {
+				relations[resource] = make(map[string]string)
+			}
+			relations[resource][relatedResource] = relationType
+		}
+	}
+	return relations
+}
+
 // GetDependencies returns detected dependencies
 func (s *AutoSeeder) GetDependencies() []ResourceDependency {
 	return s.dependencies
@@ -377,8 +622,8 @@ func getSchemaRefName(ref string) string {
 	return parts[len(parts)-1]
 }
 
-// pluralize converts a singular word to plural
-func pluralize(word string) string {
+// Pluralize converts a singular word to plural
+func Pluralize(word string) string {
 	if word == "" {
 		return ""
 	}
@@ -417,8 +662,8 @@ func pluralize(word string) string {
 	return word + "s"
 }
 
-// singularize converts a plural word to singular
-func singularize(word string) string {
+// Singularize converts a plural word to singular
+func Singularize(word string) string {
 	if word == "" {
 		return ""
 	}
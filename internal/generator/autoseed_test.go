@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -30,9 +32,9 @@ func TestPluralize(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := pluralize(tt.input)
+			result := Pluralize(tt.input)
 			if result != tt.expected {
-				t.Errorf("pluralize(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("Pluralize(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -62,9 +64,9 @@ func TestSingularize(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := singularize(tt.input)
+			result := Singularize(tt.input)
 			if result != tt.expected {
-				t.Errorf("singularize(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("Singularize(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -294,6 +296,159 @@ func TestAutoSeederForeignKeyValues(t *testing.T) {
 	}
 }
 
+func TestAutoSeederCountsByResource(t *testing.T) {
+	t.Run("Count", func(t *testing.T) {
+		seeder := NewAutoSeeder(createTestSpec(), AutoSeedConfig{
+			ItemsPerResource: 5,
+			CountsByResource: map[string]int{"pets": 50},
+		})
+		data, err := seeder.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if len(data["owners"]) != 5 {
+			t.Errorf("Expected 5 owners, got %d", len(data["owners"]))
+		}
+		if len(data["pets"]) != 50 {
+			t.Errorf("Expected 50 pets, got %d", len(data["pets"]))
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		counts := make(map[int]bool)
+		for i := 0; i < 20; i++ {
+			seeder := NewAutoSeeder(createTestSpec(), AutoSeedConfig{
+				ItemsPerResource: 5,
+				CountRanges:      map[string]CountRange{"pets": {Min: 10, Max: 12}},
+			})
+			data, err := seeder.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			count := len(data["pets"])
+			if count < 10 || count > 12 {
+				t.Fatalf("Expected between 10 and 12 pets, got %d", count)
+			}
+			counts[count] = true
+			if len(data["owners"]) != 5 {
+				t.Errorf("Expected 5 owners, got %d", len(data["owners"]))
+			}
+		}
+		if len(counts) < 2 {
+			t.Errorf("Expected the number of pets to vary, got %v", counts)
+		}
+	})
+}
+
+func TestAutoSeederForeignKeyDistribution(t *testing.T) {
+	// childCounts spreads 200 pets over 5 owners and counts the pets of
+	// each owner
+	childCounts := func(t *testing.T, distribution string) []int {
+		seeder := NewAutoSeeder(createTestSpec(), AutoSeedConfig{ItemsPerResource: 5, FKDistribution: distribution})
+		if _, err := seeder.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		counts := make([]int, 5)
+		for i := 0; i < 200; i++ {
+			pet, err := seeder.generateItem("pets", seeder.resources["pets"], i)
+			if err != nil {
+				t.Fatalf("generateItem() error = %v", err)
+			}
+			var n int
+			if _, err := fmt.Sscanf(fmt.Sprint(pet["owner_id"]), "owner-%03d", &n); err != nil || n < 1 || n > 5 {
+				t.Fatalf("Pet references unknown owner: %v", pet["owner_id"])
+			}
+			counts[n-1]++
+		}
+		return counts
+	}
+
+	t.Run("Even", func(t *testing.T) {
+		counts := childCounts(t, "")
+		for i, count := range counts {
+			if count != 40 {
+				t.Errorf("Expected 40 pets for owner %d, got %d", i+1, count)
+			}
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		counts := childCounts(t, FKDistributionRandom)
+		even := true
+		for _, count := range counts {
+			even = even && count == 40
+		}
+		if even {
+			t.Errorf("Expected an uneven spread, got %v", counts)
+		}
+	})
+
+	t.Run("Skewed", func(t *testing.T) {
+		counts := childCounts(t, FKDistributionSkewed)
+		if counts[0] <= 2*counts[4] {
+			t.Errorf("Expected the first owner to get many more pets than the last, got %v", counts)
+		}
+	})
+}
+
+func TestAutoSeederCircularDependencies(t *testing.T) {
+	// createSpec declares customers with an order_id and orders with
+	// a customer_id, each required or not
+	createSpec := func(orderRequired, customerRequired bool) *openapi3.T {
+		schema := func(fk string, required bool) *openapi3.Schema {
+			s := &openapi3.Schema{
+				Type: "object",
+				Properties: openapi3.Schemas{
+					"id": {Value: &openapi3.Schema{Type: "string"}},
+					fk:   {Value: &openapi3.Schema{Type: "string"}},
+				},
+			}
+			if required {
+				s.Required = []string{fk}
+			}
+			return s
+		}
+
+		paths := openapi3.NewPaths()
+		for path, s := range map[string]*openapi3.Schema{
+			"/customers": schema("order_id", orderRequired),
+			"/orders":    schema("customer_id", customerRequired),
+		} {
+			paths.Set(path, &openapi3.PathItem{
+				Post: &openapi3.Operation{
+					RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+						Content: openapi3.NewContentWithJSONSchema(s),
+					}},
+				},
+			})
+		}
+		return &openapi3.T{Paths: paths}
+	}
+
+	t.Run("Required both ways", func(t *testing.T) {
+		_, err := NewAutoSeeder(createSpec(true, true), AutoSeedConfig{}).Generate()
+		if err == nil {
+			t.Fatal("Expected an error for resources that require each other")
+		}
+		if !strings.Contains(err.Error(), "circular required dependency: customers -> orders -> customers") {
+			t.Errorf("Expected the error to list the cycle, got %v", err)
+		}
+	})
+
+	t.Run("Optional one way", func(t *testing.T) {
+		result, err := NewAutoSeeder(createSpec(false, true), AutoSeedConfig{}).Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		for _, order := range result["orders"] {
+			if order.(map[string]interface{})["customer_id"] == nil {
+				t.Errorf("Expected every order to reference a customer, got %v", order)
+			}
+		}
+	})
+}
+
 func createTestSpec() *openapi3.T {
 	spec := &openapi3.T{
 		OpenAPI: "3.0.0",
@@ -622,3 +777,54 @@ func createEcommerceSpec() *openapi3.T {
 
 	return spec
 }
+
+func TestAutoSeederCyclesExamples(t *testing.T) {
+	userSchema := &openapi3.Schema{
+		Type: "object",
+		Properties: openapi3.Schemas{
+			"id":   {Value: &openapi3.Schema{Type: "string"}},
+			"name": {Value: &openapi3.Schema{Type: "string"}},
+			"role": {Value: &openapi3.Schema{Type: "string"}},
+		},
+	}
+
+	paths := openapi3.NewPaths()
+	paths.Set("/users", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+				Content: openapi3.Content{
+					"application/json": &openapi3.MediaType{
+						Schema: &openapi3.SchemaRef{Value: userSchema},
+						Examples: openapi3.Examples{
+							"admin": {Value: openapi3.NewExample(map[string]interface{}{"name": "Ada", "role": "admin"})},
+							"guest": {Value: openapi3.NewExample(map[string]interface{}{"name": "Grace", "role": "guest"})},
+						},
+					},
+				},
+			}},
+		},
+	})
+
+	seeder := NewAutoSeeder(&openapi3.T{Paths: paths}, AutoSeedConfig{ItemsPerResource: 4})
+	result, err := seeder.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	users := result["users"]
+	if len(users) != 4 {
+		t.Fatalf("Expected 4 users, got %d", len(users))
+	}
+
+	roles := make(map[interface{}]bool)
+	for _, user := range users {
+		roles[user.(map[string]interface{})["role"]] = true
+	}
+	if !roles["admin"] || !roles["guest"] {
+		t.Errorf("Expected both examples in the collection, got %v", users)
+	}
+
+	if id := users[0].(map[string]interface{})["id"]; id != "user-001" {
+		t.Errorf("Expected example items to get stable IDs, got %v", id)
+	}
+}
@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/clock"
+)
+
+// clockHolder wraps the clock so atomic.Value always stores the same type
+type clockHolder struct {
+	clock clock.Clock
+}
+
+var currentClock atomic.Value
+
+func init() {
+	currentClock.Store(clockHolder{clock.Real{}})
+}
+
+// SetClock sets the clock that generated dates and timestamps are relative to.
+// The clock is shared by the whole process, so only the server that is
+// serving requests sets it.
+func SetClock(c clock.Clock) {
+	currentClock.Store(clockHolder{c})
+}
+
+func now() time.Time {
+	return currentClock.Load().(clockHolder).clock.Now()
+}
@@ -2,9 +2,10 @@ package generator
 
 import (
 	"fmt"
-	"math/rand"
+	"math"
 	"time"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jaswdr/faker"
 )
@@ -16,6 +17,11 @@ func GenerateData(schema *openapi3.SchemaRef) (interface{}, error) {
 
 // GenerateDataWithFieldName generates data with semantic field detection.
 func GenerateDataWithFieldName(schema *openapi3.SchemaRef, fieldName string) (interface{}, error) {
+	return generateData(schema, fieldName, nil)
+}
+
+// generateData generates data for a field nested in parents
+func generateData(schema *openapi3.SchemaRef, fieldName string, parents nesting) (interface{}, error) {
 	if schema == nil || schema.Value == nil {
 		return nil, fmt.Errorf("schema is nil")
 	}
@@ -26,23 +32,35 @@ func GenerateDataWithFieldName(schema *openapi3.SchemaRef, fieldName string) (in
 		return s.Example, nil
 	}
 
+	// Stop nesting self-referential schemas
+	if value, truncated := truncate(s, parents); truncated {
+		return value, nil
+	}
+
 	// Handle oneOf
 	if len(s.OneOf) > 0 {
-		return GenerateFromOneOf(s.OneOf)
+		return generateFromOneOf(s.OneOf, parents)
 	}
 
 	// Handle anyOf
 	if len(s.AnyOf) > 0 {
-		return GenerateFromAnyOf(s.AnyOf)
+		return generateFromAnyOf(s.AnyOf, parents)
 	}
 
 	// Handle allOf
 	if len(s.AllOf) > 0 {
-		return GenerateFromAllOf(s.AllOf)
+		return generateFromAllOf(s.AllOf, parents)
 	}
 
 	if len(s.Enum) > 0 {
-		return s.Enum[rand.Intn(len(s.Enum))], nil
+		return pickEnum(fieldName, s.Enum), nil
+	}
+
+	// Custom mappings apply to fields of any type
+	if fieldName != "" {
+		if value, ok := generateCustomSemantic(fieldName, s); ok {
+			return value, nil
+		}
 	}
 
 	// Try semantic detection for strings
@@ -63,13 +81,13 @@ func GenerateDataWithFieldName(schema *openapi3.SchemaRef, fieldName string) (in
 	case "boolean":
 		return faker.New().Bool(), nil
 	case "array":
-		data, err := generateArray(s)
+		data, err := generateArray(s, parents)
 		if err != nil {
 			return nil, err
 		}
 		return data, nil
 	case "object":
-		data, err := generateObject(s)
+		data, err := generateObject(s, parents)
 		if err != nil {
 			return nil, err
 		}
@@ -117,8 +135,12 @@ func GenerateExample(schema *openapi3.SchemaRef) (interface{}, error) {
 func generateString(schema *openapi3.Schema) string {
 	f := faker.New()
 
-	// Try pattern-based generation first
-	if schema.Pattern != "" {
+	// Try pattern-based generation first, keeping to the format if one is set
+	if schema.Pattern != "" && schema.Format != "" {
+		if generated, err := generateFromPatternAndFormat(schema); err == nil {
+			return generated
+		}
+	} else if schema.Pattern != "" {
 		if generated, err := GenerateFromPattern(schema.Pattern); err == nil {
 			return generated
 		}
@@ -126,17 +148,17 @@ func generateString(schema *openapi3.Schema) string {
 
 	switch schema.Format {
 	case "email":
-		return f.Internet().Email()
+		return generateEmail(f)
 	case "uuid":
-		return f.UUID().V4()
+		return newUUID(f, "uuid")
 	case "uri":
 		return f.Internet().URL()
 	case "date-time":
-		return f.Time().Time(time.Now()).Format(time.RFC3339)
+		return f.Time().Time(now()).Format(time.RFC3339)
 	case "date":
-		return f.Time().Time(time.Now()).Format("2006-01-02")
+		return f.Time().Time(now()).Format("2006-01-02")
 	case "time":
-		return f.Time().Time(time.Now()).Format("15:04:05")
+		return f.Time().Time(now()).Format("15:04:05")
 	default:
 		return f.Lorem().Word()
 	}
@@ -145,29 +167,95 @@ func generateString(schema *openapi3.Schema) string {
 func generateNumber(schema *openapi3.Schema) interface{} {
 	f := faker.New()
 	if schema.Type == "integer" {
-		min := int64(0)
-		max := int64(100)
-		if schema.Min != nil {
-			min = int64(*schema.Min)
+		return generateInteger(f, schema)
+	} else { // number (float)
+		return generateFloat(f, schema)
+	}
+}
+
+// generateFloat generates a number with two decimals within the schema's
+// minimum and maximum, honoring exclusive bounds
+func generateFloat(f faker.Faker, schema *openapi3.Schema) float64 {
+	min := 0.0
+	max := 100.0
+	if schema.Min != nil {
+		min = *schema.Min
+		if schema.Max == nil && min > max {
+			max = min + 100
 		}
-		if schema.Max != nil {
-			max = int64(*schema.Max)
+	}
+	if schema.Max != nil {
+		max = *schema.Max
+	}
+
+	// Work in hundredths, since faker only generates integers in a range
+	lo := int64(math.Ceil(min * 100))
+	hi := int64(math.Floor(max * 100))
+	if schema.ExclusiveMin && float64(lo) == min*100 {
+		lo++
+	}
+	if schema.ExclusiveMax && float64(hi) == max*100 {
+		hi--
+	}
+	if lo > hi {
+		return min
+	}
+	return float64(f.Int64Between(lo, hi)) / 100
+}
+
+// generateInteger generates an integer within the schema's minimum and maximum,
+// honoring exclusive bounds, that is a multiple of its multipleOf, if set. When the range holds no
+// multiple the multipleOf constraint is dropped rather than the range.
+func generateInteger(f faker.Faker, schema *openapi3.Schema) int64 {
+	min := int64(0)
+	max := int64(100)
+	if schema.Min != nil {
+		min = int64(math.Ceil(*schema.Min))
+		if schema.ExclusiveMin && float64(min) == *schema.Min {
+			min++
 		}
-		return f.Int64Between(min, max)
-	} else { // number (float)
-		min := 0
-		max := 100
-		if schema.Min != nil {
-			min = int(*schema.Min)
+		if schema.Max == nil && min > max {
+			max = min + 100
+		}
+	}
+	if schema.Max != nil {
+		max = int64(math.Floor(*schema.Max))
+		if schema.ExclusiveMax && float64(max) == *schema.Max {
+			max--
 		}
-		if schema.Max != nil {
-			max = int(*schema.Max)
+	}
+
+	if schema.MultipleOf != nil {
+		if value, ok := randomMultiple(f, min, max, int64(*schema.MultipleOf)); ok {
+			return value
 		}
-		return f.Float64(2, min, max)
 	}
+	return f.Int64Between(min, max)
 }
 
-func generateArray(schema *openapi3.Schema) ([]interface{}, error) {
+// randomMultiple returns a random multiple of factor within [min, max], or
+// false if the range doesn't contain one
+func randomMultiple(f faker.Faker, min, max, factor int64) (int64, bool) {
+	if factor < 0 {
+		factor = -factor
+	}
+	if factor == 0 || min > max {
+		return 0, false
+	}
+
+	first := int64(math.Ceil(float64(min) / float64(factor)))
+	last := int64(math.Floor(float64(max) / float64(factor)))
+	if first > last {
+		return 0, false
+	}
+	return f.Int64Between(first, last) * factor, true
+}
+
+func generateArray(schema *openapi3.Schema, parents nesting) ([]interface{}, error) {
+	if tuple := openapi.ArrayTuple(schema); tuple != nil {
+		return generateTuple(schema, tuple, parents)
+	}
+
 	f := faker.New()
 	minItems := int(schema.MinItems)
 	maxItems := 0
@@ -184,7 +272,7 @@ func generateArray(schema *openapi3.Schema) ([]interface{}, error) {
 	count := f.IntBetween(minItems, maxItems)
 	arr := make([]interface{}, count)
 	for i := 0; i < count; i++ {
-		item, err := GenerateData(schema.Items)
+		item, err := generateData(schema.Items, "", parents.enter(schema))
 		if err != nil {
 			return nil, err
 		}
@@ -193,13 +281,43 @@ func generateArray(schema *openapi3.Schema) ([]interface{}, error) {
 	return arr, nil
 }
 
-func generateObject(schema *openapi3.Schema) (map[string]interface{}, error) {
+// generateTuple generates one item per tuple position, padding with additional
+// items only when minItems asks for more and the tuple isn't closed
+func generateTuple(schema *openapi3.Schema, tuple *openapi.Tuple, parents nesting) ([]interface{}, error) {
+	arr := make([]interface{}, 0, len(tuple.Items))
+	for _, itemSchema := range tuple.Items {
+		item, err := generateData(itemSchema, "", parents.enter(schema))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, item)
+	}
+
+	if tuple.Closed || tuple.Additional == nil {
+		return arr, nil
+	}
+
+	for len(arr) < int(schema.MinItems) {
+		item, err := generateData(tuple.Additional, "", parents.enter(schema))
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, item)
+	}
+	return arr, nil
+}
+
+func generateObject(schema *openapi3.Schema, parents nesting) (map[string]interface{}, error) {
 	obj := make(map[string]interface{})
 	f := faker.New()
 
 	// Handle properties with semantic field detection
 	for name, propSchema := range schema.Properties {
-		data, err := GenerateDataWithFieldName(propSchema, name)
+		if isWriteOnly(propSchema) {
+			continue
+		}
+
+		data, err := generateData(propSchema, name, parents.enter(schema))
 		if err != nil {
 			return nil, err
 		}
@@ -211,7 +329,7 @@ func generateObject(schema *openapi3.Schema) (map[string]interface{}, error) {
 		if allOfSchema.Value == nil {
 			continue
 		}
-		allOfObj, err := generateObject(allOfSchema.Value)
+		allOfObj, err := generateObject(allOfSchema.Value, parents)
 		if err != nil {
 			return nil, err
 		}
@@ -232,7 +350,7 @@ func generateObject(schema *openapi3.Schema) (map[string]interface{}, error) {
 	if schema.AdditionalProperties.Has != nil && *schema.AdditionalProperties.Has {
 		for i := 0; i < f.IntBetween(1, 3); i++ {
 			key := f.Lorem().Word()
-			val, err := GenerateDataWithFieldName(schema.AdditionalProperties.Schema, key)
+			val, err := generateData(schema.AdditionalProperties.Schema, key, parents.enter(schema))
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"sync/atomic"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DefaultMaxDepth is how often a recursive schema nests in itself in
+// generated data when no depth is configured
+const DefaultMaxDepth = 5
+
+// maxDepth holds the configured recursion depth, or 0 for DefaultMaxDepth
+var maxDepth atomic.Int64
+
+// SetMaxDepth sets how often a recursive schema nests in itself in generated
+// data. A depth of 0 or less selects DefaultMaxDepth.
+func SetMaxDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	maxDepth.Store(int64(depth))
+}
+
+// nesting is the chain of object and array schemas a value is generated in,
+// outermost first
+type nesting []*openapi3.Schema
+
+// enter returns the nesting of the values inside schema
+func (n nesting) enter(schema *openapi3.Schema) nesting {
+	return append(n[:len(n):len(n)], schema)
+}
+
+// recursion counts how often schema already encloses the value
+func (n nesting) recursion(schema *openapi3.Schema) int {
+	count := 0
+	for _, parent := range n {
+		if parent == schema {
+			count++
+		}
+	}
+	return count
+}
+
+// truncate returns the value generated for an object or array whose schema
+// already encloses it the maximum number of times: null when the schema is
+// nullable, or else an empty object or array. This ends the recursion of
+// self-referential schemas, such as a category with a parent category, while
+// schemas that merely nest deeply are generated in full.
+func truncate(schema *openapi3.Schema, parents nesting) (interface{}, bool) {
+	if schema.Type != "object" && schema.Type != "array" {
+		return nil, false
+	}
+	limit := int(maxDepth.Load())
+	if limit <= 0 {
+		limit = DefaultMaxDepth
+	}
+	if parents.recursion(schema) < limit {
+		return nil, false
+	}
+
+	if schema.Nullable {
+		return nil, true
+	}
+	if schema.Type == "array" {
+		return []interface{}{}, true
+	}
+	return map[string]interface{}{}, true
+}
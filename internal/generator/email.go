@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"math/rand"
+	"net/mail"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jaswdr/faker"
+)
+
+// extendedEmail enables RFC 5322 display-name addresses and address lists
+// for generated emails
+var extendedEmail atomic.Bool
+
+// SetExtendedEmail toggles extended email generation. When enabled, generated
+// emails may be bare addresses, display-name forms such as
+// "Jane Doe <jane@example.com>" or comma-separated address lists.
+func SetExtendedEmail(enabled bool) {
+	extendedEmail.Store(enabled)
+}
+
+// generateEmail generates an email in the configured mode
+func generateEmail(f faker.Faker) string {
+	if !extendedEmail.Load() {
+		return f.Internet().Email()
+	}
+
+	// Fakers created within the same second share a seed, so the mode is
+	// picked from the global source to keep it varying between calls
+	switch rand.Intn(3) {
+	case 0:
+		return f.Internet().Email()
+	case 1:
+		return displayNameAddress(f)
+	default:
+		addresses := make([]string, 2+rand.Intn(2))
+		for i := range addresses {
+			addresses[i] = displayNameAddress(f)
+		}
+		return strings.Join(addresses, ", ")
+	}
+}
+
+// displayNameAddress generates an address with a display name
+func displayNameAddress(f faker.Faker) string {
+	address := mail.Address{Name: f.Person().Name(), Address: f.Internet().Email()}
+	return address.String()
+}
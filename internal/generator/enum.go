@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// enumWeights holds the configured weights of enum values, by field name
+var enumWeights = struct {
+	mu      sync.RWMutex
+	byField map[string]map[string]float64
+}{}
+
+// SetEnumWeights sets the relative weights of enum values per field name,
+// such as {"status": {"active": 90, "inactive": 10}}. Values are matched by
+// their string form. Values without a weight are never picked, unless none
+// of a field's enum values has a weight, in which case they are picked
+// uniformly. A nil map removes every weight.
+func SetEnumWeights(weights map[string]map[string]float64) {
+	enumWeights.mu.Lock()
+	defer enumWeights.mu.Unlock()
+	enumWeights.byField = weights
+}
+
+// pickEnum picks one of the values of an enum, using the weights configured
+// for fieldName if there are any
+func pickEnum(fieldName string, values []interface{}) interface{} {
+	enumWeights.mu.RLock()
+	weights := enumWeights.byField[fieldName]
+	enumWeights.mu.RUnlock()
+
+	if len(weights) > 0 {
+		total := 0.0
+		cumulative := make([]float64, len(values))
+		for i, value := range values {
+			if weight := weights[fmt.Sprintf("%v", value)]; weight > 0 {
+				total += weight
+			}
+			cumulative[i] = total
+		}
+
+		if total > 0 {
+			target := rand.Float64() * total
+			for i, bound := range cumulative {
+				if target < bound {
+					return values[i]
+				}
+			}
+		}
+	}
+
+	return values[rand.Intn(len(values))]
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GenerationError reports where in a schema data generation failed
+type GenerationError struct {
+	// Path of the field that failed, such as customer.addresses[].zip; empty for the root value
+	Path string
+
+	// Type of the schema that failed, if declared
+	Type string
+
+	// Cause of the failure
+	Err error
+}
+
+func (e *GenerationError) Error() string {
+	field := e.Path
+	if field == "" {
+		field = "value"
+	}
+	if e.Type != "" {
+		field = fmt.Sprintf("%s (%s)", field, e.Type)
+	}
+	return fmt.Sprintf("failed to generate %s: %v", field, e.Err)
+}
+
+func (e *GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// wrapGenerationError attaches the field path and schema type to err unless a
+// deeper field already did
+func wrapGenerationError(err error, path, schemaType string) error {
+	var genErr *GenerationError
+	if err == nil || errors.As(err, &genErr) {
+		return err
+	}
+	return &GenerationError{Path: path, Type: schemaType, Err: err}
+}
+
+// joinPath appends a property name to a field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/jaswdr/faker"
 )
@@ -181,13 +182,13 @@ func (g *Generator) generateByType(schema *openapi3.Schema, context *GenerationC
 func (g *Generator) generateString(schema *openapi3.Schema) (interface{}, error) {
 	switch schema.Format {
 	case "email":
-		return g.faker.Internet().Email(), nil
+		return generateEmail(g.faker), nil
 	case "date-time":
-		return g.faker.Time().Time(time.Now()).Format(time.RFC3339), nil
+		return g.faker.Time().Time(now()).Format(time.RFC3339), nil
 	case "date":
-		return g.faker.Time().Time(time.Now()).Format("2006-01-02"), nil
+		return g.faker.Time().Time(now()).Format("2006-01-02"), nil
 	case "uuid":
-		return g.faker.UUID().V4(), nil
+		return newUUID(g.faker, "uuid"), nil
 	case "uri":
 		return g.faker.Internet().URL(), nil
 	case "hostname":
@@ -215,58 +216,53 @@ func (g *Generator) generateString(schema *openapi3.Schema) (interface{}, error)
 
 func (g *Generator) generateNumber(schema *openapi3.Schema) (interface{}, error) {
 	if schema.Type == "integer" {
-		min := int64(0)
-		max := int64(100)
-		if schema.Min != nil {
-			min = int64(*schema.Min)
-		}
-		if schema.Max != nil {
-			max = int64(*schema.Max)
-		}
-		return g.faker.Int64Between(min, max), nil
+		return generateInteger(g.faker, schema), nil
 	}
 
-	min := 0.0
-	max := 100.0
-	if schema.Min != nil {
-		min = *schema.Min
-	}
-	if schema.Max != nil {
-		max = *schema.Max
-	}
-	// Use Int64Between and convert to float64 since faker doesn't have Float64Between
-	intMin := int64(min * 100)
-	intMax := int64(max * 100)
-	randomInt := g.faker.Int64Between(intMin, intMax)
-	return float64(randomInt) / 100.0, nil
+	return generateFloat(g.faker, schema), nil
 }
 
 func (g *Generator) generateArray(schema *openapi3.Schema, context *GenerationContext) (interface{}, error) {
-	if schema.Items == nil {
+	tuple := openapi.ArrayTuple(schema)
+	if schema.Items == nil && tuple == nil {
 		return nil, fmt.Errorf("array items schema is required")
 	}
 
-	minItems := 1
-	maxItems := 5
+	var count int
+	if tuple != nil {
+		// Tuples get exactly one item per position, plus extras only when
+		// minItems requires them and additional items are allowed
+		count = len(tuple.Items)
+		if !tuple.Closed && tuple.Additional != nil && int(schema.MinItems) > count {
+			count = int(schema.MinItems)
+		}
+	} else {
+		minItems := 1
+		maxItems := 5
 
-	if schema.MinItems > 0 {
-		minItems = int(schema.MinItems)
-	}
-	if schema.MaxItems != nil && *schema.MaxItems > 0 {
-		maxItems = int(*schema.MaxItems)
-	}
+		if schema.MinItems > 0 {
+			minItems = int(schema.MinItems)
+		}
+		if schema.MaxItems != nil && *schema.MaxItems > 0 {
+			maxItems = int(*schema.MaxItems)
+		}
 
-	count := g.faker.IntBetween(minItems, maxItems)
+		count = g.faker.IntBetween(minItems, maxItems)
+	}
 	items := make([]interface{}, count)
 
 	for i := 0; i < count; i++ {
+		itemSchema := schema.Items
+		if tuple != nil {
+			itemSchema = tuple.ItemSchema(i)
+		}
 		itemContext := &GenerationContext{
 			Path:          fmt.Sprintf("%s[%d]", context.Path, i),
 			ParentResource: context.ParentResource,
 			ParentID:      context.ParentID,
 			Cache:         context.Cache,
 		}
-		item, err := g.Generate(schema.Items.Value, itemContext)
+		item, err := g.Generate(itemSchema.Value, itemContext)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate array item %d: %w", i, err)
 		}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// DefaultNullProbability is the chance of generating null for a nullable
+// value when no probability is configured
+const DefaultNullProbability = 0.1
+
+// nullProbability holds the bits of the chance of generating null for a
+// nullable value
+var nullProbability atomic.Uint64
+
+func init() {
+	nullProbability.Store(math.Float64bits(DefaultNullProbability))
+}
+
+// SetNullProbability sets the chance, between 0 and 1, that a value whose
+// schema is nullable is generated as null. A probability of 0 turns nulls
+// off.
+func SetNullProbability(probability float64) {
+	nullProbability.Store(math.Float64bits(math.Max(0, math.Min(probability, 1))))
+}
+
+// generateNull decides whether to generate null for a nullable value
+func generateNull() bool {
+	probability := math.Float64frombits(nullProbability.Load())
+	return probability > 0 && rand.Float64() < probability
+}
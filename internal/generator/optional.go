@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// DefaultOptionalProbability is the chance of generating an optional property
+// when no probability is configured
+const DefaultOptionalProbability = 0.8
+
+// optionalProbability holds the bits of the chance of generating a property
+// that isn't required
+var optionalProbability atomic.Uint64
+
+func init() {
+	optionalProbability.Store(math.Float64bits(DefaultOptionalProbability))
+}
+
+// SetOptionalProbability sets the chance, between 0 and 1, that a generated
+// object includes each property its schema doesn't require. Required
+// properties are always included, and a probability of 0 leaves out every
+// other property.
+func SetOptionalProbability(probability float64) {
+	optionalProbability.Store(math.Float64bits(math.Max(0, math.Min(probability, 1))))
+}
+
+// includeOptional decides whether to generate an optional property
+func includeOptional() bool {
+	probability := math.Float64frombits(optionalProbability.Load())
+	return probability >= 1 || rand.Float64() < probability
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SemanticGenerator generates the value of a field matched by a custom
+// semantic mapping
+type SemanticGenerator func(schema *openapi3.Schema) interface{}
+
+// semanticMapping pairs a field name pattern with its generator
+type semanticMapping struct {
+	pattern  *regexp.Regexp
+	generate SemanticGenerator
+}
+
+// customSemantics holds the registered mappings, in registration order
+var customSemantics = struct {
+	mu       sync.RWMutex
+	mappings []semanticMapping
+}{}
+
+// RegisterSemantic adds a mapping from field names matching pattern to a
+// generator. Registered mappings are consulted in order, before the
+// built-in semantic types, for fields of any type.
+func RegisterSemantic(pattern string, generate SemanticGenerator) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid field pattern %s: %w", pattern, err)
+	}
+
+	customSemantics.mu.Lock()
+	defer customSemantics.mu.Unlock()
+	customSemantics.mappings = append(customSemantics.mappings, semanticMapping{pattern: re, generate: generate})
+	return nil
+}
+
+// ResetSemantics removes every registered mapping
+func ResetSemantics() {
+	customSemantics.mu.Lock()
+	defer customSemantics.mu.Unlock()
+	customSemantics.mappings = nil
+}
+
+// SemanticGeneratorFor builds the generator of a configured mapping, which
+// generates values matching a regular expression, values of a built-in
+// semantic type such as credit_card, or one of a list of values. Exactly one
+// of them must be given.
+func SemanticGeneratorFor(pattern, typeName string, values []string) (SemanticGenerator, error) {
+	switch {
+	case pattern != "" && typeName == "" && len(values) == 0:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid value pattern %s: %w", pattern, err)
+		}
+		return func(*openapi3.Schema) interface{} {
+			value, err := GenerateFromPattern(pattern)
+			if err != nil {
+				return nil
+			}
+			return value
+		}, nil
+
+	case typeName != "" && pattern == "" && len(values) == 0:
+		semanticType, ok := SemanticTypeByName(typeName)
+		if !ok {
+			return nil, fmt.Errorf("unknown semantic type: %s", typeName)
+		}
+		return func(schema *openapi3.Schema) interface{} {
+			return GenerateBySemanticType(semanticType, schema)
+		}, nil
+
+	case len(values) > 0 && pattern == "" && typeName == "":
+		return func(*openapi3.Schema) interface{} {
+			return values[rand.Intn(len(values))]
+		}, nil
+	}
+	return nil, fmt.Errorf("exactly one of pattern, type or values is required")
+}
+
+// generateCustomSemantic generates a value with the first registered mapping
+// matching the field name. It reports false when no mapping matches or the
+// matching generator returns nil.
+func generateCustomSemantic(fieldName string, schema *openapi3.Schema) (interface{}, bool) {
+	customSemantics.mu.RLock()
+	defer customSemantics.mu.RUnlock()
+
+	for _, mapping := range customSemantics.mappings {
+		if mapping.pattern.MatchString(fieldName) {
+			value := mapping.generate(schema)
+			return value, value != nil
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jaswdr/faker"
+)
+
+// uuidState holds the deterministic UUID settings
+var uuidState = struct {
+	mu            sync.Mutex
+	deterministic bool
+	namespace     uuid.UUID
+	counters      map[string]uint64
+}{}
+
+// SetDeterministicUUIDs makes generated UUIDs reproducible. UUIDs are v5
+// UUIDs derived from a namespace based on seed and a per-scope counter, so
+// the same seed produces the same sequence of UUIDs across runs. Calling it
+// again restarts the sequence.
+func SetDeterministicUUIDs(seed string) {
+	uuidState.mu.Lock()
+	defer uuidState.mu.Unlock()
+
+	uuidState.deterministic = true
+	// The namespace is derived from the seed within the RFC 4122 URL namespace
+	uuidState.namespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("meridian:"+seed))
+	uuidState.counters = make(map[string]uint64)
+}
+
+// DisableDeterministicUUIDs restores random v4 UUIDs
+func DisableDeterministicUUIDs() {
+	uuidState.mu.Lock()
+	defer uuidState.mu.Unlock()
+
+	uuidState.deterministic = false
+	uuidState.counters = nil
+}
+
+// newUUID returns a random v4 UUID, or the next deterministic UUID for scope
+// when deterministic mode is on
+func newUUID(f faker.Faker, scope string) string {
+	uuidState.mu.Lock()
+	defer uuidState.mu.Unlock()
+
+	if !uuidState.deterministic {
+		return f.UUID().V4()
+	}
+
+	uuidState.counters[scope]++
+	name := fmt.Sprintf("%s:%d", scope, uuidState.counters[scope])
+	return uuid.NewSHA1(uuidState.namespace, []byte(name)).String()
+}
@@ -10,7 +10,9 @@ import (
 	"testing"
 
 	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/felipevolpatto/meridian/internal/server"
+	"github.com/felipevolpatto/meridian/internal/validation"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -302,3 +304,111 @@ func TestCORSIntegration(t *testing.T) {
 		assert.Contains(t, w.Header().Get("Access-Control-Allow-Origin"), "http://example.com")
 	})
 }
+
+func TestSplitSpecIntegration(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "schemas"), 0755))
+
+	files := map[string]string{
+		"openapi.yaml": `
+openapi: 3.0.0
+info:
+  title: Split API
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: './schemas/user.yaml'
+      responses:
+        '201':
+          description: User created
+          content:
+            application/json:
+              schema:
+                $ref: './schemas/user.yaml'
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: User details
+          content:
+            application/json:
+              schema:
+                $ref: './schemas/user.yaml'
+`,
+		"schemas/user.yaml": `
+type: object
+required: [id, name, address]
+properties:
+  id:
+    type: string
+  name:
+    type: string
+    minLength: 1
+  address:
+    $ref: './address.yaml'
+`,
+		"schemas/address.yaml": `
+type: object
+required: [city]
+properties:
+  city:
+    type: string
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	spec, err := openapi.ParseFile(filepath.Join(tmpDir, "openapi.yaml"))
+	require.NoError(t, err)
+
+	validator := validation.NewRequestValidator(spec)
+
+	t.Run("validation sees external schemas", func(t *testing.T) {
+		errs := validator.ValidateRequest(http.MethodPost, "/users", http.Header{"Content-Type": []string{"application/json"}}, nil,
+			[]byte(`{"id": "1", "name": "John", "address": {}}`)).Errors()
+		assert.NotEmpty(t, errs)
+
+		errs = validator.ValidateRequest(http.MethodPost, "/users", http.Header{"Content-Type": []string{"application/json"}}, nil,
+			[]byte(`{"id": "1", "name": "John", "address": {"city": "Lisbon"}}`)).Errors()
+		assert.Empty(t, errs)
+	})
+
+	t.Run("generation sees external schemas", func(t *testing.T) {
+		cfg := &config.Config{
+			OpenAPI: filepath.Join(tmpDir, "openapi.yaml"),
+			State:   config.StateConfig{Persistence: filepath.Join(tmpDir, "test.db")},
+			Behavior: config.BehaviorConfig{
+				GenerateOnMiss: true,
+			},
+		}
+		handler := server.NewServer(spec, cfg)
+
+		req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var user map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+		address, ok := user["address"].(map[string]interface{})
+		require.True(t, ok, "address should be generated from the external schema")
+		assert.NotEmpty(t, address["city"])
+
+		errs := validator.ValidateResponse(http.MethodGet, "/users/u1", w.Code, w.Header(), w.Body.Bytes()).Errors()
+		assert.Empty(t, errs)
+	})
+}
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Conditional describes the JSON Schema if/then/else keywords. OpenAPI 3.0
+// doesn't model them, so like prefixItems they're read from the schema's
+// extensions.
+type Conditional struct {
+	// If is the schema that selects which branch applies
+	If *openapi3.SchemaRef
+
+	// Then applies when the value matches If, or nil if unconstrained
+	Then *openapi3.SchemaRef
+
+	// Else applies when the value doesn't match If, or nil if unconstrained
+	Else *openapi3.SchemaRef
+}
+
+// SchemaConditional returns the if/then/else definition of a schema, or nil
+// if the schema doesn't declare if
+func SchemaConditional(schema *openapi3.Schema) *Conditional {
+	if schema == nil {
+		return nil
+	}
+
+	raw, ok := schema.Extensions["if"]
+	if !ok {
+		return nil
+	}
+
+	cond := &Conditional{If: toSchemaRef(decodeExtension(raw))}
+	if cond.If == nil {
+		return nil
+	}
+	if raw, ok := schema.Extensions["then"]; ok {
+		cond.Then = toSchemaRef(decodeExtension(raw))
+	}
+	if raw, ok := schema.Extensions["else"]; ok {
+		cond.Else = toSchemaRef(decodeExtension(raw))
+	}
+
+	return cond
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaConditional(t *testing.T) {
+	t.Run("SchemaConditional_FromSpec", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Conditional API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Payment:
+      type: object
+      if:
+        properties:
+          paymentType:
+            enum: [card]
+      then:
+        required: [cardNumber]
+`))
+		require.NoError(t, err)
+
+		cond := SchemaConditional(spec.Components.Schemas["Payment"].Value)
+		require.NotNil(t, cond)
+		assert.Equal(t, []interface{}{"card"}, cond.If.Value.Properties["paymentType"].Value.Enum)
+		require.NotNil(t, cond.Then)
+		assert.Equal(t, []string{"cardNumber"}, cond.Then.Value.Required)
+		assert.Nil(t, cond.Else)
+	})
+
+	t.Run("SchemaConditional_NoIf", func(t *testing.T) {
+		assert.Nil(t, SchemaConditional(openapi3.NewObjectSchema()))
+	})
+}
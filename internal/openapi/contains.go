@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Contains describes the JSON Schema contains keyword with its minContains
+// and maxContains bounds. Like prefixItems, they're read from the schema's
+// extensions.
+type Contains struct {
+	// Schema is the schema that counted items must match
+	Schema *openapi3.SchemaRef
+
+	// Min is the least number of matching items, 1 unless minContains is set
+	Min int
+
+	// Max is the most matching items allowed, or nil if unbounded
+	Max *int
+}
+
+// ArrayContains returns the contains definition of an array schema, or nil if
+// the schema doesn't declare contains
+func ArrayContains(schema *openapi3.Schema) *Contains {
+	if schema == nil {
+		return nil
+	}
+
+	raw, ok := schema.Extensions["contains"]
+	if !ok {
+		return nil
+	}
+
+	contains := &Contains{Schema: toSchemaRef(decodeExtension(raw)), Min: 1}
+	if contains.Schema == nil {
+		return nil
+	}
+	if min, ok := extensionInt(schema, "minContains"); ok {
+		contains.Min = min
+	}
+	if max, ok := extensionInt(schema, "maxContains"); ok {
+		contains.Max = &max
+	}
+
+	return contains
+}
+
+// extensionInt reads a non-negative integer keyword from a schema's extensions
+func extensionInt(schema *openapi3.Schema, name string) (int, bool) {
+	switch v := decodeExtension(schema.Extensions[name]).(type) {
+	case float64:
+		if v >= 0 && v == float64(int(v)) {
+			return int(v), true
+		}
+	case int:
+		if v >= 0 {
+			return v, true
+		}
+	}
+	return 0, false
+}
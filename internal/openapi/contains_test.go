@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayContains(t *testing.T) {
+	t.Run("ArrayContains_FromSpec", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Contains API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Scores:
+      type: array
+      contains:
+        type: integer
+        minimum: 1
+      minContains: 2
+      maxContains: 3
+    Tags:
+      type: array
+      contains:
+        const: featured
+`))
+		require.NoError(t, err)
+
+		contains := ArrayContains(spec.Components.Schemas["Scores"].Value)
+		require.NotNil(t, contains)
+		assert.Equal(t, "integer", contains.Schema.Value.Type)
+		assert.Equal(t, 2, contains.Min)
+		require.NotNil(t, contains.Max)
+		assert.Equal(t, 3, *contains.Max)
+
+		// minContains defaults to 1
+		contains = ArrayContains(spec.Components.Schemas["Tags"].Value)
+		require.NotNil(t, contains)
+		assert.Equal(t, 1, contains.Min)
+		assert.Nil(t, contains.Max)
+	})
+
+	t.Run("ArrayContains_NoContains", func(t *testing.T) {
+		assert.Nil(t, ArrayContains(openapi3.NewArraySchema()))
+	})
+}
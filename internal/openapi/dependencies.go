@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DependentRequired returns the JSON Schema dependentRequired keyword of an
+// object schema: for each property, the properties that must also be present
+// when it is. The array form of the older dependencies keyword is read too.
+// Like prefixItems, both are read from the schema's extensions.
+func DependentRequired(schema *openapi3.Schema) map[string][]string {
+	if schema == nil {
+		return nil
+	}
+
+	var dependencies map[string][]string
+	for _, keyword := range []string{"dependencies", "dependentRequired"} {
+		entries, ok := decodeExtension(schema.Extensions[keyword]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, entry := range entries {
+			// Schema dependencies aren't property lists
+			required, ok := entry.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, dependent := range required {
+				if dependent, ok := dependent.(string); ok {
+					if dependencies == nil {
+						dependencies = make(map[string][]string)
+					}
+					dependencies[name] = append(dependencies[name], dependent)
+				}
+			}
+		}
+	}
+	return dependencies
+}
@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependentRequired(t *testing.T) {
+	t.Run("DependentRequired_FromSpec", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Dependencies API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Payment:
+      type: object
+      dependentRequired:
+        creditCard: [billingAddress, cardholder]
+      dependencies:
+        iban: [bic]
+        coupon:
+          required: [campaign]
+`))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string][]string{
+			"creditCard": {"billingAddress", "cardholder"},
+			"iban":       {"bic"},
+		}, DependentRequired(spec.Components.Schemas["Payment"].Value))
+	})
+
+	t.Run("DependentRequired_None", func(t *testing.T) {
+		assert.Nil(t, DependentRequired(openapi3.NewObjectSchema()))
+	})
+}
@@ -1,10 +1,259 @@
 package openapi
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v2"
 )
 
+// remoteTimeout bounds how long fetching a remote spec may take
+const remoteTimeout = 30 * time.Second
+
+// ParseFile loads a spec from a file. External $refs such as
+// ./schemas/user.yaml are resolved relative to the file, so callers always
+// see fully-resolved schemas.
 func ParseFile(filename string) (*openapi3.T, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = normalizeSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	spec, err := loader.LoadFromDataWithPath(data, &url.URL{Path: filename})
+	if err != nil {
+		return nil, err
+	}
+	resolveTuples(spec)
+	return spec, nil
+}
+
+// IsURL reports whether a spec location is an http or https URL rather than
+// a file path
+func IsURL(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Load loads a spec from a file path or an http(s) URL. The headers, such as
+// an Authorization bearer token, are sent with every request for a remote
+// spec and the external documents it references.
+func Load(location string, headers http.Header) (*openapi3.T, error) {
+	if !IsURL(location) {
+		return ParseFile(location)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: remoteTimeout}
+	data, err := fetch(client, u, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = normalizeSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
 	loader := openapi3.NewLoader()
-	return loader.LoadFromFile(filename)
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, ref *url.URL) ([]byte, error) {
+		return fetch(client, ref, headers)
+	}
+	spec, err := loader.LoadFromDataWithPath(data, u)
+	if err != nil {
+		return nil, err
+	}
+	resolveTuples(spec)
+	return spec, nil
+}
+
+func fetch(client *http.Client, u *url.URL, headers http.Header) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", u, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// normalizeSpec rewrites schema constructs that kin-openapi doesn't model:
+// tuples declared as an items array become prefixItems, and in OpenAPI 3.1
+// specs numeric exclusive bounds take their 3.0 form. Specs without either are
+// returned unchanged.
+func normalizeSpec(data []byte) ([]byte, error) {
+	var doc map[interface{}]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	changed := false
+	walkSchemas(doc, func(schema map[interface{}]interface{}) {
+		if normalizeTupleItems(schema) {
+			changed = true
+		}
+	})
+
+	version, _ := doc["openapi"].(string)
+	if strings.HasPrefix(version, "3.1") {
+		walkSchemas(doc, normalizeExclusiveBounds)
+		changed = true
+	}
+
+	if !changed {
+		return data, nil
+	}
+	return yaml.Marshal(doc)
+}
+
+// walkSchemas calls visit for every schema of a document, the values of schema
+// keys and of components.schemas, and for their subschemas. Examples are left
+// out, as they are data rather than schemas.
+func walkSchemas(node interface{}, visit func(schema map[interface{}]interface{})) {
+	switch v := node.(type) {
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			switch key {
+			case "example", "examples":
+			case "schema":
+				walkSchema(child, visit)
+			case "components":
+				components, ok := child.(map[interface{}]interface{})
+				if !ok {
+					continue
+				}
+				for section, entries := range components {
+					if section == "schemas" {
+						walkSchemaMap(entries, visit)
+					} else {
+						walkSchemas(entries, visit)
+					}
+				}
+			default:
+				walkSchemas(child, visit)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkSchemas(child, visit)
+		}
+	}
+}
+
+// walkSchema calls visit for a schema and then for each of its subschemas
+func walkSchema(node interface{}, visit func(schema map[interface{}]interface{})) {
+	schema, ok := node.(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+
+	visit(schema)
+
+	for key, child := range schema {
+		switch key {
+		case "properties", "patternProperties", "dependentSchemas", "$defs":
+			walkSchemaMap(child, visit)
+		case "items", "prefixItems", "allOf", "anyOf", "oneOf":
+			if list, ok := child.([]interface{}); ok {
+				for _, item := range list {
+					walkSchema(item, visit)
+				}
+			} else {
+				walkSchema(child, visit)
+			}
+		case "additionalProperties", "additionalItems", "not", "contains", "if", "then", "else", "propertyNames", "unevaluatedItems", "unevaluatedProperties":
+			walkSchema(child, visit)
+		}
+	}
+}
+
+// walkSchemaMap walks each schema of a map of names to schemas
+func walkSchemaMap(node interface{}, visit func(schema map[interface{}]interface{})) {
+	schemas, ok := node.(map[interface{}]interface{})
+	if !ok {
+		return
+	}
+	for _, schema := range schemas {
+		walkSchema(schema, visit)
+	}
+}
+
+// normalizeTupleItems turns the items array of a tuple into prefixItems, the
+// form ArrayTuple reads. It reports whether the schema changed.
+func normalizeTupleItems(schema map[interface{}]interface{}) bool {
+	items, ok := schema["items"].([]interface{})
+	if !ok {
+		return false
+	}
+	if _, exists := schema["prefixItems"]; !exists {
+		schema["prefixItems"] = items
+	}
+	delete(schema, "items")
+	return true
+}
+
+// normalizeExclusiveBounds converts the numeric exclusiveMinimum and
+// exclusiveMaximum of OpenAPI 3.1 into a minimum/maximum with the 3.0 boolean
+// flag, keeping whichever bound is stricter
+func normalizeExclusiveBounds(schema map[interface{}]interface{}) {
+	convertExclusiveBound(schema, "exclusiveMinimum", "minimum", func(exclusive, inclusive float64) bool { return exclusive >= inclusive })
+	convertExclusiveBound(schema, "exclusiveMaximum", "maximum", func(exclusive, inclusive float64) bool { return exclusive <= inclusive })
+}
+
+func convertExclusiveBound(schema map[interface{}]interface{}, exclusiveKey, inclusiveKey string, stricter func(exclusive, inclusive float64) bool) {
+	exclusive, ok := yamlNumber(schema[exclusiveKey])
+	if !ok {
+		return
+	}
+
+	if inclusive, ok := yamlNumber(schema[inclusiveKey]); ok && !stricter(exclusive, inclusive) {
+		delete(schema, exclusiveKey)
+		return
+	}
+
+	schema[inclusiveKey] = exclusive
+	schema[exclusiveKey] = true
+}
+
+// yamlNumber returns the value of a decoded YAML number
+func yamlNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
 }
@@ -1,9 +1,14 @@
 package openapi
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseFile(t *testing.T) {
@@ -21,3 +26,126 @@ func TestParseFile(t *testing.T) {
 		assert.Nil(t, spec)
 	})
 }
+
+func TestLoad_URL(t *testing.T) {
+	data, err := os.ReadFile("../../docs/openapi.yaml")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	t.Run("WithHeaders", func(t *testing.T) {
+		headers := http.Header{"Authorization": []string{"Bearer secret"}}
+		spec, err := Load(server.URL+"/openapi.yaml", headers)
+		require.NoError(t, err)
+		assert.Equal(t, "Simple API", spec.Info.Title)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		_, err := Load(server.URL+"/openapi.yaml", nil)
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("LocalPath", func(t *testing.T) {
+		assert.False(t, IsURL("../../docs/openapi.yaml"))
+		spec, err := Load("../../docs/openapi.yaml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Simple API", spec.Info.Title)
+	})
+}
+
+func TestParseFile_ExclusiveBounds31(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+openapi: 3.1.0
+info:
+  title: Bounds API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Price:
+      type: number
+      exclusiveMinimum: 0
+      exclusiveMaximum: 1000
+      maximum: 500
+`), 0644))
+
+	spec, err := ParseFile(path)
+	require.NoError(t, err)
+
+	price := spec.Components.Schemas["Price"].Value
+	require.NotNil(t, price.Min)
+	assert.Equal(t, 0.0, *price.Min)
+	assert.True(t, price.ExclusiveMin)
+
+	// The inclusive maximum is stricter than the exclusive one
+	require.NotNil(t, price.Max)
+	assert.Equal(t, 500.0, *price.Max)
+	assert.False(t, price.ExclusiveMax)
+}
+
+func TestParseFile_ExclusiveBounds31_SchemaPositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+openapi: 3.1.0
+info:
+  title: Bounds API
+  version: 1.0.0
+paths:
+  /ranges:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            exclusiveMinimum: 0
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Range'
+              example:
+                exclusiveMinimum: 5
+components:
+  schemas:
+    Range:
+      type: object
+      properties:
+        exclusiveMinimum:
+          type: number
+        size:
+          type: number
+          exclusiveMaximum: 10
+      example:
+        exclusiveMinimum: 5
+`), 0644))
+
+	spec, err := ParseFile(path)
+	require.NoError(t, err)
+
+	limit := spec.Paths.Find("/ranges").Get.Parameters[0].Value.Schema.Value
+	require.NotNil(t, limit.Min)
+	assert.True(t, limit.ExclusiveMin)
+
+	rangeSchema := spec.Components.Schemas["Range"].Value
+	size := rangeSchema.Properties["size"].Value
+	require.NotNil(t, size.Max)
+	assert.Equal(t, 10.0, *size.Max)
+	assert.True(t, size.ExclusiveMax)
+
+	// Examples and property names are data, not schemas
+	assert.Contains(t, rangeSchema.Properties, "exclusiveMinimum")
+	assert.Equal(t, map[string]interface{}{"exclusiveMinimum": 5.0}, rangeSchema.Example)
+	example := spec.Paths.Find("/ranges").Get.Responses.Status(200).Value.Content["application/json"].Example
+	assert.Equal(t, map[string]interface{}{"exclusiveMinimum": 5.0}, example)
+}
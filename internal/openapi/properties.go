@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PatternProperty is an entry of the JSON Schema patternProperties keyword:
+// properties whose name matches Pattern must match Schema. Like prefixItems,
+// patternProperties and propertyNames are read from the schema's extensions.
+type PatternProperty struct {
+	// Pattern is the regular expression property names are matched against
+	Pattern *regexp.Regexp
+
+	// Schema applies to the values of matching properties
+	Schema *openapi3.SchemaRef
+}
+
+// PatternProperties returns the patternProperties of an object schema, sorted
+// by pattern. Entries with an invalid regular expression are skipped.
+func PatternProperties(schema *openapi3.Schema) []PatternProperty {
+	if schema == nil {
+		return nil
+	}
+
+	entries, ok := decodeExtension(schema.Extensions["patternProperties"]).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(entries))
+	for pattern := range entries {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	properties := make([]PatternProperty, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		ref := toSchemaRef(entries[pattern])
+		if ref == nil {
+			continue
+		}
+		properties = append(properties, PatternProperty{Pattern: re, Schema: ref})
+	}
+	return properties
+}
+
+// PropertyNames returns the schema every property name of an object must
+// match, or nil if the schema doesn't declare propertyNames
+func PropertyNames(schema *openapi3.Schema) *openapi3.SchemaRef {
+	if schema == nil {
+		return nil
+	}
+
+	raw, ok := schema.Extensions["propertyNames"]
+	if !ok {
+		return nil
+	}
+	return toSchemaRef(decodeExtension(raw))
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternProperties(t *testing.T) {
+	t.Run("PatternProperties_FromSpec", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Extensions API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Extensions:
+      type: object
+      patternProperties:
+        "^x-":
+          type: string
+        "^n-":
+          type: integer
+        "([":
+          type: string
+      propertyNames:
+        pattern: "^[a-z-]+$"
+`))
+		require.NoError(t, err)
+		schema := spec.Components.Schemas["Extensions"].Value
+
+		properties := PatternProperties(schema)
+		require.Len(t, properties, 2)
+		assert.Equal(t, "^n-", properties[0].Pattern.String())
+		assert.Equal(t, "integer", properties[0].Schema.Value.Type)
+		assert.Equal(t, "^x-", properties[1].Pattern.String())
+		assert.Equal(t, "string", properties[1].Schema.Value.Type)
+
+		names := PropertyNames(schema)
+		require.NotNil(t, names)
+		assert.Equal(t, "^[a-z-]+$", names.Value.Pattern)
+	})
+
+	t.Run("PatternProperties_None", func(t *testing.T) {
+		assert.Nil(t, PatternProperties(openapi3.NewObjectSchema()))
+		assert.Nil(t, PropertyNames(openapi3.NewObjectSchema()))
+	})
+}
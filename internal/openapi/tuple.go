@@ -0,0 +1,239 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Tuple describes a positional array schema. kin-openapi only models a single
+// items schema, so tuples are kept in the schema's extensions as the JSON
+// Schema prefixItems keyword, along with additionalItems. Specs may declare
+// them with prefixItems or with an items array, which the parser converts.
+type Tuple struct {
+	// Items holds the schema of each position in the tuple
+	Items []*openapi3.SchemaRef
+
+	// Additional is the schema for items past the tuple, or nil if they're unconstrained
+	Additional *openapi3.SchemaRef
+
+	// Closed is set by additionalItems: false, which rejects items past the tuple
+	Closed bool
+}
+
+// ArrayTuple returns the tuple definition of an array schema, or nil if the
+// schema doesn't declare prefixItems
+func ArrayTuple(schema *openapi3.Schema) *Tuple {
+	if schema == nil {
+		return nil
+	}
+
+	raw, ok := schema.Extensions["prefixItems"]
+	if !ok {
+		return nil
+	}
+	raw = decodeExtension(raw)
+
+	tuple := &Tuple{}
+	switch v := raw.(type) {
+	case openapi3.SchemaRefs:
+		tuple.Items = v
+	case []*openapi3.SchemaRef:
+		tuple.Items = v
+	case []interface{}:
+		for _, item := range v {
+			ref := toSchemaRef(item)
+			if ref == nil {
+				return nil
+			}
+			tuple.Items = append(tuple.Items, ref)
+		}
+	default:
+		return nil
+	}
+
+	switch v := decodeExtension(schema.Extensions["additionalItems"]).(type) {
+	case bool:
+		tuple.Closed = !v
+	case nil:
+		tuple.Additional = schema.Items
+	default:
+		tuple.Additional = toSchemaRef(v)
+	}
+
+	return tuple
+}
+
+// ItemSchema returns the schema for the item at index i, or nil if the item
+// isn't constrained
+func (t *Tuple) ItemSchema(i int) *openapi3.SchemaRef {
+	if i < len(t.Items) {
+		return t.Items[i]
+	}
+	return t.Additional
+}
+
+// toSchemaRef converts an extension value decoded as generic JSON back into a
+// schema. A $ref is kept as a reference, see resolveTuples.
+func toSchemaRef(v interface{}) *openapi3.SchemaRef {
+	switch s := v.(type) {
+	case *openapi3.SchemaRef:
+		return s
+	case *openapi3.Schema:
+		return &openapi3.SchemaRef{Value: s}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var ref openapi3.SchemaRef
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil
+	}
+	return &ref
+}
+
+// componentSchemaPrefix starts a $ref to a schema of the spec's components
+const componentSchemaPrefix = "#/components/schemas/"
+
+// resolveTuples stores the tuple of every array schema in the spec as
+// resolved schemas. The loader only resolves the $refs of the keywords it
+// models, so references to component schemas in tuple entries, at any depth,
+// are resolved here.
+func resolveTuples(spec *openapi3.T) {
+	r := &tupleResolver{spec: spec, seen: make(map[*openapi3.Schema]bool)}
+
+	if spec.Components != nil {
+		for _, schema := range spec.Components.Schemas {
+			r.schema(schema)
+		}
+		for _, param := range spec.Components.Parameters {
+			r.parameter(param)
+		}
+		for _, body := range spec.Components.RequestBodies {
+			if body != nil && body.Value != nil {
+				r.content(body.Value.Content)
+			}
+		}
+		for _, response := range spec.Components.Responses {
+			r.response(response)
+		}
+	}
+
+	if spec.Paths == nil {
+		return
+	}
+	for _, pathItem := range spec.Paths.Map() {
+		for _, param := range pathItem.Parameters {
+			r.parameter(param)
+		}
+		for _, op := range pathItem.Operations() {
+			for _, param := range op.Parameters {
+				r.parameter(param)
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				r.content(op.RequestBody.Value.Content)
+			}
+			if op.Responses != nil {
+				for _, response := range op.Responses.Map() {
+					r.response(response)
+				}
+			}
+		}
+	}
+}
+
+// tupleResolver walks the schemas of a spec once each, resolving their tuples
+type tupleResolver struct {
+	spec *openapi3.T
+	seen map[*openapi3.Schema]bool
+}
+
+func (r *tupleResolver) parameter(param *openapi3.ParameterRef) {
+	if param != nil && param.Value != nil {
+		r.schema(param.Value.Schema)
+		r.content(param.Value.Content)
+	}
+}
+
+func (r *tupleResolver) response(response *openapi3.ResponseRef) {
+	if response == nil || response.Value == nil {
+		return
+	}
+	r.content(response.Value.Content)
+	for _, header := range response.Value.Headers {
+		if header != nil && header.Value != nil {
+			r.schema(header.Value.Schema)
+		}
+	}
+}
+
+func (r *tupleResolver) content(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			r.schema(mediaType.Schema)
+		}
+	}
+}
+
+func (r *tupleResolver) schema(ref *openapi3.SchemaRef) {
+	if ref == nil {
+		return
+	}
+	r.resolve(ref)
+
+	schema := ref.Value
+	if schema == nil || r.seen[schema] {
+		return
+	}
+	r.seen[schema] = true
+
+	if tuple := ArrayTuple(schema); tuple != nil {
+		schema.Extensions["prefixItems"] = openapi3.SchemaRefs(tuple.Items)
+		for _, item := range tuple.Items {
+			r.schema(item)
+		}
+		if _, ok := decodeExtension(schema.Extensions["additionalItems"]).(map[string]interface{}); ok && tuple.Additional != nil {
+			schema.Extensions["additionalItems"] = tuple.Additional
+			r.schema(tuple.Additional)
+		}
+	}
+
+	for _, prop := range schema.Properties {
+		r.schema(prop)
+	}
+	r.schema(schema.Items)
+	r.schema(schema.Not)
+	r.schema(schema.AdditionalProperties.Schema)
+	for _, group := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, sub := range group {
+			r.schema(sub)
+		}
+	}
+}
+
+// resolve points an unresolved reference to a component schema at it
+func (r *tupleResolver) resolve(ref *openapi3.SchemaRef) {
+	if ref.Value != nil || !strings.HasPrefix(ref.Ref, componentSchemaPrefix) || r.spec.Components == nil {
+		return
+	}
+	if target := r.spec.Components.Schemas[strings.TrimPrefix(ref.Ref, componentSchemaPrefix)]; target != nil {
+		ref.Value = target.Value
+	}
+}
+
+// decodeExtension decodes extensions that were kept as raw JSON
+func decodeExtension(v interface{}) interface{} {
+	raw, ok := v.(json.RawMessage)
+	if !ok {
+		return v
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
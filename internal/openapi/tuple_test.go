@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayTuple(t *testing.T) {
+	t.Run("ArrayTuple_FromSpec", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Tuple API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Point:
+      type: array
+      prefixItems:
+        - type: number
+        - type: string
+      additionalItems: false
+    Row:
+      type: array
+      prefixItems:
+        - type: string
+      items:
+        type: integer
+`))
+		require.NoError(t, err)
+
+		point := ArrayTuple(spec.Components.Schemas["Point"].Value)
+		require.NotNil(t, point)
+		require.Len(t, point.Items, 2)
+		assert.Equal(t, "number", point.Items[0].Value.Type)
+		assert.Equal(t, "string", point.Items[1].Value.Type)
+		assert.True(t, point.Closed)
+		assert.Nil(t, point.ItemSchema(2))
+
+		row := ArrayTuple(spec.Components.Schemas["Row"].Value)
+		require.NotNil(t, row)
+		assert.False(t, row.Closed)
+		assert.Equal(t, "integer", row.ItemSchema(3).Value.Type)
+	})
+
+	t.Run("ArrayTuple_ItemsArrayWithRefs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "openapi.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+openapi: 3.0.0
+info:
+  title: Tuple API
+  version: 1.0.0
+paths:
+  /points:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Point'
+components:
+  schemas:
+    Coordinate:
+      type: number
+    Label:
+      type: object
+      properties:
+        text:
+          type: string
+    Point:
+      type: array
+      items:
+        - $ref: '#/components/schemas/Coordinate'
+        - type: object
+          properties:
+            label:
+              $ref: '#/components/schemas/Label'
+      additionalItems: false
+`), 0644))
+
+		spec, err := ParseFile(path)
+		require.NoError(t, err)
+
+		schema := spec.Paths.Find("/points").Get.Responses.Status(200).Value.Content["application/json"].Schema.Value
+		point := ArrayTuple(schema)
+		require.NotNil(t, point)
+		require.Len(t, point.Items, 2)
+		assert.True(t, point.Closed)
+
+		require.NotNil(t, point.Items[0].Value)
+		assert.Equal(t, "number", point.Items[0].Value.Type)
+
+		label := point.Items[1].Value.Properties["label"]
+		require.NotNil(t, label.Value)
+		assert.Equal(t, "string", label.Value.Properties["text"].Value.Type)
+	})
+
+	t.Run("ArrayTuple_NotATuple", func(t *testing.T) {
+		schema := openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema())
+		assert.Nil(t, ArrayTuple(schema))
+	})
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// action returns the action configured for an operation
+func (s *Server) action(op *openapi3.Operation) (config.ActionConfig, bool) {
+	if op.OperationID == "" {
+		return config.ActionConfig{}, false
+	}
+	action, ok := s.cfg.Behavior.Actions[op.OperationID]
+	return action, ok
+}
+
+// actionTarget finds the resource an action path operates on. For
+// /users/{id}/activate the trailing segment is parsed as a nested resource,
+// so the target is its parent.
+func actionTarget(resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) (string, string) {
+	if nestedInfo != nil && nestedInfo.IsNested && nestedInfo.ChildID == "" {
+		return nestedInfo.ParentResource, nestedInfo.ParentID
+	}
+	return resourceName, resolveResourceID(pathParams, nestedInfo)
+}
+
+// handleAction applies an action's field updates to its target resource and
+// returns the updated resource
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request, action config.ActionConfig, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	targetName, targetID := actionTarget(resourceName, pathParams, nestedInfo)
+	if targetID == "" {
+		s.writeError(w, r, http.StatusBadRequest, "missing_id", "Resource ID required")
+		return
+	}
+
+	existing, err := s.stateManager.GetResource(targetName, targetID)
+	if err != nil {
+		s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
+		return
+	}
+
+	existingMap, ok := existing.(map[string]interface{})
+	if !ok {
+		http.Error(w, "invalid resource format", http.StatusInternalServerError)
+		return
+	}
+
+	for key, value := range action.Set {
+		existingMap[key] = value
+	}
+
+	if !s.checkUnique(w, r, targetName, targetID, existingMap) {
+		return
+	}
+
+	if err := s.stateManager.UpdateResource(targetName, targetID, existingMap); err != nil {
+		http.Error(w, fmt.Sprintf("failed to update resource: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.changes.publish(eventUpdated, targetName, targetID)
+	s.fireWebhooks(eventUpdated, targetName, targetID, existingMap)
+
+	s.writeData(w, http.StatusOK, s.redact(existingMap))
+}
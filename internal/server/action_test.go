@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionEndpoint_Activate(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Actions = map[string]config.ActionConfig{
+		"activateUser": {Set: map[string]interface{}{"status": "active"}},
+	}
+
+	spec := createTestSpec()
+	spec.Paths.Set("/users/{id}/activate", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			OperationID: "activateUser",
+			Responses:   spec.Paths.Value("/users/{id}").Put.Responses,
+		},
+	})
+
+	server := NewServer(spec, cfg)
+	handler := server.createHandler()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/users", `{"id": "1", "name": "Ada", "status": "pending"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = do(http.MethodPost, "/users/1/activate", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "active", response["status"])
+	assert.Equal(t, "Ada", response["name"])
+
+	// The target resource is updated rather than an activate sub-resource created
+	w = do(http.MethodGet, "/users/1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "active", response["status"])
+
+	w = do(http.MethodPost, "/users/missing/activate", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
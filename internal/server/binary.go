@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// generatedBinarySize is the size of generated binary bodies when no file is configured
+const generatedBinarySize = 1024
+
+// binaryResponse reports whether an operation's success response is a file
+// download, returning the status code and media type to respond with
+func binaryResponse(op *openapi3.Operation) (int, string, bool) {
+	code, resp := firstSuccessResponse(op)
+	if resp == nil || len(resp.Content) == 0 {
+		return 0, "", false
+	}
+
+	if resp.Content.Get("application/json") != nil {
+		return 0, "", false
+	}
+
+	for ct, mt := range resp.Content {
+		if isBinaryMediaType(ct, mt) {
+			return code, ct, true
+		}
+	}
+
+	return 0, "", false
+}
+
+// isBinaryMediaType checks if a media type describes raw file content
+func isBinaryMediaType(contentType string, mt *openapi3.MediaType) bool {
+	if mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+		schema := mt.Schema.Value
+		if schema.Type == "string" && schema.Format == "binary" {
+			return true
+		}
+	}
+
+	ct := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case ct == "application/octet-stream", ct == "application/pdf", ct == "application/zip":
+		return true
+	case strings.HasPrefix(ct, "image/"), strings.HasPrefix(ct, "audio/"), strings.HasPrefix(ct, "video/"):
+		return true
+	}
+
+	return false
+}
+
+// handleBinary serves the configured file for an operation, or generated
+// content matching its media type, as an attachment
+func (s *Server) handleBinary(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, statusCode int, mediaType string) {
+	var content []byte
+	var filename string
+
+	if path, ok := s.cfg.Behavior.Files[op.OperationID]; ok && op.OperationID != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, "file_error", fmt.Sprintf("failed to read file for %s: %v", op.OperationID, err))
+			return
+		}
+		content = data
+		filename = filepath.Base(path)
+	} else {
+		data, err := generateBinary(mediaType)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, "file_error", fmt.Sprintf("failed to generate file content: %v", err))
+			return
+		}
+		content = data
+		filename = defaultFilename(op, r.URL.Path, mediaType)
+	}
+
+	// Wildcard media types can't be sent as a Content-Type
+	if strings.Contains(mediaType, "*") {
+		mediaType = http.DetectContentType(content)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	// ServeContent handles Range requests (206/416) and HEAD, but always
+	// answers full requests with 200
+	if statusCode == http.StatusOK {
+		http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(content))
+		return
+	}
+
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+	w.WriteHeader(statusCode)
+	if r.Method != http.MethodHead {
+		w.Write(content)
+	}
+}
+
+// generateBinary produces placeholder content for a media type. PNG images are
+// valid so clients that decode them don't fail; everything else is random bytes.
+func generateBinary(mediaType string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(mediaType), "image/png") {
+		img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+		fill := color.RGBA{uint8(rand.Intn(256)), uint8(rand.Intn(256)), uint8(rand.Intn(256)), 255}
+		for x := 0; x < 16; x++ {
+			for y := 0; y < 16; y++ {
+				img.Set(x, y, fill)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	data := make([]byte, generatedBinarySize)
+	rand.Read(data)
+	return data, nil
+}
+
+// defaultFilename builds a download name from the operation ID or the last path
+// segment, with an extension matching the media type
+func defaultFilename(op *openapi3.Operation, path, mediaType string) string {
+	name := op.OperationID
+	if name == "" {
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		name = parts[len(parts)-1]
+	}
+	if name == "" {
+		name = "download"
+	}
+
+	ext := ".bin"
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+	if mediaType == "image/png" {
+		ext = ".png"
+	}
+
+	return name + ext
+}
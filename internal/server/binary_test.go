@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createBinaryTestSpec() *openapi3.T {
+	spec := createTestSpec()
+
+	binarySchema := &openapi3.Schema{}
+	binarySchema.Type = "string"
+	binarySchema.Format = "binary"
+
+	downloadResponses := openapi3.NewResponses()
+	downloadResponses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"application/octet-stream": &openapi3.MediaType{
+					Schema: &openapi3.SchemaRef{Value: binarySchema},
+				},
+			},
+		},
+	})
+	spec.Paths.Set("/reports/{id}/download", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "downloadReport",
+			Responses:   downloadResponses,
+		},
+	})
+
+	avatarResponses := openapi3.NewResponses()
+	avatarResponses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"image/png": &openapi3.MediaType{},
+			},
+		},
+	})
+	spec.Paths.Set("/users/{id}/avatar", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "getAvatar",
+			Responses:   avatarResponses,
+		},
+	})
+
+	return spec
+}
+
+func TestBinaryResponse_ConfiguredFile(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	content := []byte{0x00, 0x01, 0x02, 0xff, 'r', 'e', 'p', 'o', 'r', 't'}
+	filePath := filepath.Join(t.TempDir(), "report.dat")
+	require.NoError(t, os.WriteFile(filePath, content, 0644))
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Files = map[string]string{"downloadReport": filePath}
+
+	server := NewServer(createBinaryTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=report.dat`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "10", w.Header().Get("Content-Length"))
+	assert.Equal(t, content, w.Body.Bytes())
+}
+
+func TestBinaryResponse_GeneratedContent(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createBinaryTestSpec(), createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=downloadReport.bin`, w.Header().Get("Content-Disposition"))
+	assert.Len(t, w.Body.Bytes(), generatedBinarySize)
+
+	req = httptest.NewRequest(http.MethodGet, "/users/1/avatar", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=getAvatar.png`, w.Header().Get("Content-Disposition"))
+	_, err = png.Decode(bytes.NewReader(w.Body.Bytes()))
+	assert.NoError(t, err)
+}
+
+func TestBinaryResponse_MissingFile(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Files = map[string]string{"downloadReport": filepath.Join(t.TempDir(), "missing.pdf")}
+
+	server := NewServer(createBinaryTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "file_error")
+}
+
+func TestBinaryResponse_Range(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	content := []byte("0123456789abcdefghij")
+	filePath := filepath.Join(t.TempDir(), "report.dat")
+	require.NoError(t, os.WriteFile(filePath, content, 0644))
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Files = map[string]string{"downloadReport": filePath}
+
+	server := NewServer(createBinaryTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	req.Header.Set("Range", "bytes=5-9")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 5-9/20", w.Header().Get("Content-Range"))
+	assert.Equal(t, "5", w.Header().Get("Content-Length"))
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, content[5:10], w.Body.Bytes())
+
+	req = httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	req.Header.Set("Range", "bytes=100-200")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "bytes */20", w.Header().Get("Content-Range"))
+
+	req = httptest.NewRequest(http.MethodGet, "/reports/42/download", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	assert.Equal(t, content, w.Body.Bytes())
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// runtimeExpression matches the {expression} parts of a callback URL
+var runtimeExpression = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// callbackContext is what runtime expressions in callback URLs are evaluated
+// against
+type callbackContext struct {
+	request      *http.Request
+	pathParams   map[string]string
+	requestBody  interface{}
+	statusCode   int
+	responseBody interface{}
+}
+
+// fireCallbacks sends the callbacks an operation declares, in the background.
+// Callback URLs whose expressions can't be resolved are skipped.
+func (s *Server) fireCallbacks(op *openapi3.Operation, ctx callbackContext) {
+	names := make([]string, 0, len(op.Callbacks))
+	for name := range op.Callbacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		callback := op.Callbacks[name]
+		if callback == nil || callback.Value == nil {
+			continue
+		}
+
+		items := callback.Value.Map()
+		expressions := make([]string, 0, len(items))
+		for expression := range items {
+			expressions = append(expressions, expression)
+		}
+		sort.Strings(expressions)
+
+		for _, expression := range expressions {
+			url, err := expandCallbackURL(expression, ctx)
+			if err != nil {
+				log.Printf("Skipping callback %s: %v", name, err)
+				continue
+			}
+
+			operations := items[expression].Operations()
+			methods := make([]string, 0, len(operations))
+			for method := range operations {
+				methods = append(methods, method)
+			}
+			sort.Strings(methods)
+
+			for _, method := range methods {
+				body, err := callbackBody(operations[method])
+				if err != nil {
+					log.Printf("Skipping callback %s: %v", name, err)
+					continue
+				}
+				go deliverCallback(method, url, body)
+			}
+		}
+	}
+}
+
+// expandCallbackURL replaces the runtime expressions in a callback URL, such
+// as {$request.body#/callbackUrl}
+func expandCallbackURL(template string, ctx callbackContext) (string, error) {
+	var expandErr error
+	url := runtimeExpression.ReplaceAllStringFunc(template, func(match string) string {
+		expression := strings.TrimSpace(match[1 : len(match)-1])
+		value, ok := evalRuntimeExpression(expression, ctx)
+		if !ok && expandErr == nil {
+			expandErr = fmt.Errorf("cannot resolve %s", expression)
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return url, nil
+}
+
+// evalRuntimeExpression evaluates an OpenAPI runtime expression
+func evalRuntimeExpression(expression string, ctx callbackContext) (string, bool) {
+	r := ctx.request
+	switch {
+	case expression == "$url":
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		return scheme + "://" + r.Host + r.URL.RequestURI(), true
+	case expression == "$method":
+		return r.Method, true
+	case expression == "$statusCode":
+		return strconv.Itoa(ctx.statusCode), true
+	case strings.HasPrefix(expression, "$request.header."):
+		value := r.Header.Get(strings.TrimPrefix(expression, "$request.header."))
+		return value, value != ""
+	case strings.HasPrefix(expression, "$request.query."):
+		values, ok := r.URL.Query()[strings.TrimPrefix(expression, "$request.query.")]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case strings.HasPrefix(expression, "$request.path."):
+		value, ok := ctx.pathParams[strings.TrimPrefix(expression, "$request.path.")]
+		return value, ok
+	case strings.HasPrefix(expression, "$request.body"):
+		return resolveBodyPointer(ctx.requestBody, strings.TrimPrefix(expression, "$request.body"))
+	case strings.HasPrefix(expression, "$response.body"):
+		return resolveBodyPointer(ctx.responseBody, strings.TrimPrefix(expression, "$response.body"))
+	}
+	return "", false
+}
+
+// resolveBodyPointer resolves a #/json/pointer fragment against a decoded
+// JSON body and formats the value found
+func resolveBodyPointer(body interface{}, fragment string) (string, bool) {
+	if fragment != "" && !strings.HasPrefix(fragment, "#") {
+		return "", false
+	}
+	pointer := strings.TrimPrefix(fragment, "#")
+
+	value := body
+	if pointer != "" {
+		unescape := strings.NewReplacer("~1", "/", "~0", "~")
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = unescape.Replace(token)
+			switch v := value.(type) {
+			case map[string]interface{}:
+				next, ok := v[token]
+				if !ok {
+					return "", false
+				}
+				value = next
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(v) {
+					return "", false
+				}
+				value = v[i]
+			default:
+				return "", false
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		return string(encoded), err == nil
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// callbackBody generates a JSON body from the callback operation's request
+// schema, or returns nil when it has none
+func callbackBody(op *openapi3.Operation) ([]byte, error) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil, nil
+	}
+	mt := op.RequestBody.Value.Content.Get("application/json")
+	if mt == nil || mt.Schema == nil {
+		return nil, nil
+	}
+
+	data, err := generator.GenerateAdvancedData(mt.Schema, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// deliverCallback sends a callback request, logging failures
+func deliverCallback(method, url string, body []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Callback %s %s failed: %v", method, url, err)
+		return
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		log.Printf("Callback %s %s failed: %v", method, url, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Callback %s %s failed: unexpected status %s", method, url, resp.Status)
+	}
+}
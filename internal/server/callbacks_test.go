@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const callbackTestSpec = `
+openapi: 3.0.0
+info:
+  title: Callbacks API
+  version: 1.0.0
+paths:
+  /subscriptions:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                callbackUrl:
+                  type: string
+      responses:
+        '201':
+          description: Subscribed
+      callbacks:
+        onEvent:
+          '{$request.body#/callbackUrl}/events/{$response.body#/id}':
+            post:
+              requestBody:
+                content:
+                  application/json:
+                    schema:
+                      type: object
+                      required: [event]
+                      properties:
+                        event:
+                          type: string
+                          enum: [created]
+              responses:
+                '200':
+                  description: Received
+`
+
+func TestCallbacks_FiredAfterPost(t *testing.T) {
+	type received struct {
+		path string
+		body map[string]interface{}
+	}
+	requests := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests <- received{path: r.URL.Path, body: body}
+	}))
+	defer receiver.Close()
+
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(callbackTestSpec))
+	require.NoError(t, err)
+
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Callbacks = true
+	handler := NewServer(spec, cfg).createHandler()
+
+	reqBody, _ := json.Marshal(map[string]interface{}{"id": "sub-1", "callbackUrl": receiver.URL})
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	select {
+	case got := <-requests:
+		assert.Equal(t, "/events/sub-1", got.path)
+		assert.Equal(t, "created", got.body["event"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+}
+
+func TestExpandCallbackURL(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://api.test/orders?tenant=acme", nil)
+	req.Header.Set("X-Hook", "http://hooks.test")
+	ctx := callbackContext{
+		request:      req,
+		pathParams:   map[string]string{"orgId": "o1"},
+		requestBody:  map[string]interface{}{"hooks": []interface{}{map[string]interface{}{"url": "http://a.test/x"}}},
+		statusCode:   http.StatusCreated,
+		responseBody: map[string]interface{}{"id": "42"},
+	}
+
+	tests := []struct {
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{template: "{$request.body#/hooks/0/url}", want: "http://a.test/x"},
+		{template: "{$request.header.X-Hook}/{$request.query.tenant}/{$request.path.orgId}", want: "http://hooks.test/acme/o1"},
+		{template: "http://b.test/{$method}/{$statusCode}/{$response.body#/id}", want: "http://b.test/POST/201/42"},
+		{template: "{$request.body#/missing}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := expandCallbackURL(tt.template, ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
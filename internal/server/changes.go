@@ -0,0 +1,154 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+)
+
+// eventsPath is the Server-Sent Events stream of resource changes
+const eventsPath = "/_meridian/events"
+
+// maxPreferWait caps how long a Prefer: wait request is held
+const maxPreferWait = 5 * time.Minute
+
+// Resource change events
+const (
+	eventCreated = "created"
+	eventUpdated = "updated"
+	eventDeleted = "deleted"
+)
+
+// changeEvent describes a resource that was created, updated or deleted
+type changeEvent struct {
+	Event    string `json:"event"`
+	Resource string `json:"resource"`
+	ID       string `json:"id"`
+}
+
+// changeHub notifies subscribers when a resource is created, updated or
+// deleted
+type changeHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+	streams     map[*deliveryQueue[changeEvent]]struct{}
+	queue       config.DeliveryQueueConfig
+
+	// dropped counts events discarded because a stream fell too far behind
+	dropped atomic.Uint64
+}
+
+func newChangeHub(queue config.DeliveryQueueConfig) *changeHub {
+	return &changeHub{
+		subscribers: make(map[string]map[chan struct{}]struct{}),
+		streams:     make(map[*deliveryQueue[changeEvent]]struct{}),
+		queue:       queue,
+	}
+}
+
+func changeKey(resourceName, resourceID string) string {
+	return resourceName + "/" + resourceID
+}
+
+// subscribe returns a channel that receives a value when the resource
+// changes, and a function that cancels the subscription
+func (h *changeHub) subscribe(resourceName, resourceID string) (<-chan struct{}, func()) {
+	key := changeKey(resourceName, resourceID)
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[chan struct{}]struct{})
+	}
+	h.subscribers[key][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[key], ch)
+		if len(h.subscribers[key]) == 0 {
+			delete(h.subscribers, key)
+		}
+	}
+}
+
+// stream returns a queue that receives every change, and a function that
+// cancels the subscription
+func (h *changeHub) stream() (*deliveryQueue[changeEvent], func()) {
+	queue := newDeliveryQueue[changeEvent](h.queue, &h.dropped)
+
+	h.mu.Lock()
+	h.streams[queue] = struct{}{}
+	h.mu.Unlock()
+
+	return queue, func() {
+		// Close first so a publish blocked on this stream moves on
+		queue.close()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.streams, queue)
+	}
+}
+
+// publish notifies every subscriber of the resource without blocking and
+// queues the change on every stream. The streams are pushed to outside the
+// lock, since a full stream with the block policy waits for its client, and
+// must not hold up other changes or subscriptions meanwhile.
+func (h *changeHub) publish(event, resourceName, resourceID string) {
+	h.mu.Lock()
+	for ch := range h.subscribers[changeKey(resourceName, resourceID)] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+	streams := make([]*deliveryQueue[changeEvent], 0, len(h.streams))
+	for queue := range h.streams {
+		streams = append(streams, queue)
+	}
+	h.mu.Unlock()
+
+	change := changeEvent{Event: event, Resource: resourceName, ID: resourceID}
+	for _, queue := range streams {
+		queue.push(change)
+	}
+}
+
+// preferWait returns the wait preference of a request (RFC 7240), such as
+// Prefer: wait=10
+func preferWait(r *http.Request) (time.Duration, bool) {
+	value, ok := preference(r, "wait")
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0, false
+	}
+	wait := time.Duration(seconds) * time.Second
+	if wait > maxPreferWait {
+		wait = maxPreferWait
+	}
+	return wait, true
+}
+
+// awaitChange holds a request until the resource changes, the wait elapses or
+// the client goes away. It reports whether the resource changed.
+func awaitChange(r *http.Request, changed <-chan struct{}, wait time.Duration) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-changed:
+		return true
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferWait_ReturnsOnChange(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createTestSpec(), createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	do := func(method, path, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/users", `{"id": "1", "name": "Ada"}`, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	start := time.Now()
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- do(http.MethodGet, "/users/1", "", http.Header{"Prefer": {"wait=10"}})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	w = do(http.MethodPut, "/users/1", `{"name": "Grace"}`, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case w = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiting GET did not return after the update")
+	}
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "wait=10", w.Header().Get("Preference-Applied"))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Grace", response["name"])
+
+	// Without a change the current resource is returned once the wait elapses
+	start = time.Now()
+	w = do(http.MethodGet, "/users/1", "", http.Header{"Prefer": {"respond-async, wait=1"}})
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Grace", response["name"])
+}
+
+func TestPreferWait_Parsing(t *testing.T) {
+	tests := []struct {
+		header string
+		wait   time.Duration
+		ok     bool
+	}{
+		{header: "wait=10", wait: 10 * time.Second, ok: true},
+		{header: "return=minimal, wait=3", wait: 3 * time.Second, ok: true},
+		{header: "wait=100000", wait: maxPreferWait, ok: true},
+		{header: "wait=soon"},
+		{header: "return=minimal"},
+		{header: ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		if tt.header != "" {
+			req.Header.Set("Prefer", tt.header)
+		}
+
+		wait, ok := preferWait(req)
+		assert.Equal(t, tt.ok, ok, tt.header)
+		assert.Equal(t, tt.wait, wait, tt.header)
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	ts := httptest.NewServer(NewServer(createTestSpec(), createTestConfig(tmpFile.Name())).createHandler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/_meridian/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The stream is subscribed once the headers arrive
+	post, err := http.Post(ts.URL+"/users", "application/json", strings.NewReader(`{"id": "1", "name": "Alice"}`))
+	require.NoError(t, err)
+	post.Body.Close()
+	require.Equal(t, http.StatusCreated, post.StatusCode)
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var received []string
+	timeout := time.After(2 * time.Second)
+	for len(received) < 2 {
+		select {
+		case line := <-lines:
+			if line != "" {
+				received = append(received, line)
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for event, got %v", received)
+		}
+	}
+
+	assert.Equal(t, "event: created", received[0])
+	var change changeEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(received[1], "data: ")), &change))
+	assert.Equal(t, changeEvent{Event: "created", Resource: "users", ID: "1"}, change)
+}
+
+func TestChangeHub_BlockedStream(t *testing.T) {
+	hub := newChangeHub(config.DeliveryQueueConfig{Capacity: 1, Policy: queueBlock})
+	_, cancelStream := hub.stream()
+	defer cancelStream()
+
+	// The second change waits for the stream's client, which never reads
+	hub.publish(eventCreated, "users", "1")
+	go hub.publish(eventCreated, "users", "2")
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, unsubscribe := hub.subscribe("users", "1")
+		unsubscribe()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a blocked stream holds up subscriptions")
+	}
+}
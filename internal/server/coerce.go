@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// coerceResponse converts stored values to the types the operation's 200
+// response schema declares, when coerce_types is enabled
+func (s *Server) coerceResponse(op *openapi3.Operation, data interface{}) interface{} {
+	if !s.cfg.Behavior.CoerceTypes {
+		return data
+	}
+	schema := responseSchema(op, http.StatusOK)
+	if schema == nil {
+		return data
+	}
+	return coerceToSchema(data, schema)
+}
+
+// coerceToSchema converts string values to the integer, number or boolean
+// type their schema declares, descending into objects and arrays. Values that
+// don't parse are left unchanged.
+func coerceToSchema(data interface{}, schema *openapi3.Schema) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for name, prop := range schema.Properties {
+			if value, ok := v[name]; ok && prop != nil && prop.Value != nil {
+				v[name] = coerceToSchema(value, prop.Value)
+			}
+		}
+		for _, sub := range schema.AllOf {
+			if sub != nil && sub.Value != nil {
+				coerceToSchema(v, sub.Value)
+			}
+		}
+		return v
+	case []interface{}:
+		if schema.Items == nil || schema.Items.Value == nil {
+			return v
+		}
+		for i, item := range v {
+			v[i] = coerceToSchema(item, schema.Items.Value)
+		}
+		return v
+	case string:
+		switch schema.Type {
+		case "integer":
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+		case "number":
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				return n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+	}
+	return data
+}
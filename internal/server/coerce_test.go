@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCoerceTestSpec() *openapi3.T {
+	itemSchema := &openapi3.Schema{
+		Type: "object",
+		Properties: openapi3.Schemas{
+			"id":     {Value: &openapi3.Schema{Type: "integer"}},
+			"price":  {Value: &openapi3.Schema{Type: "number"}},
+			"active": {Value: &openapi3.Schema{Type: "boolean"}},
+			"sku":    {Value: &openapi3.Schema{Type: "string"}},
+		},
+	}
+
+	response := func(schema *openapi3.Schema) *openapi3.Responses {
+		responses := openapi3.NewResponses()
+		responses.Set("200", &openapi3.ResponseRef{Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"application/json": &openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: schema}},
+			},
+		}})
+		return responses
+	}
+
+	paths := openapi3.NewPaths()
+	paths.Set("/items", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: response(&openapi3.Schema{
+			Type:  "array",
+			Items: &openapi3.SchemaRef{Value: itemSchema},
+		})},
+	})
+	paths.Set("/items/{id}", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: response(itemSchema)},
+	})
+	return &openapi3.T{Paths: paths}
+}
+
+func TestCoerceTypes(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.CoerceTypes = true
+
+	server := NewServer(createCoerceTestSpec(), cfg)
+	require.NoError(t, server.stateManager.AddResource("items", map[string]interface{}{
+		"id":     "42",
+		"price":  "9.99",
+		"active": "true",
+		"sku":    "007",
+	}))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var item map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+	assert.Equal(t, float64(42), item["id"])
+	assert.Equal(t, 9.99, item["price"])
+	assert.Equal(t, true, item["active"])
+	assert.Equal(t, "007", item["sku"])
+
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var items []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, float64(42), items[0]["id"])
+}
+
+func TestCoerceTypes_Disabled(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createCoerceTestSpec(), createTestConfig(tmpFile.Name()))
+	require.NoError(t, server.stateManager.AddResource("items", map[string]interface{}{"id": "42"}))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var item map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+	assert.Equal(t, "42", item["id"])
+}
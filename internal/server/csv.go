@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// wantsCSV reports whether a collection is requested as CSV, with
+// ?format=csv or an Accept header preferring text/csv to JSON
+func wantsCSV(r *http.Request) bool {
+	if strings.EqualFold(r.URL.Query().Get("format"), "csv") {
+		return true
+	}
+	return negotiateMediaType(r.Header.Get("Accept"), "application/json", "text/csv") == "text/csv"
+}
+
+// writeCSV writes a collection as CSV with a header row. The columns are the
+// union of the items' keys in sorted order, nested objects are flattened into
+// dotted columns such as address.city, and arrays are written as JSON.
+func writeCSV(w http.ResponseWriter, status int, items []interface{}) {
+	rows := make([]map[string]string, len(items))
+	columns := make(map[string]bool)
+	for i, item := range items {
+		rows[i] = make(map[string]string)
+		flattenCSV("", item, rows[i])
+		for column := range rows[i] {
+			columns[column] = true
+		}
+	}
+
+	header := make([]string, 0, len(columns))
+	for column := range columns {
+		header = append(header, column)
+	}
+	sort.Strings(header)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(status)
+	if len(header) == 0 {
+		return
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, column := range header {
+			record[i] = row[column]
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+}
+
+// flattenCSV adds the cells of value to row, under prefix
+func flattenCSV(prefix string, value interface{}, row map[string]string) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		row[prefix] = csvCell(value)
+		return
+	}
+
+	for key, v := range obj {
+		column := key
+		if prefix != "" {
+			column = prefix + "." + key
+		}
+		if nested, isObject := v.(map[string]interface{}); isObject && len(nested) > 0 {
+			flattenCSV(column, nested, row)
+			continue
+		}
+		row[column] = csvCell(v)
+	}
+}
+
+// csvCell formats a value as a CSV cell: scalars as text, null as an empty
+// cell and arrays and empty objects as JSON
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool, int, int64:
+		return fmt.Sprintf("%v", v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVExport(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	handler := NewServer(createTestSpec(), createTestConfig(tmpFile.Name())).createHandler()
+
+	for _, body := range []string{
+		`{"id": "1", "name": "Ada, Countess", "address": {"city": "London", "geo": {"lat": 51.5}}, "tags": ["math", "code"]}`,
+		`{"id": "2", "name": "Grace \"Amazing\"", "age": 85, "active": true}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	for name, setup := range map[string]func(*http.Request){
+		"format parameter": func(r *http.Request) { r.URL.RawQuery = "format=csv" },
+		"accept header":    func(r *http.Request) { r.Header.Set("Accept", "text/csv") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			setup(req)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+			records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{
+				{"active", "address.city", "address.geo.lat", "age", "id", "name", "tags"},
+				{"", "London", "51.5", "", "1", "Ada, Countess", `["math","code"]`},
+				{"true", "", "", "85", "2", `Grace "Amazing"`, ""},
+			}, records)
+		})
+	}
+
+	// JSON stays the default
+	for _, accept := range []string{"", "application/json, text/csv"} {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	}
+}
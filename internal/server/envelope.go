@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+)
+
+// writeData writes a CRUD response body, wrapping it in the configured envelope
+func (s *Server) writeData(w http.ResponseWriter, status int, data interface{}) {
+	envelope := s.cfg.Behavior.Envelope
+	if !envelope.Enabled {
+		writeJSON(w, status, data)
+		return
+	}
+
+	dataKey := envelope.DataKey
+	if dataKey == "" {
+		dataKey = "data"
+	}
+	metaKey := envelope.MetaKey
+	if metaKey == "" {
+		metaKey = "meta"
+	}
+
+	wrapped := map[string]interface{}{
+		dataKey: data,
+	}
+
+	if envelope.Meta {
+		meta := map[string]interface{}{}
+		if items, ok := data.([]interface{}); ok {
+			meta["total"] = len(items)
+			meta["count"] = len(items)
+		}
+		wrapped[metaKey] = meta
+	}
+
+	writeJSON(w, status, wrapped)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope_WrapsCRUDResponses(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Envelope.Enabled = true
+	cfg.Behavior.Envelope.Meta = true
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	for _, name := range []string{"Ada", "Grace"} {
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader([]byte(`{"name":"`+name+`"}`)))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		data, ok := created["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, name, data["name"])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response["data"], 2)
+	assert.Equal(t, map[string]interface{}{"total": float64(2), "count": float64(2)}, response["meta"])
+}
+
+func TestEnvelope_CustomKeys(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Envelope.Enabled = true
+	cfg.Behavior.Envelope.DataKey = "items"
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []interface{}{}, response["items"])
+	assert.NotContains(t, response, "meta")
+}
+
+func TestEnvelope_ErrorsAreNotWrapped(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Envelope.Enabled = true
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/missing", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "not_found", response["code"])
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// errorFormatProblem selects RFC 7807 problem details for error responses
+const errorFormatProblem = "problem"
+
+// problemType describes the RFC 7807 type of an error code
+type problemType struct {
+	uri   string
+	title string
+}
+
+// problemTypes maps error codes to problem types. Codes without an entry use
+// about:blank, whose title is the HTTP status text.
+var problemTypes = map[string]problemType{
+	"not_found":           {"urn:meridian:problem:not-found", "Not Found"},
+	"invalid_json":        {"urn:meridian:problem:invalid-json", "Malformed JSON body"},
+	"missing_id":          {"urn:meridian:problem:missing-id", "Resource ID required"},
+	"invalid_parameter":   {"urn:meridian:problem:invalid-parameter", "Invalid parameter"},
+	"conflict":            {"urn:meridian:problem:conflict", "Unique constraint violated"},
+	"rate_limit_exceeded": {"urn:meridian:problem:rate-limit-exceeded", "Rate limit exceeded"},
+	"simulated_error":     {"urn:meridian:problem:simulated-error", "Simulated error"},
+	"template_error":      {"urn:meridian:problem:template-error", "Response template failed"},
+	"file_error":          {"urn:meridian:problem:file-error", "File response failed"},
+}
+
+// problemMembers are the members defined by RFC 7807, which extension fields
+// can't override
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+// writeError writes an error response in the configured format
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	s.writeErrorWithFields(w, r, status, code, message, nil)
+}
+
+// writeErrorWithFields writes an error response with additional members. By
+// default errors are {"error": message, "code": code}; with errors.format set
+// to problem they are application/problem+json documents, and with
+// behavior.jsonapi JSON:API errors documents.
+func (s *Server) writeErrorWithFields(w http.ResponseWriter, r *http.Request, status int, code, message string, fields map[string]interface{}) {
+	if s.cfg.Behavior.Errors.Format != errorFormatProblem && s.cfg.Behavior.JSONAPI {
+		writeJSONAPIError(w, status, code, message, fields)
+		return
+	}
+
+	if s.cfg.Behavior.Errors.Format != errorFormatProblem {
+		body := map[string]interface{}{
+			"error": message,
+			"code":  code,
+		}
+		for key, value := range fields {
+			body[key] = value
+		}
+		writeJSON(w, status, body)
+		return
+	}
+
+	pt, ok := problemTypes[code]
+	if !ok {
+		pt = problemType{uri: "about:blank", title: http.StatusText(status)}
+	}
+
+	problem := map[string]interface{}{
+		"type":     pt.uri,
+		"title":    pt.title,
+		"status":   status,
+		"detail":   message,
+		"instance": r.URL.RequestURI(),
+		"code":     code,
+	}
+	for key, value := range fields {
+		if !problemMembers[key] {
+			problem[key] = value
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorFormat_Legacy(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createTestSpec(), createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/missing", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]interface{}{"error": "Resource not found", "code": "not_found"}, response)
+}
+
+func TestErrorFormat_Problem(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Errors.Format = "problem"
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/missing?expand=posts", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "urn:meridian:problem:not-found", problem["type"])
+	assert.Equal(t, "Not Found", problem["title"])
+	assert.Equal(t, float64(http.StatusNotFound), problem["status"])
+	assert.Equal(t, "Resource not found", problem["detail"])
+	assert.Equal(t, "/users/missing?expand=posts", problem["instance"])
+	assert.Equal(t, "not_found", problem["code"])
+
+	// Extra fields become extension members
+	req = httptest.NewRequest(http.MethodGet, "/nowhere", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "/nowhere", problem["path"])
+	assert.Equal(t, "Path not found", problem["detail"])
+}
+
+func TestErrorFormat_ProblemUnknownCode(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Errors.Format = "problem"
+	server := NewServer(createTestSpec(), cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	server.writeErrorWithFields(w, req, http.StatusTeapot, "brewing", "Short and stout", map[string]interface{}{
+		"type": "ignored",
+	})
+
+	var problem map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem["type"])
+	assert.Equal(t, "I'm a teapot", problem["title"])
+}
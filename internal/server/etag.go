@@ -0,0 +1,43 @@
+package server
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// resourceETag returns a weak ETag for a stored resource, hashed from its
+// canonical JSON and updated_at timestamp. It stays the same across query
+// parameters, envelopes and formats, which change the representation but not
+// the resource, hence the weak validator.
+func resourceETag(data interface{}, updatedAt string) string {
+	// json.Marshal sorts object keys, so equal resources encode equally
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		encoded = []byte(fmt.Sprintf("%v", data))
+	}
+
+	hash := md5.New()
+	hash.Write(encoded)
+	hash.Write([]byte(updatedAt))
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// etagMatches evaluates an If-None-Match header against an ETag with the weak
+// comparison of RFC 7232: "*" matches anything, and W/ prefixes are ignored
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`"xyz", W/"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`*`, `W/"abc"`))
+	assert.False(t, etagMatches(`W/"xyz"`, `W/"abc"`))
+	assert.False(t, etagMatches(``, `W/"abc"`))
+}
+
+func TestResourceETag(t *testing.T) {
+	for name, caching := range map[string]bool{"without caching": false, "with caching": true} {
+		t.Run(name, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "test-*.db")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+			tmpFile.Close()
+
+			cfg := createTestConfig(tmpFile.Name())
+			if caching {
+				cfg.Behavior.Caching.Enabled = true
+				cfg.Behavior.Caching.UseETag = true
+				cfg.Behavior.Caching.TTL = config.Duration{Duration: 5 * time.Minute}
+			}
+			server := NewServer(createTestSpec(), cfg)
+			server.clock.Freeze(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			handler := server.createHandler()
+
+			do := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, path, strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				for key, value := range header {
+					req.Header.Set(key, value)
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				return w
+			}
+
+			require.Equal(t, http.StatusCreated, do(http.MethodPost, "/users", `{"id": "1", "name": "Ada", "email": "ada@example.com"}`, nil).Code)
+
+			// The ETag doesn't depend on the representation
+			w := do(http.MethodGet, "/users/1", "", nil)
+			require.Equal(t, http.StatusOK, w.Code)
+			etag := w.Header().Get("ETag")
+			assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+			assert.Equal(t, etag, do(http.MethodGet, "/users/1?fields=name", "", nil).Header().Get("ETag"))
+
+			w = do(http.MethodGet, "/users/1?fields=email", "", map[string]string{"If-None-Match": etag})
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Empty(t, w.Body.String())
+
+			// Updates change it, even when the content is the same
+			server.clock.Freeze(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+			require.Equal(t, http.StatusOK, do(http.MethodPut, "/users/1", `{"id": "1", "name": "Ada", "email": "ada@example.com"}`, nil).Code)
+
+			w = do(http.MethodGet, "/users/1", "", map[string]string{"If-None-Match": etag})
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEqual(t, etag, w.Header().Get("ETag"))
+		})
+	}
+}
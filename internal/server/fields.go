@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// fieldTree is a parsed fields parameter. A nil subtree selects the whole
+// value of the field.
+type fieldTree map[string]fieldTree
+
+// parseFields parses a comma-separated list of fields, with dots for nested
+// fields such as address.city. The id field is always selected.
+func parseFields(param string) fieldTree {
+	tree := fieldTree{"id": nil}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		node := tree
+		keys := strings.Split(field, ".")
+		for i, key := range keys {
+			child, exists := node[key]
+			if i == len(keys)-1 {
+				// Selecting a whole field overrides any nested selection
+				node[key] = nil
+				break
+			}
+			if exists && child == nil {
+				// The whole field is already selected
+				break
+			}
+			if child == nil {
+				child = fieldTree{}
+				node[key] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// selectFields projects a response down to the fields requested with the
+// fields query parameter. It returns data unchanged when none are requested.
+func selectFields(r *http.Request, data interface{}) interface{} {
+	param := r.URL.Query().Get("fields")
+	if strings.TrimSpace(param) == "" {
+		return data
+	}
+	return project(data, parseFields(param))
+}
+
+// project keeps the selected fields of objects, applying the selection to
+// each item of arrays
+func project(data interface{}, tree fieldTree) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		projected := make(map[string]interface{}, len(tree))
+		for key, subtree := range tree {
+			value, ok := v[key]
+			if !ok {
+				continue
+			}
+			if subtree == nil {
+				projected[key] = value
+			} else {
+				projected[key] = project(value, subtree)
+			}
+		}
+		return projected
+	case []interface{}:
+		projected := make([]interface{}, len(v))
+		for i, item := range v {
+			projected[i] = project(item, tree)
+		}
+		return projected
+	default:
+		return data
+	}
+}
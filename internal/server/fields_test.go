@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	assert.Equal(t, fieldTree{"id": nil, "name": nil}, parseFields("name"))
+	assert.Equal(t, fieldTree{"id": nil, "name": nil, "address": fieldTree{"city": nil, "zip": nil}}, parseFields(" name, address.city,address.zip,"))
+
+	// Selecting a whole field wins over selecting part of it
+	assert.Equal(t, fieldTree{"id": nil, "address": nil}, parseFields("address.city,address"))
+	assert.Equal(t, fieldTree{"id": nil, "address": nil}, parseFields("address,address.city"))
+}
+
+func TestSelectFields(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createTestSpec(), createTestConfig(tmpFile.Name()))
+	require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{
+		"id":    "1",
+		"name":  "Alice",
+		"email": "alice@example.com",
+		"address": map[string]interface{}{
+			"city":   "Lisbon",
+			"street": "Rua Augusta",
+		},
+		"pets": []interface{}{
+			map[string]interface{}{"name": "Rex", "kind": "dog"},
+		},
+	}))
+	handler := server.createHandler()
+
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("list", func(t *testing.T) {
+		assert.JSONEq(t, `[{"id": "1", "name": "Alice"}]`, get("/users?fields=name"))
+	})
+
+	t.Run("single", func(t *testing.T) {
+		assert.JSONEq(t, `{"id": "1", "email": "alice@example.com"}`, get("/users/1?fields=email,missing"))
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		assert.JSONEq(t, `{"id": "1", "address": {"city": "Lisbon"}, "pets": [{"name": "Rex"}]}`, get("/users/1?fields=address.city,pets.name"))
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		var user map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(get("/users/1")), &user))
+		assert.Len(t, user, 5)
+	})
+}
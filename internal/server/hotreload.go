@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/felipevolpatto/meridian/internal/config"
-	"github.com/felipevolpatto/meridian/internal/generator"
 	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/felipevolpatto/meridian/internal/state"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -23,6 +23,9 @@ type HotReloadServer struct {
 	isRunning  bool
 	stopChan   chan struct{}
 	reloadChan chan struct{}
+
+	// specHeaders are sent when fetching a remote spec, on every reload
+	specHeaders http.Header
 }
 
 // HotReloadConfig configures hot reload behavior
@@ -44,6 +47,12 @@ func NewHotReloadServer(configPath string) *HotReloadServer {
 	}
 }
 
+// SetSpecHeaders sets the headers sent when fetching a remote spec, such as
+// Authorization
+func (hrs *HotReloadServer) SetSpecHeaders(headers http.Header) {
+	hrs.specHeaders = headers
+}
+
 // Start starts the server with hot reload enabled
 func (hrs *HotReloadServer) Start() error {
 	hrs.mu.Lock()
@@ -62,7 +71,7 @@ func (hrs *HotReloadServer) Start() error {
 
 	// Setup file watcher
 	watchFiles := []string{hrs.configPath}
-	if cfg.OpenAPI != "" {
+	if cfg.OpenAPI != "" && !openapi.IsURL(cfg.OpenAPI) {
 		watchFiles = append(watchFiles, cfg.OpenAPI)
 	}
 	if cfg.State.Seed != "" {
@@ -116,7 +125,7 @@ func (hrs *HotReloadServer) serverLoop(cfg *config.Config, spec *openapi3.T) {
 		// Start server in goroutine
 		serverErr := make(chan error, 1)
 		go func() {
-			if err := StartServer(spec, cfg); err != nil {
+			if err := srv.ListenAndServe(); err != nil {
 				serverErr <- err
 			}
 			close(serverErr)
@@ -167,7 +176,7 @@ func (hrs *HotReloadServer) loadConfig() (*config.Config, *openapi3.T, error) {
 		return nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	spec, err := openapi.ParseFile(cfg.OpenAPI)
+	spec, err := openapi.Load(cfg.OpenAPI, hrs.specHeaders)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
 	}
@@ -184,15 +193,12 @@ func (hrs *HotReloadServer) initializeState(cfg *config.Config, spec *openapi3.T
 		Spec:            spec,
 	}
 
+	if err := ApplyGeneratorConfig(cfg); err != nil {
+		return err
+	}
+
 	if cfg.State.AutoSeed.Enabled {
-		initOpts.AutoSeedConfig = generator.AutoSeedConfig{
-			ItemsPerResource: cfg.State.AutoSeed.ItemsPerResource,
-			IncludeResources: cfg.State.AutoSeed.IncludeResources,
-			ExcludeResources: cfg.State.AutoSeed.ExcludeResources,
-		}
-		if initOpts.AutoSeedConfig.ItemsPerResource <= 0 {
-			initOpts.AutoSeedConfig.ItemsPerResource = 5
-		}
+		initOpts.AutoSeedConfig = AutoSeedConfig(cfg)
 	}
 
 	return state.InitializeWithOptions(initOpts)
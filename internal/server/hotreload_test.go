@@ -1,6 +1,8 @@
 package server
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -23,6 +25,39 @@ func TestHotReloadServerCreation(t *testing.T) {
 	}
 }
 
+func TestHotReloadServerSpecHeaders(t *testing.T) {
+	spec := `
+openapi: "3.0.0"
+info:
+  title: Test API
+  version: "1.0"
+paths: {}
+`
+	specServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(spec))
+	}))
+	defer specServer.Close()
+
+	configPath := filepath.Join(t.TempDir(), "meridian.yaml")
+	if err := os.WriteFile(configPath, []byte("openapi: "+specServer.URL+"/openapi.yaml\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	hrs := NewHotReloadServer(configPath)
+	if _, _, err := hrs.loadConfig(); err == nil {
+		t.Error("Expected loading the spec without headers to fail")
+	}
+
+	hrs.SetSpecHeaders(http.Header{"Authorization": []string{"Bearer secret"}})
+	if _, _, err := hrs.loadConfig(); err != nil {
+		t.Errorf("loadConfig() error = %v", err)
+	}
+}
+
 func TestHotReloadServerDoubleStart(t *testing.T) {
 	// Create temp config files
 	tmpDir := t.TempDir()
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+)
+
+// include is a related resource requested with the include query parameter,
+// with the relations to embed in it in turn
+type include struct {
+	name   string
+	nested []string
+}
+
+// parseIncludes parses a comma-separated list of relations, with a dot for a
+// relation of an embedded resource such as customer.address. Embedding is
+// one level deep, so longer chains are ignored.
+func parseIncludes(param string) []include {
+	includes := make([]include, 0)
+	index := make(map[string]int)
+	for _, name := range strings.Split(param, ",") {
+		parts := strings.Split(strings.TrimSpace(name), ".")
+		if parts[0] == "" || len(parts) > 2 {
+			continue
+		}
+
+		i, exists := index[parts[0]]
+		if !exists {
+			i = len(includes)
+			index[parts[0]] = i
+			includes = append(includes, include{name: parts[0]})
+		}
+		if len(parts) == 2 && parts[1] != "" {
+			includes[i].nested = append(includes[i].nested, parts[1])
+		}
+	}
+	return includes
+}
+
+// parseExpand parses a comma-separated list of child resource types
+func parseExpand(param string) []string {
+	names := make([]string, 0)
+	for _, name := range strings.Split(param, ",") {
+		if name = strings.TrimSpace(name); name != "" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// embedIncludes embeds the related resources named by the include query
+// parameter, and the children named by the expand query parameter, into
+// resources of resourceName. It returns data unchanged when neither is
+// given.
+func (s *Server) embedIncludes(r *http.Request, resourceName string, data interface{}) interface{} {
+	query := r.URL.Query()
+	includes := parseIncludes(query.Get("include"))
+	expand := parseExpand(query.Get("expand"))
+	if len(includes) == 0 && len(expand) == 0 {
+		return data
+	}
+
+	e := &embedder{s: s, collections: make(map[string][]interface{})}
+	apply := func(obj map[string]interface{}) {
+		e.embed(obj, resourceName, includes)
+		for _, childResource := range expand {
+			if id, exists := obj["id"]; exists {
+				obj[childResource] = e.children(childResource, resourceName, fmt.Sprintf("%v", id))
+			}
+		}
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		apply(v)
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				apply(obj)
+			}
+		}
+	}
+	return data
+}
+
+// embedder resolves relations against the state, reading each collection
+// at most once per request
+type embedder struct {
+	s           *Server
+	collections map[string][]interface{}
+}
+
+// embed sets each included relation of obj, a resource of resourceName, to
+// the related resource, or to the list of related resources for a one-to-many
+// relation. Relations that can't be resolved are left out.
+func (e *embedder) embed(obj map[string]interface{}, resourceName string, includes []include) {
+	for _, inc := range includes {
+		related, relatedResource, ok := e.resolve(obj, resourceName, inc.name)
+		if !ok {
+			continue
+		}
+
+		nested := make([]include, len(inc.nested))
+		for i, name := range inc.nested {
+			nested[i] = include{name: name}
+		}
+		switch v := related.(type) {
+		case map[string]interface{}:
+			e.embed(v, relatedResource, nested)
+		case []interface{}:
+			for _, item := range v {
+				if child, ok := item.(map[string]interface{}); ok {
+					e.embed(child, relatedResource, nested)
+				}
+			}
+		}
+		obj[inc.name] = related
+	}
+}
+
+// resolve finds the resources a relation refers to. A relation is either a
+// foreign key of obj, such as customer for customer_id, or a collection
+// whose items reference obj, such as orders with a customer_id holding the
+// ID of a customer.
+func (e *embedder) resolve(obj map[string]interface{}, resourceName, name string) (interface{}, string, bool) {
+	singular := generator.Singularize(name)
+	collection := generator.Pluralize(singular)
+
+	for _, field := range []string{singular + "_id", singular + "Id"} {
+		id, exists := obj[field]
+		if !exists || id == nil {
+			continue
+		}
+		for _, item := range e.collection(collection) {
+			if child, ok := item.(map[string]interface{}); ok && fmt.Sprintf("%v", child["id"]) == fmt.Sprintf("%v", id) {
+				return copyObject(child), collection, true
+			}
+		}
+		return nil, "", false
+	}
+
+	id, exists := obj["id"]
+	if !exists || name != collection {
+		return nil, "", false
+	}
+	return e.children(collection, resourceName, fmt.Sprintf("%v", id)), collection, true
+}
+
+// children returns the resources of childResource that reference the
+// parent resource with parentID, matched the same way as the items of a
+// nested collection such as /users/{userId}/posts
+func (e *embedder) children(childResource, parentResource, parentID string) []interface{} {
+	parent := &NestedResourceInfo{
+		IsNested:        true,
+		ParentResource:  parentResource,
+		ParentID:        parentID,
+		ParentIDParam:   generator.Singularize(parentResource) + "Id",
+		ChildResource:   childResource,
+		ForeignKeyField: inferForeignKeyField(parentResource, ""),
+	}
+
+	// Each lookup decodes fresh objects, so they can be modified independently
+	children, _ := e.s.childrenOf(childResource, parent)
+	return children
+}
+
+// collection returns the stored resources of a type
+func (e *embedder) collection(resourceName string) []interface{} {
+	items, cached := e.collections[resourceName]
+	if !cached {
+		items, _ = e.s.stateManager.GetResources(resourceName)
+		e.collections[resourceName] = items
+	}
+	return items
+}
+
+// copyObject returns a shallow copy of obj, so that a resource embedded in
+// several places can be modified independently
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		copied[key] = value
+	}
+	return copied
+}
+
+// copyValue returns a deep copy of decoded JSON data, so that data shared with
+// the spec, such as an example, can be modified without changing the original
+func copyValue(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	}
+	return data
+}
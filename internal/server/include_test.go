@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncludes(t *testing.T) {
+	assert.Equal(t, []include{{name: "customer"}}, parseIncludes("customer"))
+	assert.Equal(t, []include{
+		{name: "customer", nested: []string{"address", "orders"}},
+		{name: "items"},
+	}, parseIncludes(" customer.address, items,customer.orders,,a.b.c"))
+}
+
+func TestParseExpand(t *testing.T) {
+	assert.Equal(t, []string{"posts", "comments"}, parseExpand("posts, comments,,posts"))
+	assert.Empty(t, parseExpand(""))
+}
+
+func TestEmbedIncludes(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	// Orders and customers share the users operations
+	spec := createTestSpec()
+	spec.Paths.Set("/orders", spec.Paths.Value("/users"))
+	spec.Paths.Set("/orders/{id}", spec.Paths.Value("/users/{id}"))
+	spec.Paths.Set("/customers/{id}", spec.Paths.Value("/users/{id}"))
+
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	for resource, items := range map[string][]map[string]interface{}{
+		"addresses": {{"id": "a1", "city": "Lisbon"}},
+		"customers": {
+			{"id": "c1", "name": "Alice", "address_id": "a1"},
+			{"id": "c2", "name": "Bob"},
+		},
+		"orders": {
+			{"id": "o1", "customer_id": "c1", "total": 10},
+			{"id": "o2", "customer_id": "c1", "total": 20},
+			{"id": "o3", "customerId": "c2", "total": 30},
+			{"id": "o4", "customer_id": "missing", "total": 40},
+		},
+	} {
+		for _, item := range items {
+			require.NoError(t, server.stateManager.AddResource(resource, item))
+		}
+	}
+	handler := server.createHandler()
+
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("foreign key", func(t *testing.T) {
+		assert.JSONEq(t, `{
+			"id": "o1", "customer_id": "c1", "total": 10,
+			"customer": {"id": "c1", "name": "Alice", "address_id": "a1"}
+		}`, get("/orders/o1?include=customer"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		assert.JSONEq(t, `[
+			{"id": "o1", "customer_id": "c1", "total": 10, "customer": {"id": "c1", "name": "Alice", "address_id": "a1"}},
+			{"id": "o2", "customer_id": "c1", "total": 20, "customer": {"id": "c1", "name": "Alice", "address_id": "a1"}},
+			{"id": "o3", "customerId": "c2", "total": 30, "customer": {"id": "c2", "name": "Bob"}},
+			{"id": "o4", "customer_id": "missing", "total": 40}
+		]`, get("/orders?include=customer"))
+	})
+
+	t.Run("one to many", func(t *testing.T) {
+		assert.JSONEq(t, `{
+			"id": "c1", "name": "Alice", "address_id": "a1",
+			"address": {"id": "a1", "city": "Lisbon"},
+			"orders": [
+				{"id": "o1", "customer_id": "c1", "total": 10},
+				{"id": "o2", "customer_id": "c1", "total": 20}
+			]
+		}`, get("/customers/c1?include=orders,address"))
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		assert.JSONEq(t, `{
+			"id": "o1", "customer_id": "c1", "total": 10,
+			"customer": {
+				"id": "c1", "name": "Alice", "address_id": "a1",
+				"address": {"id": "a1", "city": "Lisbon"},
+				"orders": [
+					{"id": "o1", "customer_id": "c1", "total": 10},
+					{"id": "o2", "customer_id": "c1", "total": 20}
+				]
+			}
+		}`, get("/orders/o1?include=customer.address,customer.orders"))
+	})
+
+	t.Run("with fields", func(t *testing.T) {
+		assert.JSONEq(t, `{"id": "o1", "customer": {"name": "Alice"}}`, get("/orders/o1?include=customer&fields=customer.name"))
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		assert.JSONEq(t, `{
+			"id": "c2", "name": "Bob",
+			"orders": [{"id": "o3", "customerId": "c2", "total": 30}]
+		}`, get("/customers/c2?expand=orders"))
+		assert.JSONEq(t, `{"id": "c2", "name": "Bob", "invoices": []}`, get("/customers/c2?expand=invoices"))
+	})
+
+	t.Run("unknown relation", func(t *testing.T) {
+		assert.JSONEq(t, `{"id": "o1", "customer_id": "c1", "total": 10}`, get("/orders/o1?include=warehouse"))
+	})
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Kinds of spec violations that can be injected into responses
+const (
+	invalidWrongType       = "wrong_type"
+	invalidMissingRequired = "missing_required"
+	invalidOutOfRange      = "out_of_range"
+)
+
+var invalidKinds = []string{invalidWrongType, invalidMissingRequired, invalidOutOfRange}
+
+// shouldInjectInvalid reports whether the response to a request should be
+// made to violate the spec
+func (s *Server) shouldInjectInvalid(r *http.Request) bool {
+	inject := s.cfg.Behavior.InjectInvalid
+	if !inject.Enabled || rand.Float64() >= inject.Rate {
+		return false
+	}
+	if len(inject.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range inject.Paths {
+		if matched, _ := path.Match(pattern, r.URL.Path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// serveInvalid records the response of next and, when it is a successful JSON
+// response, rewrites the body so that it violates the operation's response
+// schema. The applied violation is reported in the X-Meridian-Invalid header.
+func (s *Server) serveInvalid(w http.ResponseWriter, op *openapi3.Operation, next func(w http.ResponseWriter)) {
+	recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	next(recorder)
+
+	body := recorder.body
+	if recorder.statusCode >= 200 && recorder.statusCode < 300 {
+		if schema := responseSchema(op, recorder.statusCode); schema != nil {
+			var data interface{}
+			if err := json.Unmarshal(body, &data); err == nil {
+				if invalid, violation, ok := injectInvalid(data, schema, s.cfg.Behavior.InjectInvalid.Kinds); ok {
+					if encoded, err := json.Marshal(invalid); err == nil {
+						body = append(encoded, '\n')
+						w.Header().Set("X-Meridian-Invalid", violation)
+						w.Header().Del("Content-Length")
+					}
+				}
+			}
+		}
+	}
+
+	w.WriteHeader(recorder.statusCode)
+	w.Write(body)
+}
+
+// responseSchema returns the JSON schema of an operation's response
+func responseSchema(op *openapi3.Operation, statusCode int) *openapi3.Schema {
+	if op.Responses == nil {
+		return nil
+	}
+	resp := op.Responses.Status(statusCode)
+	if resp == nil {
+		resp = op.Responses.Default()
+	}
+	if resp == nil || resp.Value == nil {
+		return nil
+	}
+	mt := resp.Value.Content.Get("application/json")
+	if mt == nil || mt.Schema == nil {
+		return nil
+	}
+	return mt.Schema.Value
+}
+
+// injectInvalid applies one of the given kinds of violation (all kinds when
+// empty) to data. It returns the modified data and a description of the
+// violation, or false when none of the kinds applies to the schema.
+func injectInvalid(data interface{}, schema *openapi3.Schema, kinds []string) (interface{}, string, bool) {
+	if len(kinds) == 0 {
+		kinds = invalidKinds
+	}
+
+	// Lists are made invalid through their first item
+	if items, ok := data.([]interface{}); ok && schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil && len(items) > 0 {
+		item, violation, ok := injectInvalid(items[0], schema.Items.Value, kinds)
+		if ok {
+			items[0] = item
+			return items, "[0]." + violation, true
+		}
+	}
+
+	for _, i := range rand.Perm(len(kinds)) {
+		switch kinds[i] {
+		case invalidWrongType:
+			if obj, ok := data.(map[string]interface{}); ok {
+				for _, name := range sortedFields(obj) {
+					if prop := schema.Properties[name]; prop != nil && prop.Value != nil && prop.Value.Type != "" {
+						obj[name] = wrongTypeValue(prop.Value.Type)
+						return obj, name + " " + invalidWrongType, true
+					}
+				}
+			}
+			if schema.Type != "" {
+				return wrongTypeValue(schema.Type), invalidWrongType, true
+			}
+		case invalidMissingRequired:
+			if obj, ok := data.(map[string]interface{}); ok && len(schema.Required) > 0 {
+				name := schema.Required[rand.Intn(len(schema.Required))]
+				delete(obj, name)
+				return obj, name + " " + invalidMissingRequired, true
+			}
+		case invalidOutOfRange:
+			if obj, ok := data.(map[string]interface{}); ok {
+				for _, name := range sortedFields(schema.Properties) {
+					prop := schema.Properties[name]
+					if prop == nil || prop.Value == nil {
+						continue
+					}
+					if value, ok := outOfRangeValue(prop.Value); ok {
+						obj[name] = value
+						return obj, name + " " + invalidOutOfRange, true
+					}
+				}
+			}
+		}
+	}
+	return data, "", false
+}
+
+// wrongTypeValue returns a value that doesn't have the given schema type
+func wrongTypeValue(schemaType string) interface{} {
+	if schemaType == "string" {
+		return 12345
+	}
+	return "invalid"
+}
+
+// outOfRangeValue returns a value outside the bounds of a numeric or string
+// schema
+func outOfRangeValue(schema *openapi3.Schema) (interface{}, bool) {
+	switch schema.Type {
+	case "integer", "number":
+		if schema.Max != nil {
+			return *schema.Max + 1, true
+		}
+		if schema.Min != nil {
+			return *schema.Min - 1, true
+		}
+	case "string":
+		if schema.MaxLength != nil {
+			b := make([]byte, *schema.MaxLength+1)
+			for i := range b {
+				b[i] = 'x'
+			}
+			return string(b), true
+		}
+		if len(schema.Enum) > 0 {
+			return "not-in-enum-" + strconv.Itoa(rand.Intn(1000)), true
+		}
+	}
+	return nil, false
+}
+
+// sortedFields returns the keys of a map in order so that the field picked
+// for a violation is stable
+func sortedFields[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
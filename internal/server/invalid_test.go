@@ -0,0 +1,114 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/felipevolpatto/meridian/internal/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createInvalidTestSpec() *openapi3.T {
+	stringSchema := &openapi3.Schema{}
+	stringSchema.Type = "string"
+
+	ageSchema := &openapi3.Schema{}
+	ageSchema.Type = "integer"
+	ageSchema.Min = openapi3.Float64Ptr(0)
+	ageSchema.Max = openapi3.Float64Ptr(150)
+
+	userSchema := &openapi3.Schema{}
+	userSchema.Type = "object"
+	userSchema.Required = []string{"id", "name"}
+	userSchema.Properties = map[string]*openapi3.SchemaRef{
+		"id":   {Value: stringSchema},
+		"name": {Value: stringSchema},
+		"age":  {Value: ageSchema},
+	}
+
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"application/json": &openapi3.MediaType{
+					Schema: &openapi3.SchemaRef{Value: userSchema},
+				},
+			},
+		},
+	})
+
+	paths := openapi3.NewPaths()
+	paths.Set("/users/{id}", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "getUser",
+			Responses:   responses,
+			Parameters: openapi3.Parameters{
+				{Value: openapi3.NewPathParameter("id").WithSchema(stringSchema)},
+			},
+		},
+	})
+
+	return &openapi3.T{Paths: paths}
+}
+
+func TestInjectInvalid_FailsValidation(t *testing.T) {
+	for _, kind := range invalidKinds {
+		t.Run(kind, func(t *testing.T) {
+			tmpFile, err := os.CreateTemp("", "test-*.db")
+			require.NoError(t, err)
+			defer os.Remove(tmpFile.Name())
+			tmpFile.Close()
+
+			spec := createInvalidTestSpec()
+			cfg := createTestConfig(tmpFile.Name())
+			cfg.Behavior.InjectInvalid.Enabled = true
+			cfg.Behavior.InjectInvalid.Rate = 1.0
+			cfg.Behavior.InjectInvalid.Kinds = []string{kind}
+
+			server := NewServer(spec, cfg)
+			require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{
+				"id":   "u1",
+				"name": "Alice",
+				"age":  30,
+			}))
+			handler := server.createHandler()
+
+			req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("X-Meridian-Invalid"), kind)
+
+			errs := validation.NewRequestValidator(spec).ValidateResponse(http.MethodGet, "/users/u1", w.Code, w.Header(), w.Body.Bytes()).Errors()
+			assert.NotEmpty(t, errs)
+		})
+	}
+}
+
+func TestInjectInvalid_PathFilter(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.InjectInvalid.Enabled = true
+	cfg.Behavior.InjectInvalid.Rate = 1.0
+	cfg.Behavior.InjectInvalid.Paths = []string{"/orders/*"}
+
+	server := NewServer(createInvalidTestSpec(), cfg)
+	require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{"id": "u1", "name": "Alice"}))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Meridian-Invalid"))
+}
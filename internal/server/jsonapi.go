@@ -0,0 +1,260 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// jsonAPIMediaType is the media type of JSON:API documents
+const jsonAPIMediaType = "application/vnd.api+json"
+
+// foreignKeyField matches fields holding the ID of a related resource, such
+// as customer_id or customerId
+var foreignKeyField = regexp.MustCompile(`^(.+?)(_id|Id)$`)
+
+// jsonAPIDocument collects the resource objects of a compound document
+type jsonAPIDocument struct {
+	included []interface{}
+	seen     map[string]bool
+}
+
+// writeJSONAPI writes CRUD data as a JSON:API document. Resources become
+// resource objects with their foreign keys as relationships, and the
+// relations named by the include parameter are added to included.
+func writeJSONAPI(w http.ResponseWriter, r *http.Request, resourceName string, status int, data interface{}) {
+	includes := parseIncludes(r.URL.Query().Get("include"))
+	doc := &jsonAPIDocument{included: make([]interface{}, 0), seen: make(map[string]bool)}
+
+	// Primary resources are never repeated in included
+	primary := []interface{}{data}
+	if items, ok := data.([]interface{}); ok {
+		primary = items
+	}
+	for _, item := range primary {
+		if obj, ok := item.(map[string]interface{}); ok {
+			doc.seen[resourceKey(resourceName, obj["id"])] = true
+		}
+	}
+
+	var body map[string]interface{}
+	if items, ok := data.([]interface{}); ok {
+		resources := make([]interface{}, len(items))
+		for i, item := range items {
+			resources[i] = doc.resource(item, resourceName, includes)
+		}
+		body = map[string]interface{}{"data": resources}
+	} else {
+		body = map[string]interface{}{"data": doc.resource(data, resourceName, includes)}
+	}
+	if len(includes) > 0 {
+		body["included"] = doc.included
+	}
+
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// resource converts a stored resource into a resource object. Embedded
+// includes and foreign keys become relationships, every other field except
+// id is an attribute.
+func (d *jsonAPIDocument) resource(item interface{}, resourceType string, includes []include) interface{} {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return item
+	}
+
+	attributes := make(map[string]interface{}, len(obj))
+	for key, value := range obj {
+		if key != "id" {
+			attributes[key] = value
+		}
+	}
+	relationships := make(map[string]interface{})
+
+	for key, value := range obj {
+		match := foreignKeyField.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		delete(attributes, key)
+		if value == nil {
+			relationships[match[1]] = map[string]interface{}{"data": nil}
+			continue
+		}
+		relationships[match[1]] = map[string]interface{}{
+			"data": identifier(relatedType(match[1]), value),
+		}
+	}
+
+	for _, inc := range includes {
+		related, exists := obj[inc.name]
+		if !exists {
+			continue
+		}
+		delete(attributes, inc.name)
+
+		nested := make([]include, len(inc.nested))
+		for i, name := range inc.nested {
+			nested[i] = include{name: name}
+		}
+		typ := relatedType(inc.name)
+
+		switch v := related.(type) {
+		case map[string]interface{}:
+			relationships[inc.name] = map[string]interface{}{"data": identifier(typ, v["id"])}
+			d.include(v, typ, nested)
+		case []interface{}:
+			linkage := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				if childObj, ok := child.(map[string]interface{}); ok {
+					linkage = append(linkage, identifier(typ, childObj["id"]))
+					d.include(childObj, typ, nested)
+				}
+			}
+			relationships[inc.name] = map[string]interface{}{"data": linkage}
+		}
+	}
+
+	resource := map[string]interface{}{
+		"type":       resourceType,
+		"attributes": attributes,
+	}
+	if id, exists := obj["id"]; exists {
+		resource["id"] = fmt.Sprintf("%v", id)
+	}
+	if len(relationships) > 0 {
+		resource["relationships"] = relationships
+	}
+	return resource
+}
+
+// include adds a related resource to the document, once
+func (d *jsonAPIDocument) include(obj map[string]interface{}, resourceType string, includes []include) {
+	key := resourceKey(resourceType, obj["id"])
+	if d.seen[key] {
+		return
+	}
+	d.seen[key] = true
+
+	// Reserve the slot first so resources precede the ones they include
+	i := len(d.included)
+	d.included = append(d.included, nil)
+	d.included[i] = d.resource(obj, resourceType, includes)
+}
+
+// resourceKey identifies a resource within a document
+func resourceKey(resourceType string, id interface{}) string {
+	return resourceType + "/" + fmt.Sprintf("%v", id)
+}
+
+// identifier builds a resource identifier object
+func identifier(resourceType string, id interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": resourceType, "id": fmt.Sprintf("%v", id)}
+}
+
+// relatedType returns the resource type a relation refers to, such as
+// customers for customer
+func relatedType(name string) string {
+	return generator.Pluralize(generator.Singularize(name))
+}
+
+// fromJSONAPI reads the resource object of a JSON:API request body as a
+// resource. Attributes become fields, the id is kept when given, and to-one
+// relationships are stored in the foreign key field foreignKey names, such as
+// customer_id for customer.
+func fromJSONAPI(body map[string]interface{}, foreignKey func(relationship string) string) (map[string]interface{}, error) {
+	data, ok := body["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing data object")
+	}
+
+	resource := make(map[string]interface{})
+	if attributes, ok := data["attributes"].(map[string]interface{}); ok {
+		for key, value := range attributes {
+			resource[key] = value
+		}
+	}
+	if id, exists := data["id"]; exists && id != nil && id != "" {
+		resource["id"] = id
+	}
+
+	if relationships, ok := data["relationships"].(map[string]interface{}); ok {
+		for name, rel := range relationships {
+			relObj, ok := rel.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			linkage, exists := relObj["data"]
+			if !exists {
+				continue
+			}
+			switch v := linkage.(type) {
+			case nil:
+				resource[foreignKey(name)] = nil
+			case map[string]interface{}:
+				resource[foreignKey(name)] = v["id"]
+			}
+		}
+	}
+
+	return resource, nil
+}
+
+// foreignKeyFor returns the field a to-one relationship is stored in: the
+// spelling of its foreign key the existing resource uses or the schema
+// declares, such as customerId, and customer_id otherwise
+func foreignKeyFor(relationship string, existing map[string]interface{}, schema *openapi3.Schema) string {
+	candidates := []string{relationship + "_id", relationship + "Id"}
+	for _, key := range candidates {
+		if _, ok := existing[key]; ok {
+			return key
+		}
+	}
+	if schema != nil {
+		for _, key := range candidates {
+			if _, ok := schema.Properties[key]; ok {
+				return key
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// jsonAPIRequestSchema returns the schema of an operation's JSON:API or JSON
+// request body, if it has one
+func jsonAPIRequestSchema(op *openapi3.Operation) *openapi3.Schema {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil
+	}
+	for _, mediaType := range []string{jsonAPIMediaType, "application/json"} {
+		if mt := op.RequestBody.Value.Content.Get(mediaType); mt != nil && mt.Schema != nil {
+			return mt.Schema.Value
+		}
+	}
+	return nil
+}
+
+// writeJSONAPIError writes an error as a JSON:API errors document, with
+// additional members under meta
+func writeJSONAPIError(w http.ResponseWriter, status int, code, message string, fields map[string]interface{}) {
+	errObj := map[string]interface{}{
+		"status": strconv.Itoa(status),
+		"code":   code,
+		"title":  http.StatusText(status),
+		"detail": message,
+	}
+	if len(fields) > 0 {
+		errObj["meta"] = fields
+	}
+
+	w.Header().Set("Content-Type", jsonAPIMediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": []interface{}{errObj}})
+}
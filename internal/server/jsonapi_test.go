@@ -0,0 +1,152 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONAPI(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	// Orders and customers share the users operations
+	spec := createTestSpec()
+	spec.Paths.Set("/orders", spec.Paths.Value("/users"))
+	spec.Paths.Set("/orders/{id}", spec.Paths.Value("/users/{id}"))
+	spec.Paths.Set("/customers/{id}", spec.Paths.Value("/users/{id}"))
+	spec.Paths.Set("/invoices/{id}", spec.Paths.Value("/users/{id}"))
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.JSONAPI = true
+	server := NewServer(spec, cfg)
+	for resource, items := range map[string][]map[string]interface{}{
+		"addresses": {{"id": "a1", "city": "Lisbon"}},
+		"customers": {{"id": "c1", "name": "Alice", "address_id": "a1"}, {"id": "c2", "name": "Bob"}},
+		"invoices":  {{"id": "i1", "total": 5, "customerId": "c1"}},
+	} {
+		for _, item := range items {
+			require.NoError(t, server.stateManager.AddResource(resource, item))
+		}
+	}
+	handler := server.createHandler()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", jsonAPIMediaType)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("create", func(t *testing.T) {
+		w := do(http.MethodPost, "/orders", `{"data": {
+			"type": "orders", "id": "o1",
+			"attributes": {"total": 10},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+		}}`)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.Equal(t, jsonAPIMediaType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"data": {
+			"type": "orders", "id": "o1",
+			"attributes": {"total": 10},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+		}}`, w.Body.String())
+
+		// The relationship is stored as a foreign key
+		stored, err := server.stateManager.GetResource("orders", "o1")
+		require.NoError(t, err)
+		assert.Equal(t, "c1", stored.(map[string]interface{})["customer_id"])
+	})
+
+	t.Run("compound document", func(t *testing.T) {
+		w := do(http.MethodGet, "/orders?include=customer.address", "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"data": [{
+				"type": "orders", "id": "o1",
+				"attributes": {"total": 10},
+				"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+			}],
+			"included": [{
+				"type": "customers", "id": "c1",
+				"attributes": {"name": "Alice"},
+				"relationships": {"address": {"data": {"type": "addresses", "id": "a1"}}}
+			}, {
+				"type": "addresses", "id": "a1",
+				"attributes": {"city": "Lisbon"}
+			}]
+		}`, w.Body.String())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		w := do(http.MethodPut, "/orders/o1", `{"data": {
+			"type": "orders", "id": "o1",
+			"attributes": {"total": 15},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+		}}`)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `{"data": {
+			"type": "orders", "id": "o1",
+			"attributes": {"total": 15},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+		}}`, w.Body.String())
+	})
+
+	t.Run("camelCase foreign keys", func(t *testing.T) {
+		w := do(http.MethodGet, "/invoices/i1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data": {
+			"type": "invoices", "id": "i1",
+			"attributes": {"total": 5},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c1"}}}
+		}}`, w.Body.String())
+
+		// Writes go back to the field the relationship was read from
+		w = do(http.MethodPut, "/invoices/i1", `{"data": {
+			"type": "invoices", "id": "i1",
+			"attributes": {"total": 5},
+			"relationships": {"customer": {"data": {"type": "customers", "id": "c2"}}}
+		}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		stored, err := server.stateManager.GetResource("invoices", "i1")
+		require.NoError(t, err)
+		assert.Equal(t, "c2", stored.(map[string]interface{})["customerId"])
+		assert.NotContains(t, stored.(map[string]interface{}), "customer_id")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		w := do(http.MethodGet, "/orders/missing", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, jsonAPIMediaType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"errors": [{
+			"status": "404", "code": "not_found", "title": "Not Found", "detail": "Resource not found"
+		}]}`, w.Body.String())
+
+		w = do(http.MethodPost, "/orders", `{"total": 20}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestForeignKeyFor(t *testing.T) {
+	camel := openapi3.NewObjectSchema().WithProperty("customerId", openapi3.NewStringSchema())
+
+	assert.Equal(t, "customer_id", foreignKeyFor("customer", nil, nil))
+	assert.Equal(t, "customerId", foreignKeyFor("customer", nil, camel))
+	assert.Equal(t, "customerId", foreignKeyFor("customer", map[string]interface{}{"customerId": "c1"}, nil))
+
+	// The stored resource wins over the schema
+	assert.Equal(t, "customer_id", foreignKeyFor("customer", map[string]interface{}{"customer_id": "c1"}, camel))
+}
@@ -1,25 +1,63 @@
 package server
 
 import (
+	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"math/rand"
+	"mime"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Rate limiting algorithms.
+//
+// fixed counts requests in discrete windows. It is the cheapest (one counter
+// per client) but lets a client send up to twice the limit across a window
+// boundary.
+//
+// sliding keeps a log of request timestamps and counts those in the trailing
+// window. It is exact, but memory grows with the limit: up to rate timestamps
+// per client.
+//
+// token_bucket refills tokens continuously and allows bursts up to a
+// configurable capacity. It uses constant memory per client and smooths
+// traffic, but the reset time is an estimate of when the bucket is full again.
+const (
+	rateLimitFixed       = "fixed"
+	rateLimitSliding     = "sliding"
+	rateLimitTokenBucket = "token_bucket"
 )
 
 type rateLimiter struct {
-	mu       sync.RWMutex
-	requests map[string]*clientRequests
-	rate     int
-	window   time.Duration
+	mu        sync.Mutex
+	algorithm string
+	rate      int
+	window    time.Duration
+	burst     int
+	now       func() time.Time
+	lastSweep time.Time
+
+	buckets  map[string]*tokenBucket
+	counters map[string]*clientRequests
+	logs     map[string][]time.Time
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
 }
 
 type clientRequests struct {
@@ -27,12 +65,23 @@ type clientRequests struct {
 	resetTime time.Time
 }
 
-func newRateLimiter(rateStr string) *rateLimiter {
+func newRateLimiter(algorithm, rateStr string, burst int) *rateLimiter {
 	rate, window := parseRate(rateStr)
+	if burst <= 0 {
+		burst = rate
+	}
+	if algorithm == "" {
+		algorithm = rateLimitTokenBucket
+	}
 	return &rateLimiter{
-		requests: make(map[string]*clientRequests),
-		rate:     rate,
-		window:   window,
+		algorithm: algorithm,
+		rate:      rate,
+		window:    window,
+		burst:     burst,
+		now:       time.Now,
+		buckets:   make(map[string]*tokenBucket),
+		counters:  make(map[string]*clientRequests),
+		logs:      make(map[string][]time.Time),
 	}
 }
 
@@ -43,7 +92,7 @@ func parseRate(rateStr string) (int, time.Duration) {
 	}
 
 	rate, err := strconv.Atoi(parts[0])
-	if err != nil {
+	if err != nil || rate <= 0 {
 		rate = 100
 	}
 
@@ -62,19 +111,32 @@ func parseRate(rateStr string) (int, time.Duration) {
 	return rate, window
 }
 
+// allow records a request for the client. It returns whether the request is
+// allowed, how many requests are left, and the time the limit resets.
 func (rl *rateLimiter) allow(clientIP string) (bool, int, time.Time) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	now := time.Now()
+	now := rl.now()
+	rl.sweep(now)
 
-	client, exists := rl.requests[clientIP]
-	if !exists || now.After(client.resetTime) {
-		rl.requests[clientIP] = &clientRequests{
-			count:     1,
-			resetTime: now.Add(rl.window),
-		}
-		return true, rl.rate - 1, now.Add(rl.window)
+	switch rl.algorithm {
+	case rateLimitFixed:
+		return rl.allowFixed(clientIP, now)
+	case rateLimitSliding:
+		return rl.allowSliding(clientIP, now)
+	default:
+		return rl.allowTokenBucket(clientIP, now)
+	}
+}
+
+// allowFixed counts requests in the current window; the reset time is the end
+// of that window.
+func (rl *rateLimiter) allowFixed(clientIP string, now time.Time) (bool, int, time.Time) {
+	client, exists := rl.counters[clientIP]
+	if !exists || !now.Before(client.resetTime) {
+		client = &clientRequests{resetTime: now.Add(rl.window)}
+		rl.counters[clientIP] = client
 	}
 
 	if client.count >= rl.rate {
@@ -85,8 +147,120 @@ func (rl *rateLimiter) allow(clientIP string) (bool, int, time.Time) {
 	return true, rl.rate - client.count, client.resetTime
 }
 
+// allowSliding counts the requests in the trailing window; the reset time is
+// when the oldest of them ages out and frees a slot.
+func (rl *rateLimiter) allowSliding(clientIP string, now time.Time) (bool, int, time.Time) {
+	log := pruneTimestamps(rl.logs[clientIP], now.Add(-rl.window))
+
+	if len(log) >= rl.rate {
+		rl.logs[clientIP] = log
+		return false, 0, log[0].Add(rl.window)
+	}
+
+	log = append(log, now)
+	rl.logs[clientIP] = log
+	return true, rl.rate - len(log), log[0].Add(rl.window)
+}
+
+// allowTokenBucket takes a token from the client's bucket; the reset time is
+// when the bucket is full again if allowed, or when the next token arrives if not.
+func (rl *rateLimiter) allowTokenBucket(clientIP string, now time.Time) (bool, int, time.Time) {
+	perToken := rl.window / time.Duration(rl.rate)
+
+	bucket, exists := rl.buckets[clientIP]
+	if !exists {
+		bucket = &tokenBucket{
+			tokens:     float64(rl.burst),
+			lastRefill: now,
+		}
+		rl.buckets[clientIP] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastRefill)
+	if elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() / perToken.Seconds()
+		if bucket.tokens > float64(rl.burst) {
+			bucket.tokens = float64(rl.burst)
+		}
+		bucket.lastRefill = now
+	}
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) * float64(perToken))
+		return false, 0, now.Add(wait)
+	}
+
+	bucket.tokens--
+	untilFull := time.Duration((float64(rl.burst) - bucket.tokens) * float64(perToken))
+	return true, int(bucket.tokens), now.Add(untilFull)
+}
+
+// sweep drops per-client state that no longer affects any decision, at most
+// once per window, so idle clients don't accumulate.
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.window {
+		return
+	}
+	rl.lastSweep = now
+
+	for client, entry := range rl.counters {
+		if !now.Before(entry.resetTime) {
+			delete(rl.counters, client)
+		}
+	}
+
+	cutoff := now.Add(-rl.window)
+	for client, log := range rl.logs {
+		if log = pruneTimestamps(log, cutoff); len(log) == 0 {
+			delete(rl.logs, client)
+		} else {
+			rl.logs[client] = log
+		}
+	}
+
+	for client, bucket := range rl.buckets {
+		// A bucket idle for a full window has refilled completely
+		if now.Sub(bucket.lastRefill) >= rl.window {
+			delete(rl.buckets, client)
+		}
+	}
+}
+
+// pruneTimestamps removes timestamps at or before cutoff from a sorted log
+func pruneTimestamps(log []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(log) && !log[i].After(cutoff) {
+		i++
+	}
+	if i == 0 {
+		return log
+	}
+	return append(log[:0], log[i:]...)
+}
+
+// retryAfterSeconds rounds the wait until resetTime up to whole seconds
+func retryAfterSeconds(resetTime time.Time) int64 {
+	wait := time.Until(resetTime)
+	if wait <= 0 {
+		return 0
+	}
+	return int64((wait + time.Second - 1) / time.Second)
+}
+
+// setRetryAfter sets the Retry-After header in the configured format, either
+// delta-seconds or the HTTP date at which to retry
+func (s *Server) setRetryAfter(w http.ResponseWriter, seconds int64) {
+	if s.cfg.Behavior.RateLimit.RetryAfterFormat == "http-date" {
+		retryAt := time.Now().Add(time.Duration(seconds) * time.Second)
+		w.Header().Set("Retry-After", retryAt.UTC().Format(http.TimeFormat))
+		return
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
 func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
-	limiter := newRateLimiter(s.cfg.Behavior.RateLimit.Rate)
+	rl := s.cfg.Behavior.RateLimit
+	limiter := newRateLimiter(rl.Algorithm, rl.Rate, rl.Burst)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		clientIP := r.RemoteAddr
@@ -107,13 +281,10 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
 
 		if !allowed {
-			w.Header().Set("Retry-After", strconv.FormatInt(int64(time.Until(resetTime).Seconds()), 10))
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Rate limit exceeded",
-				"code":  "rate_limit_exceeded",
-				"retry_after": int64(time.Until(resetTime).Seconds()),
+			retryAfter := retryAfterSeconds(resetTime)
+			s.setRetryAfter(w, retryAfter)
+			s.writeErrorWithFields(w, r, http.StatusTooManyRequests, "rate_limit_exceeded", "Rate limit exceeded", map[string]interface{}{
+				"retry_after": retryAfter,
 			})
 			return
 		}
@@ -122,9 +293,25 @@ func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// Simulated network failure modes
+const (
+	networkReset   = "reset"
+	networkTimeout = "timeout"
+)
+
+// defaultNetworkTimeout is how long a simulated timeout holds a request
+const defaultNetworkTimeout = 30 * time.Second
+
 func (s *Server) errorSimulationMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if rand.Float64() < s.cfg.Behavior.Errors.Rate {
+		network := s.cfg.Behavior.Errors.Network
+		if network.Enabled && rand.Float64() < network.Rate {
+			if s.simulateNetworkFailure(w, r) {
+				return
+			}
+		}
+
+		if s.cfg.Behavior.Errors.Enabled && rand.Float64() < s.cfg.Behavior.Errors.Rate {
 			statusCode := http.StatusInternalServerError
 			if len(s.cfg.Behavior.Errors.StatusCodes) > 0 {
 				statusCode = s.cfg.Behavior.Errors.StatusCodes[rand.Intn(len(s.cfg.Behavior.Errors.StatusCodes))]
@@ -135,11 +322,7 @@ func (s *Server) errorSimulationMiddleware(next http.Handler) http.Handler {
 				errorType = s.cfg.Behavior.Errors.Types[rand.Intn(len(s.cfg.Behavior.Errors.Types))]
 			}
 
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(statusCode)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error":     fmt.Sprintf("Simulated %s error", errorType),
-				"code":      "simulated_error",
+			s.writeErrorWithFields(w, r, statusCode, "simulated_error", fmt.Sprintf("Simulated %s error", errorType), map[string]interface{}{
 				"type":      errorType,
 				"simulated": true,
 			})
@@ -150,6 +333,105 @@ func (s *Server) errorSimulationMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// simulateNetworkFailure resets the connection or holds the request without
+// responding. Both need to take over the connection, so it reports false when
+// the ResponseWriter doesn't support http.Hijacker, as with HTTP/2.
+func (s *Server) simulateNetworkFailure(w http.ResponseWriter, r *http.Request) bool {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		log.Printf("Cannot simulate a network failure for %s %s: connection can't be hijacked", r.Method, r.URL.Path)
+		return false
+	}
+
+	network := s.cfg.Behavior.Errors.Network
+	modes := network.Modes
+	if len(modes) == 0 {
+		modes = []string{networkReset, networkTimeout}
+	}
+
+	if modes[rand.Intn(len(modes))] == networkTimeout {
+		timeout := network.Timeout.Duration
+		if timeout <= 0 {
+			timeout = defaultNetworkTimeout
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+		}
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("Cannot simulate a network failure for %s %s: %v", r.Method, r.URL.Path, err)
+		return false
+	}
+
+	// Without lingering, closing a TCP connection sends a reset rather than
+	// an orderly shutdown
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+	return true
+}
+
+// responseValidationMiddleware flags responses whose Content-Type isn't one
+// of the media types the operation declares for the status code. Mismatches
+// are logged and reported in a header, and the non-fatal warnings of
+// validating the response, such as unknown formats, are added to the
+// warnings header. The response itself is sent unchanged.
+func (s *Server) responseValidationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/_meridian/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		path, ok := s.stripBasePath(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		pathItem, _ := s.matchPath(path)
+		if pathItem == nil || pathItem.GetOperation(r.Method) == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The body is held back so its findings can still be sent as headers
+		recorder := &responseRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(recorder, r)
+
+		if w.Header().Get("Content-Type") == "" && len(recorder.body) > 0 {
+			w.Header().Set("Content-Type", http.DetectContentType(recorder.body))
+		}
+
+		errs := s.validator.ValidateResponseContentType(pathItem.GetOperation(r.Method), recorder.statusCode, w.Header().Get("Content-Type"))
+		if len(errs) > 0 {
+			log.Printf("Response validation failed for %s %s: %s", r.Method, r.URL.Path, errs.Error())
+			w.Header().Set("X-Meridian-Validation-Errors", errs.Error())
+		}
+
+		warnings := s.validator.ValidateResponse(r.Method, path, recorder.statusCode, w.Header(), recorder.body).Warnings()
+		if len(warnings) > 0 {
+			if existing := w.Header().Get("X-Meridian-Validation-Warnings"); existing != "" {
+				w.Header().Set("X-Meridian-Validation-Warnings", existing+"; "+warnings.Error())
+			} else {
+				w.Header().Set("X-Meridian-Validation-Warnings", warnings.Error())
+			}
+		}
+
+		w.WriteHeader(recorder.statusCode)
+		w.Write(recorder.body)
+	})
+}
+
 type responseRecorder struct {
 	http.ResponseWriter
 	statusCode int
@@ -169,43 +451,61 @@ func (s *Server) cachingMiddleware(next http.Handler) http.Handler {
 	cache := &sync.Map{}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, _ := s.stripBasePath(r.URL.Path)
+		resourceName := cacheResourceName(path)
+
+		// The event stream never completes, so it can't be cached
+		if r.URL.Path == eventsPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		if r.Method != http.MethodGet {
 			next.ServeHTTP(w, r)
+
+			// Writes make every cached response for the resource stale
+			if isMutatingMethod(r.Method) {
+				cache.Range(func(key, value interface{}) bool {
+					if value.(*cacheEntry).resource == resourceName {
+						cache.Delete(key)
+					}
+					return true
+				})
+			}
 			return
 		}
 
 		if len(s.cfg.Behavior.Caching.Resources) > 0 {
-			path := strings.Trim(r.URL.Path, "/")
-			parts := strings.Split(path, "/")
-			if len(parts) > 0 {
-				resourceName := parts[0]
-				found := false
-				for _, res := range s.cfg.Behavior.Caching.Resources {
-					if res == resourceName {
-						found = true
-						break
-					}
-				}
-				if !found {
-					next.ServeHTTP(w, r)
-					return
+			found := false
+			for _, res := range s.cfg.Behavior.Caching.Resources {
+				if res == resourceName {
+					found = true
+					break
 				}
 			}
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
 		}
 
-		cacheKey := r.URL.String()
+		// Responses are negotiated on Accept, as CSV or XML, so each Accept
+		// header gets its own entry
+		cacheKey := r.URL.String() + " " + r.Header.Get("Accept")
+		addVary(w.Header(), "Accept")
+		noCache := strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache")
 
-		if s.cfg.Behavior.Caching.UseETag {
-			ifNoneMatch := r.Header.Get("If-None-Match")
-			if ifNoneMatch != "" {
-				if cached, ok := cache.Load(cacheKey); ok {
-					entry := cached.(*cacheEntry)
-					if entry.etag == ifNoneMatch && time.Now().Before(entry.expiry) {
+		if !noCache {
+			if cached, ok := cache.Load(cacheKey); ok {
+				entry := cached.(*cacheEntry)
+				if time.Now().Before(entry.expiry) && s.notModified(r, entry) {
+					if s.cfg.Behavior.Caching.UseETag {
 						w.Header().Set("ETag", entry.etag)
-						w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.cfg.Behavior.Caching.TTL.Seconds())))
-						w.WriteHeader(http.StatusNotModified)
-						return
 					}
+					w.Header().Set("Last-Modified", entry.lastModified.UTC().Format(http.TimeFormat))
+					w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.cfg.Behavior.Caching.TTL.Seconds())))
+					w.WriteHeader(http.StatusNotModified)
+					return
 				}
 			}
 		}
@@ -218,18 +518,35 @@ func (s *Server) cachingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(recorder, r)
 
 		if recorder.statusCode == http.StatusOK {
-			etag := generateETag(recorder.body)
-			expiry := time.Now().Add(s.cfg.Behavior.Caching.TTL.Duration)
+			now := time.Now()
+
+			// Handlers may set an ETag of their own, such as the version of a
+			// single resource
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				etag = generateETag(recorder.body)
+			}
+
+			// Keep the original modification time while the content is unchanged
+			lastModified := now
+			if cached, ok := cache.Load(cacheKey); ok {
+				if entry := cached.(*cacheEntry); entry.etag == etag {
+					lastModified = entry.lastModified
+				}
+			}
 
 			cache.Store(cacheKey, &cacheEntry{
-				body:   recorder.body,
-				etag:   etag,
-				expiry: expiry,
+				body:         recorder.body,
+				etag:         etag,
+				resource:     resourceName,
+				lastModified: lastModified,
+				expiry:       now.Add(s.cfg.Behavior.Caching.TTL.Duration),
 			})
 
 			if s.cfg.Behavior.Caching.UseETag {
 				w.Header().Set("ETag", etag)
 			}
+			w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
 			w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.cfg.Behavior.Caching.TTL.Seconds())))
 		}
 
@@ -239,60 +556,427 @@ func (s *Server) cachingMiddleware(next http.Handler) http.Handler {
 }
 
 type cacheEntry struct {
-	body   []byte
-	etag   string
-	expiry time.Time
+	body         []byte
+	etag         string
+	resource     string
+	lastModified time.Time
+	expiry       time.Time
 }
 
+// notModified evaluates a request's conditional headers against a cached
+// response. If-None-Match takes precedence over If-Modified-Since, as in RFC 7232.
+func (s *Server) notModified(r *http.Request, entry *cacheEntry) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return s.cfg.Behavior.Caching.UseETag && entry.etag == ifNoneMatch
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	// A date in the future is the client's clock running ahead, so it can't
+	// prove the client has the current version
+	if since.After(time.Now()) {
+		return false
+	}
+
+	// HTTP dates have second precision
+	return !entry.lastModified.Truncate(time.Second).After(since)
+}
+
+// cacheResourceName returns the top-level resource of a path, which scopes
+// cache entries and their invalidation
+func cacheResourceName(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	return parts[0]
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// generateETag hashes a response body. JSON bodies are canonicalized first so
+// that equivalent documents get the same ETag whatever their key order or
+// whitespace.
 func generateETag(data []byte) string {
-	hash := md5.Sum(data)
+	hash := md5.Sum(canonicalJSON(data))
 	return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash[:]))
 }
 
-type gzipResponseWriter struct {
-	io.Writer
+// canonicalJSON re-encodes a JSON document compactly with sorted object keys,
+// keeping numbers exactly as written. Non-JSON data is returned unchanged.
+func canonicalJSON(data []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return data
+	}
+
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return canonical
+}
+
+// compressResponseWriter buffers the start of a response until it reaches the
+// minimum length, then decides whether to compress the rest of it
+type compressResponseWriter struct {
 	http.ResponseWriter
-	wroteHeader bool
+	encoding   string
+	minLength  int
+	types      []string
+	statusCode int
+	buf        []byte
+	encoder    io.WriteCloser
+	decided    bool
+}
+
+func (crw *compressResponseWriter) WriteHeader(code int) {
+	if crw.statusCode == 0 {
+		crw.statusCode = code
+	}
+}
+
+func (crw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !crw.decided {
+		crw.buf = append(crw.buf, b...)
+		if len(crw.buf) < crw.minLength {
+			return len(b), nil
+		}
+		if err := crw.start(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if crw.encoder != nil {
+		return crw.encoder.Write(b)
+	}
+	return crw.ResponseWriter.Write(b)
+}
+
+// start writes the headers, switching to the encoder when compressing, and
+// flushes whatever was buffered
+func (crw *compressResponseWriter) start(compress bool) error {
+	crw.decided = true
+
+	status := crw.statusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := crw.ResponseWriter.Header()
+	if header.Get("Content-Encoding") != "" || status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		compress = false
+	}
+
+	// Set the type now, as sniffing a compressed body would find gzip data
+	if header.Get("Content-Type") == "" && len(crw.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(crw.buf))
+	}
+	if compress && !compressibleType(header.Get("Content-Type"), crw.types) {
+		compress = false
+	}
+
+	if compress {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", crw.encoding)
+		switch crw.encoding {
+		case "br":
+			crw.encoder = brotli.NewWriterLevel(crw.ResponseWriter, brotli.DefaultCompression)
+		default:
+			gz, err := gzip.NewWriterLevel(crw.ResponseWriter, gzip.DefaultCompression)
+			if err != nil {
+				return err
+			}
+			crw.encoder = gz
+		}
+	}
+
+	crw.ResponseWriter.WriteHeader(status)
+
+	buf := crw.buf
+	crw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if crw.encoder != nil {
+		_, err := crw.encoder.Write(buf)
+		return err
+	}
+	_, err := crw.ResponseWriter.Write(buf)
+	return err
+}
+
+// Close sends a response that never reached the minimum length uncompressed
+// and flushes the encoder
+func (crw *compressResponseWriter) Close() error {
+	if !crw.decided {
+		if err := crw.start(false); err != nil {
+			return err
+		}
+	}
+	if crw.encoder != nil {
+		return crw.encoder.Close()
+	}
+	return nil
+}
+
+// compressibleType reports whether a response content type matches one of
+// the allowed types. Types may end in /* to allow a whole family, and an
+// empty list allows everything.
+func compressibleType(contentType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range types {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType {
+			return true
+		}
+		if family, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, family+"/") {
+			return true
+		}
+	}
+	return false
 }
 
-func (grw *gzipResponseWriter) Write(b []byte) (int, error) {
-	if !grw.wroteHeader {
-		grw.ResponseWriter.Header().Del("Content-Length")
-		grw.wroteHeader = true
+// negotiateEncoding picks the response encoding from an Accept-Encoding header:
+// the supported coding with the highest q-value, brotli on a tie. Codings the
+// header doesn't name get the q-value of *, and q=0 excludes a coding. The
+// empty string means identity, chosen when the client accepts no supported
+// coding or names identity with a higher q-value than all of them.
+func negotiateEncoding(acceptEncoding string) string {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		weights[name] = q
+	}
+
+	weight := func(coding string) (float64, bool) {
+		if q, ok := weights[coding]; ok {
+			return q, true
+		}
+		q, ok := weights["*"]
+		return q, ok
 	}
-	return grw.Writer.Write(b)
+
+	best, bestQ := "", 0.0
+	for _, coding := range []string{"br", "gzip"} {
+		if q, _ := weight(coding); q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	if q, ok := weight("identity"); ok && q > bestQ {
+		return ""
+	}
+	return best
 }
 
-func (grw *gzipResponseWriter) WriteHeader(code int) {
-	grw.ResponseWriter.Header().Del("Content-Length")
-	grw.wroteHeader = true
-	grw.ResponseWriter.WriteHeader(code)
+// negotiateMediaType picks the media type to respond with from an Accept
+// header. Each offer gets the q-value of the most specific range matching it,
+// and ties go to the more specific range, then to the range named first. The
+// first offer is the default when the header prefers none of them.
+func negotiateMediaType(accept string, offers ...string) string {
+	best, bestQ, bestSpecificity, bestPosition := offers[0], 0.0, -1, 0
+	for _, offer := range offers {
+		q, specificity, position := 0.0, -1, 0
+		for i, part := range strings.Split(accept, ",") {
+			mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			rangeSpecificity := mediaRangeSpecificity(mediaRange, offer)
+			if rangeSpecificity <= specificity {
+				continue
+			}
+
+			specificity, position, q = rangeSpecificity, i, 1.0
+			if v, err := strconv.ParseFloat(params["q"], 64); err == nil {
+				q = v
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && (specificity > bestSpecificity ||
+			(specificity == bestSpecificity && position < bestPosition))) {
+			best, bestQ, bestSpecificity, bestPosition = offer, q, specificity, position
+		}
+	}
+	return best
+}
+
+// mediaRangeSpecificity reports how specifically an Accept media range
+// matches a media type: 2 for the type itself, 1 for type/* and 0 for */*,
+// or -1 if it doesn't match. JSON and XML types match any range of the same
+// syntax, so application/json matches application/problem+json.
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case mediaRange == mediaType,
+		isJSONMediaType(mediaRange) && isJSONMediaType(mediaType),
+		isXMLMediaType(mediaRange) && isXMLMediaType(mediaType):
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
+
+// isJSONMediaType reports whether a media type is application/json or a
+// structured +json type such as application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// addVary adds a header name to the Vary header unless it is already listed
+func addVary(h http.Header, name string) {
+	for _, value := range h.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), name) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", name)
 }
 
 func (s *Server) compressionMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.URL.Path == eventsPath {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		gz, err := gzip.NewWriterLevel(w, gzip.DefaultCompression)
-		if err != nil {
+		crw := &compressResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minLength:      s.cfg.Behavior.Compression.MinBytes,
+			types:          s.cfg.Behavior.Compression.Types,
+		}
+		defer crw.Close()
+
+		next.ServeHTTP(crw, r)
+	})
+}
+
+// Drip settings used when the configured ones can't make progress
+const (
+	defaultDripBytes    = 64
+	defaultDripInterval = 100 * time.Millisecond
+)
+
+// dripMiddleware sends API response bodies a few bytes at a time with a
+// pause in between. Placed outside the compression and caching middleware, it
+// slows down the bytes that actually go over the wire.
+func (s *Server) dripMiddleware(next http.Handler) http.Handler {
+	chunk := s.cfg.Behavior.Drip.Bytes
+	if chunk <= 0 {
+		chunk = defaultDripBytes
+	}
+	interval := s.cfg.Behavior.Drip.Interval.Duration
+	if interval <= 0 {
+		interval = defaultDripInterval
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/_meridian/") {
 			next.ServeHTTP(w, r)
 			return
 		}
-		defer gz.Close()
-
-		w.Header().Set("Content-Encoding", "gzip")
-		w.Header().Set("Vary", "Accept-Encoding")
 
-		grw := &gzipResponseWriter{
-			Writer:         gz,
+		next.ServeHTTP(&dripWriter{
 			ResponseWriter: w,
+			ctx:            r.Context(),
+			chunk:          chunk,
+			interval:       interval,
+		}, r)
+	})
+}
+
+// dripWriter writes the body in chunks, flushing each and pausing between
+// them. Once the client goes away the rest of the body is discarded.
+type dripWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	chunk    int
+	interval time.Duration
+	started  bool
+}
+
+func (dw *dripWriter) Write(b []byte) (int, error) {
+	written := 0
+	for written < len(b) {
+		if dw.started {
+			select {
+			case <-time.After(dw.interval):
+			case <-dw.ctx.Done():
+				return written, dw.ctx.Err()
+			}
 		}
+		dw.started = true
 
-		next.ServeHTTP(grw, r)
-	})
+		end := written + dw.chunk
+		if end > len(b) {
+			end = len(b)
+		}
+		n, err := dw.ResponseWriter.Write(b[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		dw.Flush()
+	}
+	return written, nil
+}
+
+func (dw *dripWriter) Flush() {
+	if flusher, ok := dw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
 }
 
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
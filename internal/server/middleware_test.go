@@ -3,13 +3,20 @@ package server
 import (
 	"compress/gzip"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/felipevolpatto/meridian/internal/config"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
@@ -69,6 +76,56 @@ func TestRateLimitMiddleware(t *testing.T) {
 	assert.Equal(t, "rate_limit_exceeded", response["code"])
 }
 
+func TestRateLimitMiddleware_RetryAfterFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		check  func(t *testing.T, value string)
+	}{
+		{
+			format: "seconds",
+			check: func(t *testing.T, value string) {
+				seconds, err := strconv.Atoi(value)
+				require.NoError(t, err)
+				assert.True(t, seconds >= 1 && seconds <= 60, "unexpected delay %d", seconds)
+			},
+		},
+		{
+			format: "http-date",
+			check: func(t *testing.T, value string) {
+				retryAt, err := http.ParseTime(value)
+				require.NoError(t, err)
+				assert.True(t, retryAt.After(time.Now().Add(-time.Second)))
+				assert.True(t, retryAt.Before(time.Now().Add(61*time.Second)))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := config.New()
+			cfg.Behavior.RateLimit.Enabled = true
+			cfg.Behavior.RateLimit.Rate = "1/minute"
+			cfg.Behavior.RateLimit.RetryAfterFormat = tt.format
+
+			handler := createTestServer(cfg).rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			for i := 0; i < 2; i++ {
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+				if i == 0 {
+					require.Equal(t, http.StatusOK, rr.Code)
+					continue
+				}
+
+				require.Equal(t, http.StatusTooManyRequests, rr.Code)
+				tt.check(t, rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
 func TestRateLimitMiddleware_DifferentClients(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.RateLimit.Enabled = true
@@ -96,6 +153,116 @@ func TestRateLimitMiddleware_DifferentClients(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestRateLimiter_NoBoundaryBurst(t *testing.T) {
+	limiter := newRateLimiter(rateLimitTokenBucket, "5/second", 0)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	// Spend the whole bucket right before where a fixed window would reset
+	now = now.Add(900 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		allowed, _, _ := limiter.allow("client")
+		assert.True(t, allowed, "request %d should be allowed", i+1)
+	}
+
+	// A fixed window would hand out 5 fresh requests here; the bucket has
+	// only refilled a single token in the 200ms that passed.
+	now = now.Add(200 * time.Millisecond)
+	allowed, _, _ := limiter.allow("client")
+	assert.True(t, allowed)
+
+	allowed, remaining, resetTime := limiter.allow("client")
+	assert.False(t, allowed)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, now.Add(200*time.Millisecond), resetTime)
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := newRateLimiter(rateLimitTokenBucket, "1/second", 3)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		allowed, _, _ := limiter.allow("client")
+		assert.True(t, allowed, "request %d should be allowed", i+1)
+	}
+
+	allowed, _, _ := limiter.allow("client")
+	assert.False(t, allowed)
+
+	now = now.Add(time.Second)
+	allowed, _, _ = limiter.allow("client")
+	assert.True(t, allowed)
+}
+
+func TestRateLimiter_FixedWindow(t *testing.T) {
+	limiter := newRateLimiter(rateLimitFixed, "2/second", 0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		allowed, _, resetTime := limiter.allow("client")
+		assert.True(t, allowed)
+		assert.Equal(t, start.Add(time.Second), resetTime)
+	}
+
+	allowed, _, _ := limiter.allow("client")
+	assert.False(t, allowed)
+
+	now = start.Add(time.Second)
+	allowed, remaining, _ := limiter.allow("client")
+	assert.True(t, allowed)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestRateLimiter_SlidingWindow(t *testing.T) {
+	limiter := newRateLimiter(rateLimitSliding, "3/second", 0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	limiter.now = func() time.Time { return now }
+
+	allowed, remaining, resetTime := limiter.allow("client")
+	assert.True(t, allowed)
+	assert.Equal(t, 2, remaining)
+	assert.Equal(t, start.Add(time.Second), resetTime)
+
+	now = start.Add(600 * time.Millisecond)
+	limiter.allow("client")
+	limiter.allow("client")
+
+	// The window still holds all three requests
+	now = start.Add(900 * time.Millisecond)
+	allowed, _, resetTime = limiter.allow("client")
+	assert.False(t, allowed)
+	assert.Equal(t, start.Add(time.Second), resetTime, "reset is when the oldest request ages out")
+
+	// The first request has aged out, freeing one slot
+	now = start.Add(1100 * time.Millisecond)
+	allowed, remaining, resetTime = limiter.allow("client")
+	assert.True(t, allowed)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, start.Add(1600*time.Millisecond), resetTime)
+
+	allowed, _, _ = limiter.allow("client")
+	assert.False(t, allowed)
+}
+
+func TestRateLimiter_SweepsIdleClients(t *testing.T) {
+	limiter := newRateLimiter(rateLimitSliding, "5/second", 0)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		limiter.allow(fmt.Sprintf("client-%d", i))
+	}
+	assert.Len(t, limiter.logs, 10)
+
+	now = now.Add(2 * time.Second)
+	limiter.allow("client-new")
+	assert.Len(t, limiter.logs, 1)
+}
+
 func TestErrorSimulationMiddleware(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.Errors.Enabled = true
@@ -123,6 +290,141 @@ func TestErrorSimulationMiddleware(t *testing.T) {
 	assert.Equal(t, true, response["simulated"])
 }
 
+func TestErrorSimulationMiddleware_Network(t *testing.T) {
+	tests := []struct {
+		name string
+		mode string
+	}{
+		{name: "reset", mode: "reset"},
+		{name: "timeout", mode: "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New()
+			cfg.Behavior.Errors.Network = config.NetworkErrorConfig{
+				Enabled: true,
+				Rate:    1.0,
+				Modes:   []string{tt.mode},
+				Timeout: config.Duration{Duration: time.Second},
+			}
+
+			s := createTestServer(cfg)
+			var served atomic.Bool
+			ts := httptest.NewServer(s.errorSimulationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served.Store(true)
+				w.Write([]byte("OK"))
+			})))
+			defer ts.Close()
+
+			client := &http.Client{Timeout: 200 * time.Millisecond}
+			resp, err := client.Get(ts.URL + "/test")
+			if resp != nil {
+				resp.Body.Close()
+			}
+			require.Error(t, err)
+			assert.False(t, served.Load())
+
+			var netErr net.Error
+			isTimeout := errors.As(err, &netErr) && netErr.Timeout()
+			assert.Equal(t, tt.mode == "timeout", isTimeout, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestErrorSimulationMiddleware_NetworkWithoutHijacker(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Errors.Network = config.NetworkErrorConfig{Enabled: true, Rate: 1.0, Modes: []string{"reset"}}
+
+	s := createTestServer(cfg)
+	handler := s.errorSimulationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+
+	// httptest.ResponseRecorder can't be hijacked, so the request is served
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "OK", rr.Body.String())
+}
+
+func TestResponseValidationMiddleware_ContentTypeMismatch(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.ValidateResponses = true
+
+	// The spec only declares CSV for the list, but the mock emits JSON
+	csvOnly := openapi3.NewResponses()
+	csvOnly.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"text/csv": &openapi3.MediaType{
+					Schema: &openapi3.SchemaRef{Value: openapi3.NewArraySchema()},
+				},
+			},
+		},
+	})
+	spec := createTestSpec()
+	spec.Paths.Value("/users").Get.Responses = csvOnly
+
+	handler := NewServer(spec, cfg).createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, rr.Header().Get("X-Meridian-Validation-Errors"), "does not match declared types: text/csv")
+
+	// Responses matching the declared media type aren't flagged
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name": "Ada"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get("X-Meridian-Validation-Errors"))
+}
+
+func TestResponseValidationMiddleware_Warnings(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.ValidateResponses = true
+
+	// The response declares a format Meridian doesn't know
+	user := openapi3.NewObjectSchema().WithProperty("phone", &openapi3.Schema{Type: "string", Format: "phone"})
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.NewContentWithJSONSchema(openapi3.NewArraySchema().WithItems(user)),
+		},
+	})
+	spec := createTestSpec()
+	spec.Paths.Value("/users").Get.Responses = responses
+
+	server := NewServer(spec, cfg)
+	require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{"id": "1", "phone": "555-1234"}))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("X-Meridian-Validation-Warnings"), "phone")
+	assert.Empty(t, rr.Header().Get("X-Meridian-Validation-Errors"))
+	assert.Contains(t, rr.Body.String(), "555-1234")
+}
+
 func TestErrorSimulationMiddleware_ZeroRate(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.Errors.Enabled = true
@@ -143,6 +445,25 @@ func TestErrorSimulationMiddleware_ZeroRate(t *testing.T) {
 	}
 }
 
+func TestGenerateETag_CanonicalJSON(t *testing.T) {
+	first := []byte(`{"id": "1", "name": "Ada", "tags": ["a", "b"], "score": 1.50}`)
+	second := []byte(`{
+		"score": 1.50,
+		"tags": ["a", "b"],
+		"name": "Ada",
+		"id": "1"
+	}`)
+
+	assert.Equal(t, generateETag(first), generateETag(second))
+
+	// Values still matter, including array order and number formatting
+	assert.NotEqual(t, generateETag(first), generateETag([]byte(`{"id": "1", "name": "Ada", "tags": ["b", "a"], "score": 1.50}`)))
+	assert.NotEqual(t, generateETag(first), generateETag([]byte(`{"id": "2", "name": "Ada", "tags": ["a", "b"], "score": 1.50}`)))
+
+	// Non-JSON bodies are hashed as-is
+	assert.NotEqual(t, generateETag([]byte("plain text")), generateETag([]byte("plain  text")))
+}
+
 func TestCachingMiddleware_ETag(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.Caching.Enabled = true
@@ -177,6 +498,114 @@ func TestCachingMiddleware_ETag(t *testing.T) {
 	assert.Equal(t, 1, callCount)
 }
 
+func TestCachingMiddleware_VariesOnAccept(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Caching.Enabled = true
+	cfg.Behavior.Caching.UseETag = true
+	cfg.Behavior.Caching.TTL = config.Duration{Duration: 5 * time.Minute}
+
+	s := createTestServer(cfg)
+
+	handler := s.cachingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wantsCSV(r) {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte("id\n1\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": "1"}]`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+	csvETag := rr.Header().Get("ETag")
+
+	// The CSV entry doesn't answer for the JSON representation
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("If-None-Match", csvETag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"id": "1"}]`, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept", "text/csv")
+	req.Header.Set("If-None-Match", csvETag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+}
+
+func TestCachingMiddleware_Compressed(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Caching = config.CachingConfig{Enabled: true, UseETag: true, TTL: config.Duration{Duration: 5 * time.Minute}}
+	cfg.Behavior.Compression = config.CompressionConfig{Enabled: true}
+
+	s := NewServer(createTestSpec(), cfg)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, s.stateManager.AddResource("users", map[string]interface{}{"id": strconv.Itoa(i), "name": strings.Repeat("user", 10)}))
+	}
+	handler := s.createHandler()
+
+	get := func(encoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	identity := get("", "")
+	require.Equal(t, http.StatusOK, identity.Code)
+	etag := identity.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, generateETag(identity.Body.Bytes()), etag, "the ETag is that of the uncompressed body")
+
+	// Each encoding of the same representation has the same ETag
+	for _, encoding := range []string{"gzip", "br"} {
+		compressed := get(encoding, "")
+		require.Equal(t, http.StatusOK, compressed.Code)
+		assert.Equal(t, encoding, compressed.Header().Get("Content-Encoding"))
+		assert.Equal(t, etag, compressed.Header().Get("ETag"))
+
+		assert.Equal(t, http.StatusNotModified, get(encoding, etag).Code)
+	}
+}
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"text/csv", "text/csv"},
+		{"application/json, text/csv", "application/json"},
+		{"text/csv, application/json", "text/csv"},
+		{"text/csv;q=0.5, application/json", "application/json"},
+		{"application/json;q=0.1, text/csv", "text/csv"},
+		{"*/*, text/csv", "text/csv"},
+		{"text/*", "text/csv"},
+		{"application/problem+json, text/csv", "application/json"},
+		{"text/csv;q=0", "application/json"},
+		{"image/png", "application/json"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, negotiateMediaType(tt.accept, "application/json", "text/csv"), tt.accept)
+	}
+}
+
 func TestCachingMiddleware_OnlyGET(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.Caching.Enabled = true
@@ -224,9 +653,146 @@ func TestCachingMiddleware_ResourceFilter(t *testing.T) {
 	assert.Empty(t, rr2.Header().Get("ETag"))
 }
 
+func TestCachingMiddleware_InvalidatesOnWrite(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Caching.Enabled = true
+	cfg.Behavior.Caching.UseETag = true
+	cfg.Behavior.Caching.TTL = config.Duration{Duration: 5 * time.Minute}
+
+	s := createTestServer(cfg)
+
+	users := []string{"alice"}
+	handler := s.cachingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			users = append(users, "bob")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(users)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"bob"}`))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	assert.Contains(t, rr.Body.String(), "bob")
+}
+
+func TestCachingMiddleware_NoCache(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Caching.Enabled = true
+	cfg.Behavior.Caching.UseETag = true
+	cfg.Behavior.Caching.TTL = config.Duration{Duration: 5 * time.Minute}
+
+	s := createTestServer(cfg)
+
+	callCount := 0
+	handler := s.cachingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.Write([]byte(`{"data": "test"}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	etag := rr.Header().Get("ETag")
+	lastModified := rr.Header().Get("Last-Modified")
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-None-Match", etag)
+	req.Header.Set("Cache-Control", "no-cache")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, callCount)
+	assert.Equal(t, `{"data": "test"}`, rr.Body.String())
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+	assert.Equal(t, lastModified, rr.Header().Get("Last-Modified"))
+}
+
+func TestCachingMiddleware_IfModifiedSince(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Caching.Enabled = true
+	cfg.Behavior.Caching.UseETag = true
+	cfg.Behavior.Caching.TTL = config.Duration{Duration: 5 * time.Minute}
+
+	s := createTestServer(cfg)
+
+	users := []string{"alice"}
+	handler := s.cachingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			users = append(users, "bob")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		json.NewEncoder(w).Encode(users)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	lastModified := rr.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+	modified, err := http.ParseTime(lastModified)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		since    string
+		expected int
+	}{
+		{"same time", lastModified, http.StatusNotModified},
+		{"RFC 850 format", modified.Format(time.RFC850), http.StatusNotModified},
+		{"ANSI C format", modified.Format(time.ANSIC), http.StatusNotModified},
+		{"earlier", modified.Add(-time.Hour).Format(http.TimeFormat), http.StatusOK},
+		{"future clock skew", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), http.StatusOK},
+		{"invalid date", "yesterday", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Header.Set("If-Modified-Since", tt.since)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+
+	// A write invalidates the cached entry, so the same date is no longer enough
+	req = httptest.NewRequest(http.MethodPost, "/users", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "bob")
+}
+
 func TestCompressionMiddleware(t *testing.T) {
 	cfg := config.New()
-	cfg.Behavior.Compression = true
+	cfg.Behavior.Compression.Enabled = true
 
 	s := createTestServer(cfg)
 
@@ -257,7 +823,7 @@ func TestCompressionMiddleware(t *testing.T) {
 
 func TestCompressionMiddleware_NoAcceptEncoding(t *testing.T) {
 	cfg := config.New()
-	cfg.Behavior.Compression = true
+	cfg.Behavior.Compression.Enabled = true
 
 	s := createTestServer(cfg)
 
@@ -278,6 +844,255 @@ func TestCompressionMiddleware_NoAcceptEncoding(t *testing.T) {
 	assert.Equal(t, responseBody, rr.Body.String())
 }
 
+func TestCompressionMiddleware_Brotli(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Compression.Enabled = true
+
+	s := createTestServer(cfg)
+
+	responseBody := strings.Repeat(`{"message": "brotli"}`, 20)
+	handler := s.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(responseBody)))
+		w.Write([]byte(responseBody))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "br", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Empty(t, rr.Header().Get("Content-Length"))
+
+	decompressed, err := io.ReadAll(brotli.NewReader(rr.Body))
+	require.NoError(t, err)
+	assert.Equal(t, responseBody, string(decompressed))
+}
+
+func TestCompressionMiddleware_MinLength(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Compression.Enabled = true
+	cfg.Behavior.Compression.MinBytes = 100
+
+	s := createTestServer(cfg)
+
+	var responseBody string
+	handler := s.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(responseBody)))
+		w.WriteHeader(http.StatusCreated)
+		// Write in chunks so the threshold is crossed mid-response
+		for i := 0; i < len(responseBody); i += 10 {
+			end := i + 10
+			if end > len(responseBody) {
+				end = len(responseBody)
+			}
+			w.Write([]byte(responseBody[i:end]))
+		}
+	}))
+
+	responseBody = `{"message": "small"}`
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+	assert.Equal(t, fmt.Sprintf("%d", len(responseBody)), rr.Header().Get("Content-Length"))
+	assert.Equal(t, responseBody, rr.Body.String())
+
+	responseBody = strings.Repeat(`{"message": "large"}`, 10)
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Empty(t, rr.Header().Get("Content-Length"))
+
+	reader, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, responseBody, string(decompressed))
+}
+
+func TestCompressionMiddleware_MinBytesAndTypes(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Compression = config.CompressionConfig{
+		Enabled:  true,
+		MinBytes: 256,
+		Types:    []string{"application/json", "text/*"},
+	}
+
+	s := createTestServer(cfg)
+
+	tests := []struct {
+		name         string
+		contentType  string
+		body         string
+		wantEncoding string
+	}{
+		{name: "small json", contentType: "application/json", body: `{"id": "1"}`},
+		{name: "large json", contentType: "application/json; charset=utf-8", body: strings.Repeat(`{"id": "1"}`, 50), wantEncoding: "gzip"},
+		{name: "large text", contentType: "text/csv", body: strings.Repeat("id,name\n", 50), wantEncoding: "gzip"},
+		{name: "large image", contentType: "image/png", body: strings.Repeat("x", 1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := s.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.wantEncoding, rr.Header().Get("Content-Encoding"))
+			if tt.wantEncoding == "" {
+				assert.Equal(t, tt.body, rr.Body.String())
+				return
+			}
+
+			reader, err := gzip.NewReader(rr.Body)
+			require.NoError(t, err)
+			defer reader.Close()
+			decompressed, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(decompressed))
+		})
+	}
+}
+
+// writeCounter records how many writes reach the client
+type writeCounter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (wc *writeCounter) Write(b []byte) (int, error) {
+	wc.writes++
+	return wc.ResponseRecorder.Write(b)
+}
+
+func TestDripMiddleware(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Drip = config.DripConfig{Enabled: true, Bytes: 10, Interval: config.Duration{Duration: 20 * time.Millisecond}}
+
+	s := createTestServer(cfg)
+
+	responseBody := strings.Repeat("x", 100)
+	handler := s.dripMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(responseBody))
+	}))
+
+	rr := &writeCounter{ResponseRecorder: httptest.NewRecorder()}
+	start := time.Now()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	elapsed := time.Since(start)
+
+	// Ten chunks with a pause between each
+	assert.GreaterOrEqual(t, elapsed, 9*20*time.Millisecond)
+	assert.Equal(t, 10, rr.writes)
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, responseBody, rr.Body.String())
+}
+
+func TestDripMiddleware_Unset(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Drip = config.DripConfig{Enabled: true}
+
+	s := createTestServer(cfg)
+
+	responseBody := strings.Repeat("x", 100)
+	handler := s.dripMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responseBody))
+	}))
+
+	// Without bytes or an interval, the defaults still finish the body
+	rr := &writeCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, 2, rr.writes)
+	assert.Equal(t, responseBody, rr.Body.String())
+}
+
+func TestDripMiddleware_Compressed(t *testing.T) {
+	cfg := config.New()
+	cfg.Behavior.Compression.Enabled = true
+	cfg.Behavior.Drip = config.DripConfig{Enabled: true, Bytes: 8, Interval: config.Duration{Duration: 5 * time.Millisecond}}
+
+	s := createTestServer(cfg)
+
+	responseBody := strings.Repeat(`{"message": "drip"}`, 20)
+	handler := s.dripMiddleware(s.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responseBody))
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := &writeCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(rr, req)
+
+	// The compressed bytes are what gets dripped
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Greater(t, rr.writes, 1)
+
+	reader, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, responseBody, string(decompressed))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"gzip, br", "br"},
+		{"br;q=0, gzip", "gzip"},
+		{"gzip;q=0", ""},
+		{"identity", ""},
+		{"*", "br"},
+		{"GZIP;q=0.5", "gzip"},
+		{"br;q=0.1, gzip", "gzip"},
+		{"gzip;q=0.5, br;q=0.5", "br"},
+		{"gzip;q=0, *", "br"},
+		{"br;q=0, *", "gzip"},
+		{"*;q=0", ""},
+		{"br, *;q=0", "br"},
+		{"gzip;q=0.5, identity", ""},
+		{"identity;q=0.5, gzip", "gzip"},
+		{"identity;q=0, gzip;q=0.1", "gzip"},
+		{"identity;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.header))
+		})
+	}
+}
+
 func TestLatencyMiddleware(t *testing.T) {
 	cfg := config.New()
 	cfg.Behavior.Latency.Enabled = true
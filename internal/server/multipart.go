@@ -0,0 +1,143 @@
+package server
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// maxFormMemory is how much of a multipart body is held in memory, the rest
+// of the file parts is buffered on disk
+const maxFormMemory = 32 << 20
+
+// formError reports the parts of a multipart body that don't match the
+// operation's schema or encoding
+type formError struct {
+	details []string
+}
+
+func (e *formError) Error() string {
+	return "invalid form data: " + strings.Join(e.details, "; ")
+}
+
+// isMultipartRequest checks if a request body is multipart/form-data
+func isMultipartRequest(r *http.Request) bool {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && ct == "multipart/form-data"
+}
+
+// decodeMultipart reads a multipart/form-data body as a resource. Text fields
+// become properties, converted to the types of the operation's schema, and
+// file parts are recorded by their metadata (filename, size and
+// content_type) rather than their bytes. Fields and files sent several times
+// become arrays. Required parts and the content types of the spec's encoding
+// are checked, returning a *formError.
+func decodeMultipart(r *http.Request, op *openapi3.Operation) (map[string]interface{}, error) {
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+		return nil, err
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	data := make(map[string]interface{})
+	for name, values := range r.MultipartForm.Value {
+		data[name] = formValue(values)
+	}
+	for name, files := range r.MultipartForm.File {
+		metadata := make([]interface{}, len(files))
+		for i, file := range files {
+			metadata[i] = fileMetadata(file)
+		}
+		data[name] = formValue(metadata)
+	}
+
+	var mt *openapi3.MediaType
+	if op != nil && op.RequestBody != nil && op.RequestBody.Value != nil {
+		mt = op.RequestBody.Value.Content.Get("multipart/form-data")
+	}
+	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+		return data, nil
+	}
+	schema := mt.Schema.Value
+
+	var details []string
+	for _, name := range schema.Required {
+		if _, ok := data[name]; !ok {
+			details = append(details, fmt.Sprintf("missing required part %s", name))
+		}
+	}
+	for _, name := range sortedKeys(mt.Encoding) {
+		encoding := mt.Encoding[name]
+		if encoding == nil || encoding.ContentType == "" {
+			continue
+		}
+		for _, file := range r.MultipartForm.File[name] {
+			if ct := file.Header.Get("Content-Type"); !matchesContentTypes(ct, encoding.ContentType) {
+				details = append(details, fmt.Sprintf("part %s has content type %s, expected %s", name, ct, encoding.ContentType))
+			}
+		}
+	}
+	if len(details) > 0 {
+		return nil, &formError{details: details}
+	}
+
+	for name, ref := range schema.Properties {
+		value, ok := data[name]
+		if _, isList := value.([]interface{}); ok && !isList && ref != nil && ref.Value != nil && ref.Value.Type == "array" {
+			data[name] = []interface{}{value}
+		}
+	}
+	return coerceToSchema(data, schema).(map[string]interface{}), nil
+}
+
+// formValue returns the only value of a part, or all of them as an array
+func formValue[T any](values []T) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	items := make([]interface{}, len(values))
+	for i, value := range values {
+		items[i] = value
+	}
+	return items
+}
+
+// fileMetadata describes an uploaded file
+func fileMetadata(file *multipart.FileHeader) map[string]interface{} {
+	return map[string]interface{}{
+		"filename":     file.Filename,
+		"size":         file.Size,
+		"content_type": file.Header.Get("Content-Type"),
+	}
+}
+
+// matchesContentTypes checks a content type against a comma separated list
+// of media types, such as "image/png, image/*"
+func matchesContentTypes(contentType, allowed string) bool {
+	ct, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range strings.Split(allowed, ",") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if ok, _ := path.Match(pattern, ct); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of an encoding map in sorted order
+func sortedKeys(encodings map[string]*openapi3.Encoding) []string {
+	keys := make([]string, 0, len(encodings))
+	for key := range encodings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
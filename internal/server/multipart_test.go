@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartTestSpec = `
+openapi: 3.0.0
+info:
+  title: Uploads API
+  version: 1.0.0
+paths:
+  /uploads:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [title, file]
+              properties:
+                title:
+                  type: string
+                pages:
+                  type: integer
+                tags:
+                  type: array
+                  items:
+                    type: string
+                file:
+                  type: string
+                  format: binary
+            encoding:
+              file:
+                contentType: image/png, image/*
+      responses:
+        '201':
+          description: Uploaded
+          content:
+            application/json:
+              schema:
+                type: object
+`
+
+// multipartBody builds a form with the given fields and, unless fileType is
+// empty, a file part named file
+func multipartBody(t *testing.T, fields map[string][]string, fileType string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+	}
+	if fileType != "" {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="file"; filename="cover.png"`)
+		header.Set("Content-Type", fileType)
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func newMultipartTestServer(t *testing.T) http.Handler {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(multipartTestSpec))
+	require.NoError(t, err)
+
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+	tmpFile.Close()
+
+	return NewServer(spec, createTestConfig(tmpFile.Name())).createHandler()
+}
+
+func TestMultipart_StoresFieldsAndFileMetadata(t *testing.T) {
+	handler := newMultipartTestServer(t)
+
+	body, contentType := multipartBody(t, map[string][]string{
+		"title": {"Cover"},
+		"pages": {"12"},
+		"tags":  {"art"},
+	}, "image/png")
+	req := httptest.NewRequest(http.MethodPost, "/uploads", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var upload map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &upload))
+	assert.NotEmpty(t, upload["id"])
+	assert.Equal(t, "Cover", upload["title"])
+	assert.Equal(t, float64(12), upload["pages"])
+	assert.Equal(t, []interface{}{"art"}, upload["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"filename":     "cover.png",
+		"size":         float64(10),
+		"content_type": "image/png",
+	}, upload["file"])
+}
+
+func TestMultipart_ValidatesParts(t *testing.T) {
+	handler := newMultipartTestServer(t)
+
+	tests := []struct {
+		name     string
+		fields   map[string][]string
+		fileType string
+		details  []interface{}
+	}{
+		{
+			name:    "missing parts",
+			fields:  map[string][]string{"pages": {"3"}},
+			details: []interface{}{"missing required part title", "missing required part file"},
+		},
+		{
+			name:     "unexpected content type",
+			fields:   map[string][]string{"title": {"Notes"}},
+			fileType: "text/plain",
+			details:  []interface{}{"part file has content type text/plain, expected image/png, image/*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartBody(t, tt.fields, tt.fileType)
+			req := httptest.NewRequest(http.MethodPost, "/uploads", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "invalid_form", resp["code"])
+			assert.Equal(t, tt.details, resp["details"])
+		})
+	}
+
+	// Other image types match the wildcard
+	body, contentType := multipartBody(t, map[string][]string{"title": {"Photo"}}, "image/jpeg")
+	req := httptest.NewRequest(http.MethodPost, "/uploads", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
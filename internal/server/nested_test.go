@@ -243,6 +243,9 @@ func TestNestedResourcesIntegration(t *testing.T) {
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("Failed to create post: %d - %s", rr.Code, rr.Body.String())
 	}
+	if location := rr.Header().Get("Location"); location != "/users/user-1/posts/post-1" {
+		t.Errorf("Expected Location = '/users/user-1/posts/post-1', got %q", location)
+	}
 
 	// Verify the post has user_id set automatically
 	var createdPost map[string]interface{}
@@ -404,6 +407,61 @@ func TestNestedResourcesPatch(t *testing.T) {
 	}
 }
 
+func TestNestedResourcesRelations(t *testing.T) {
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   "Test API",
+			Version: "1.0.0",
+		},
+		Paths: &openapi3.Paths{},
+	}
+
+	spec.Paths.Set("/users", &openapi3.PathItem{
+		Post: &openapi3.Operation{OperationID: "createUser"},
+	})
+	spec.Paths.Set("/users/{userId}/posts", &openapi3.PathItem{
+		Post: &openapi3.Operation{OperationID: "createUserPost"},
+	})
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{Address: "localhost", Port: 8080},
+		State:  config.StateConfig{Persistence: ""},
+	}
+
+	server := NewServer(spec, cfg)
+
+	for _, post := range []struct{ path, body string }{
+		{"/users", `{"id": "u1"}`},
+		{"/users/u1/posts", `{"id": "p1", "title": "First"}`},
+		{"/users/u1/posts", `{"id": "p2", "title": "Second"}`},
+		// A missing parent still creates the post, without an edge
+		{"/users/u2/posts", `{"id": "p3", "title": "Orphan"}`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, post.path, strings.NewReader(post.body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("POST %s failed: %d - %s", post.path, rr.Code, rr.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/_meridian/state", nil)
+	rr := httptest.NewRecorder()
+	server.ServeHTTP(rr, req)
+
+	var export struct {
+		Relations map[string]map[string]string `json:"relations"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &export); err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+	if export.Relations["users"]["posts"] != "one_to_many" {
+		t.Errorf("Expected users -> posts to be one_to_many, got %v", export.Relations)
+	}
+}
+
 func TestBuildAndParseNestedResourceKey(t *testing.T) {
 	key := BuildNestedResourceKey("users", "123", "posts")
 	expected := "users:123:posts"
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// preference returns the value of a preference in the request's Prefer
+// headers (RFC 7240), such as 404 for Prefer: code=404
+func preference(r *http.Request, name string) (string, bool) {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			// Parameters after ; don't matter for the preferences we support
+			pref, _, _ = strings.Cut(pref, ";")
+			key, value, ok := strings.Cut(strings.TrimSpace(pref), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), name) {
+				continue
+			}
+			return strings.Trim(strings.TrimSpace(value), `"`), true
+		}
+	}
+	return "", false
+}
+
+// servePreferred answers with the response the client asked for with
+// Prefer: code=NNN and/or Prefer: example=name, instead of handling the
+// request. It reports false, leaving the request to the normal handlers,
+// when there's no such preference or the operation doesn't define the
+// response.
+func (s *Server) servePreferred(w http.ResponseWriter, r *http.Request, op *openapi3.Operation) bool {
+	codeValue, hasCode := preference(r, "code")
+	example, hasExample := preference(r, "example")
+	if !hasCode && !hasExample {
+		return false
+	}
+
+	var statusCode int
+	var resp *openapi3.Response
+	if hasCode {
+		code, err := strconv.Atoi(codeValue)
+		if err != nil || op.Responses == nil {
+			return false
+		}
+		ref := op.Responses.Status(code)
+		if ref == nil || ref.Value == nil {
+			return false
+		}
+		statusCode, resp = code, ref.Value
+	} else {
+		statusCode, resp = firstSuccessResponse(op)
+		if resp == nil {
+			return false
+		}
+	}
+
+	mediaType, mt := preferredMediaType(resp)
+	if hasExample && (mt == nil || mt.Examples[example] == nil || mt.Examples[example].Value == nil) {
+		return false
+	}
+
+	applied := make([]string, 0, 2)
+	if hasCode {
+		applied = append(applied, "code="+codeValue)
+	}
+	if hasExample {
+		applied = append(applied, "example="+example)
+	}
+	w.Header().Set("Preference-Applied", strings.Join(applied, ", "))
+
+	if mt == nil || statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+		return true
+	}
+
+	var body interface{}
+	switch {
+	case hasExample:
+		body = mt.Examples[example].Value.Value
+	case mt.Example != nil:
+		body = mt.Example
+	case len(mt.Examples) > 0:
+		body = firstExample(mt.Examples)
+	case mt.Schema != nil:
+		generated, err := generator.GenerateAdvancedData(mt.Schema, "")
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate response: %v", err), http.StatusInternalServerError)
+			return true
+		}
+		body = generated
+	}
+
+	if text, ok := body.(string); ok && !strings.Contains(mediaType, "json") {
+		w.Header().Set("Content-Type", mediaType)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(text))
+		return true
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+	return true
+}
+
+// preferredMediaType returns the JSON content of a response, or the first of
+// its content types in order when it has no JSON
+func preferredMediaType(resp *openapi3.Response) (string, *openapi3.MediaType) {
+	if mt := resp.Content.Get("application/json"); mt != nil {
+		return "application/json", mt
+	}
+	for _, mediaType := range sortedFields(resp.Content) {
+		return mediaType, resp.Content[mediaType]
+	}
+	return "", nil
+}
+
+// firstExample returns the value of the first named example in order
+func firstExample(examples openapi3.Examples) interface{} {
+	names := make([]string, 0, len(examples))
+	for name, example := range examples {
+		if example != nil && example.Value != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return examples[names[0]].Value.Value
+}
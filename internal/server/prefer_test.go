@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreference(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Add("Prefer", "respond-async, wait=5")
+	r.Header.Add("Prefer", `code=404; foo=bar, example="missing"`)
+
+	value, ok := preference(r, "code")
+	assert.True(t, ok)
+	assert.Equal(t, "404", value)
+
+	value, ok = preference(r, "example")
+	assert.True(t, ok)
+	assert.Equal(t, "missing", value)
+
+	value, ok = preference(r, "wait")
+	assert.True(t, ok)
+	assert.Equal(t, "5", value)
+
+	_, ok = preference(r, "return")
+	assert.False(t, ok)
+}
+
+func TestPreferredResponse(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	errorSchema := openapi3.NewObjectSchema().WithProperty("message", openapi3.NewStringSchema())
+	errorSchema.Required = []string{"message"}
+
+	spec := createTestSpec()
+	responses := spec.Paths.Value("/users/{id}").Get.Responses
+	responses.Set("200", &openapi3.ResponseRef{Value: &openapi3.Response{
+		Content: openapi3.Content{
+			"application/json": &openapi3.MediaType{
+				Schema: openapi3.NewObjectSchema().NewRef(),
+				Examples: openapi3.Examples{
+					"alice": &openapi3.ExampleRef{Value: openapi3.NewExample(map[string]interface{}{"id": "1", "name": "Alice"})},
+					"bob":   &openapi3.ExampleRef{Value: openapi3.NewExample(map[string]interface{}{"id": "2", "name": "Bob"})},
+				},
+			},
+		},
+	}})
+	responses.Set("404", &openapi3.ResponseRef{Value: &openapi3.Response{
+		Content: openapi3.Content{
+			"application/json": &openapi3.MediaType{Schema: errorSchema.NewRef()},
+		},
+	}})
+	responses.Set("409", &openapi3.ResponseRef{Value: &openapi3.Response{
+		Content: openapi3.Content{
+			"application/json": &openapi3.MediaType{Example: map[string]interface{}{"message": "conflict"}},
+		},
+	}})
+
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{"id": "1", "name": "Stored"}))
+	handler := server.createHandler()
+
+	get := func(prefer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set("Prefer", prefer)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("generated body for code", func(t *testing.T) {
+		w := get("code=404")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "code=404", w.Header().Get("Preference-Applied"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.IsType(t, "", body["message"])
+	})
+
+	t.Run("example of code", func(t *testing.T) {
+		w := get("code=409")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"message": "conflict"}`, w.Body.String())
+	})
+
+	t.Run("named example", func(t *testing.T) {
+		w := get("example=bob")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "example=bob", w.Header().Get("Preference-Applied"))
+		assert.JSONEq(t, `{"id": "2", "name": "Bob"}`, w.Body.String())
+
+		w = get("code=200, example=alice")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": "1", "name": "Alice"}`, w.Body.String())
+	})
+
+	t.Run("undefined response is ignored", func(t *testing.T) {
+		for _, prefer := range []string{"code=418", "example=carol", "code=abc"} {
+			w := get(prefer)
+			assert.Equal(t, http.StatusOK, w.Code, prefer)
+			assert.Empty(t, w.Header().Get("Preference-Applied"), prefer)
+			assert.JSONEq(t, `{"id": "1", "name": "Stored"}`, w.Body.String(), prefer)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+)
+
+// Policies applied when a delivery queue is full
+const (
+	queueDropOldest = "drop_oldest"
+	queueBlock      = "block"
+)
+
+// defaultQueueCapacity is how many events a queue holds when no capacity is
+// configured
+const defaultQueueCapacity = 64
+
+// deliveryQueue is a bounded FIFO of pending deliveries, so a slow consumer
+// holds at most its capacity in memory. When the queue is full, push either
+// discards the oldest entry or waits for room, depending on the policy.
+type deliveryQueue[T any] struct {
+	items   chan T
+	block   bool
+	done    chan struct{}
+	closed  sync.Once
+	dropped *atomic.Uint64
+}
+
+// newDeliveryQueue creates a queue that counts the entries it discards in
+// dropped
+func newDeliveryQueue[T any](cfg config.DeliveryQueueConfig, dropped *atomic.Uint64) *deliveryQueue[T] {
+	capacity := cfg.Capacity
+	if capacity <= 0 {
+		capacity = defaultQueueCapacity
+	}
+	return &deliveryQueue[T]{
+		items:   make(chan T, capacity),
+		block:   cfg.Policy == queueBlock,
+		done:    make(chan struct{}),
+		dropped: dropped,
+	}
+}
+
+// push adds an item to the queue. Once the queue is closed, items are
+// discarded.
+func (q *deliveryQueue[T]) push(item T) {
+	select {
+	case <-q.done:
+		return
+	default:
+	}
+
+	if q.block {
+		select {
+		case q.items <- item:
+		case <-q.done:
+		}
+		return
+	}
+
+	for {
+		select {
+		case q.items <- item:
+			return
+		default:
+		}
+
+		// Full: make room by discarding the oldest entry
+		select {
+		case <-q.items:
+			q.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// close releases any push waiting for room and tells consumers to stop
+func (q *deliveryQueue[T]) close() {
+	q.closed.Do(func() { close(q.done) })
+}
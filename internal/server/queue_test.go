@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryQueue_DropOldestStaysBounded(t *testing.T) {
+	hub := newChangeHub(config.DeliveryQueueConfig{Capacity: 8, Policy: queueDropOldest})
+	stream, cancel := hub.stream()
+	defer cancel()
+
+	// Flood the stream while its consumer reads slowly
+	done := make(chan struct{})
+	received := make([]changeEvent, 0)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case change := <-stream.items:
+				received = append(received, change)
+				time.Sleep(time.Millisecond)
+			case <-time.After(50 * time.Millisecond):
+				return
+			}
+		}
+	}()
+
+	maxPending := 0
+	for i := 0; i < 1000; i++ {
+		hub.publish(eventCreated, "users", strconv.Itoa(i))
+		if pending := len(stream.items); pending > maxPending {
+			maxPending = pending
+		}
+	}
+	<-done
+
+	assert.LessOrEqual(t, maxPending, 8)
+	assert.Greater(t, hub.dropped.Load(), uint64(0))
+	assert.Equal(t, uint64(1000), hub.dropped.Load()+uint64(len(received)))
+
+	// The newest events survive
+	require.NotEmpty(t, received)
+	assert.Equal(t, "999", received[len(received)-1].ID)
+}
+
+func TestDeliveryQueue_BlockWaitsForRoom(t *testing.T) {
+	var dropped atomic.Uint64
+	queue := newDeliveryQueue[int](config.DeliveryQueueConfig{Capacity: 2, Policy: queueBlock}, &dropped)
+
+	queue.push(1)
+	queue.push(2)
+
+	pushed := make(chan struct{})
+	go func() {
+		queue.push(3)
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("push returned while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.Equal(t, 1, <-queue.items)
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("push did not resume after room was made")
+	}
+	assert.Equal(t, 2, <-queue.items)
+	assert.Equal(t, 3, <-queue.items)
+	assert.Zero(t, dropped.Load())
+
+	// Closing releases a blocked push
+	queue.push(4)
+	queue.push(5)
+	released := make(chan struct{})
+	go func() {
+		queue.push(6)
+		close(released)
+	}()
+	queue.close()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("close did not release the blocked push")
+	}
+	assert.Len(t, queue.items, 2)
+}
+
+func TestStatus_ReportsDroppedEvents(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.DeliveryQueue = config.DeliveryQueueConfig{Capacity: 1}
+	server := NewServer(createTestSpec(), cfg)
+
+	_, cancel := server.changes.stream()
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		server.changes.publish(eventUpdated, "users", "1")
+	}
+
+	w := httptest.NewRecorder()
+	server.createHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_meridian/status", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status struct {
+		DroppedEvents map[string]uint64 `json:"dropped_events"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.Equal(t, uint64(4), status.DroppedEvents["events"])
+	assert.Equal(t, uint64(0), status.DroppedEvents["webhooks"])
+}
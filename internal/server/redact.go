@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+)
+
+// redactLast4 keeps the last four characters of a redacted field; the full
+// strategy masks all of them
+const redactLast4 = "last4"
+
+// redact returns a copy of data with the fields configured in redact_fields
+// masked, at any depth. Fields are matched by name first, then by semantic
+// type. The data itself is left unchanged, since it may be a spec example.
+func (s *Server) redact(data interface{}) interface{} {
+	if len(s.cfg.Behavior.RedactFields) == 0 {
+		return data
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for name, value := range v {
+			if strategy, ok := s.redactStrategy(name); ok && value != nil {
+				redacted[name] = maskValue(value, strategy)
+				continue
+			}
+			redacted[name] = s.redact(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = s.redact(item)
+		}
+		return redacted
+	}
+	return data
+}
+
+// redactStrategy returns the masking strategy configured for a field
+func (s *Server) redactStrategy(field string) (string, bool) {
+	for key, strategy := range s.cfg.Behavior.RedactFields {
+		if strings.EqualFold(key, field) {
+			return strategy, true
+		}
+	}
+
+	fieldType := generator.DetectSemanticType(field)
+	if fieldType == generator.SemanticUnknown {
+		return "", false
+	}
+	for key, strategy := range s.cfg.Behavior.RedactFields {
+		if semanticType, ok := generator.SemanticTypeByName(key); ok && semanticType == fieldType {
+			return strategy, true
+		}
+	}
+	return "", false
+}
+
+// maskValue replaces the characters of a value with asterisks, keeping the
+// last four for the last4 strategy
+func maskValue(value interface{}, strategy string) string {
+	text := []rune(fmt.Sprint(value))
+
+	keep := 0
+	if strategy == redactLast4 && len(text) > 4 {
+		keep = 4
+	}
+	for i := 0; i < len(text)-keep; i++ {
+		text[i] = '*'
+	}
+	return string(text)
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactFields(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.RedactFields = map[string]string{
+		"credit_card": "last4",
+		"ssn":         "full",
+	}
+
+	server := NewServer(createTestSpec(), cfg)
+	require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{
+		"id":   "1",
+		"name": "Alice",
+		"ssn":  "123-45-6789",
+		"billing": map[string]interface{}{
+			"card_number": "4111111111111111",
+		},
+	}))
+	handler := server.createHandler()
+
+	for _, path := range []string{"/users/1", "/users"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var user map[string]interface{}
+			if path == "/users" {
+				var users []map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+				require.Len(t, users, 1)
+				user = users[0]
+			} else {
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+			}
+
+			assert.Equal(t, "Alice", user["name"])
+			assert.Equal(t, "***********", user["ssn"])
+			assert.Equal(t, "************1111", user["billing"].(map[string]interface{})["card_number"])
+		})
+	}
+}
+
+func TestRedactFields_Writes(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.RedactFields = map[string]string{"ssn": "full"}
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"id": "1", "name": "Alice", "ssn": "123-45-6789"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var user map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Equal(t, "***********", user["ssn"])
+
+	// Only the response is masked, not the stored resource
+	stored, err := server.stateManager.GetResource("users", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "123-45-6789", stored.(map[string]interface{})["ssn"])
+}
+
+func TestRedact_Copies(t *testing.T) {
+	server := &Server{cfg: createTestConfig("")}
+	server.cfg.Behavior.RedactFields = map[string]string{"ssn": "full"}
+
+	data := map[string]interface{}{"ssn": "1234", "items": []interface{}{map[string]interface{}{"ssn": "5678"}}}
+	redacted := server.redact(data).(map[string]interface{})
+
+	assert.Equal(t, "****", redacted["ssn"])
+	assert.Equal(t, "****", redacted["items"].([]interface{})[0].(map[string]interface{})["ssn"])
+	assert.Equal(t, "1234", data["ssn"])
+	assert.Equal(t, "5678", data["items"].([]interface{})[0].(map[string]interface{})["ssn"])
+}
+
+func TestMaskValue(t *testing.T) {
+	assert.Equal(t, "****", maskValue("1234", "last4"))
+	assert.Equal(t, "*****6789", maskValue("123456789", "last4"))
+	assert.Equal(t, "*****", maskValue(12345, "full"))
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultSearchParam is the query parameter that carries search text
+const defaultSearchParam = "q"
+
+// searchQuery returns the search text of a list request, if any
+func (s *Server) searchQuery(r *http.Request) string {
+	param := s.cfg.Behavior.Search.Param
+	if param == "" {
+		param = defaultSearchParam
+	}
+	return strings.TrimSpace(r.URL.Query().Get(param))
+}
+
+// search keeps the resources with a searched field that contains the query,
+// ignoring case
+func (s *Server) search(data []interface{}, resourceName, query string) []interface{} {
+	needle := strings.ToLower(query)
+	fields := s.cfg.Behavior.Search.Fields[resourceName]
+
+	matches := make([]interface{}, 0)
+	for _, item := range data {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if len(fields) == 0 {
+			if containsText(obj, needle, s.cfg.Behavior.Search.Nested) {
+				matches = append(matches, item)
+			}
+			continue
+		}
+
+		for _, field := range fields {
+			if value, ok := lookupField(obj, field); ok && containsText(value, needle, true) {
+				matches = append(matches, item)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// containsText reports whether a string in value contains needle. Strings in
+// nested objects and arrays are only considered when nested is set.
+func containsText(value interface{}, needle string, nested bool) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(strings.ToLower(v), needle)
+	case map[string]interface{}:
+		for _, field := range v {
+			if _, isString := field.(string); isString || nested {
+				if containsText(field, needle, nested) {
+					return true
+				}
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if containsText(item, needle, nested) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupField resolves a dotted field path such as address.city
+func lookupField(obj map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
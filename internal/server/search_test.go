@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	server := NewServer(createTestSpec(), cfg)
+
+	users := []map[string]interface{}{
+		{"id": "1", "name": "Alice Golang", "bio": "Writes Go"},
+		{"id": "2", "name": "Bob", "bio": "Loves GOLANG and Rust", "age": 42},
+		{"id": "3", "name": "Carol", "address": map[string]interface{}{"city": "Golang City"}},
+		{"id": "4", "name": "Dave", "tags": []interface{}{"golang"}},
+	}
+	for _, user := range users {
+		require.NoError(t, server.stateManager.AddResource("users", user))
+	}
+
+	search := func(query string) []string {
+		w := httptest.NewRecorder()
+		server.createHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		ids := make([]string, 0, len(result))
+		for _, item := range result {
+			ids = append(ids, item["id"].(string))
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	t.Run("top-level strings", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "2"}, search("q=golang"))
+		assert.Equal(t, []string{"1", "2", "3", "4"}, search("q="))
+		assert.Empty(t, search("q=42"))
+	})
+
+	t.Run("nested values", func(t *testing.T) {
+		cfg.Behavior.Search.Nested = true
+		defer func() { cfg.Behavior.Search.Nested = false }()
+
+		assert.Equal(t, []string{"1", "2", "3", "4"}, search("q=GoLang"))
+	})
+
+	t.Run("configured fields", func(t *testing.T) {
+		cfg.Behavior.Search.Fields = map[string][]string{"users": {"name", "address.city"}}
+		defer func() { cfg.Behavior.Search.Fields = nil }()
+
+		assert.Equal(t, []string{"1", "3"}, search("q=golang"))
+	})
+
+	t.Run("custom parameter", func(t *testing.T) {
+		cfg.Behavior.Search = config.SearchConfig{Param: "search"}
+		defer func() { cfg.Behavior.Search = config.SearchConfig{} }()
+
+		assert.Equal(t, []string{"2"}, search("search=rust"))
+		assert.Equal(t, []string{"1", "2", "3", "4"}, search("q=rust"))
+	})
+}
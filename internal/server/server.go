@@ -4,15 +4,22 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/felipevolpatto/meridian/internal/clock"
 	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/felipevolpatto/meridian/internal/generator"
 	"github.com/felipevolpatto/meridian/internal/state"
 	"github.com/felipevolpatto/meridian/internal/validation"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -28,7 +35,20 @@ type Server struct {
 	httpServer   *http.Server
 	stateManager *state.Manager
 	pathMatchers []pathMatcher
+	basePath     string
 	handler      http.Handler
+	changes      *changeHub
+	clock        *clock.Virtual
+	webhooks     *deliveryQueue[webhookDelivery]
+
+	// lastID is the most recent ID generated for a POST without one
+	lastID atomic.Int64
+
+	// webhooksDropped counts webhook deliveries discarded from a full queue
+	webhooksDropped atomic.Uint64
+
+	// seed is the parsed seed file, restored by /_meridian/reset
+	seed *state.ExportData
 }
 
 type pathMatcher struct {
@@ -49,10 +69,46 @@ func NewServer(spec *openapi3.T, cfg *config.Config) *Server {
 		cfg:          cfg,
 		validator:    validation.NewRequestValidator(spec),
 		stateManager: manager,
+		changes:      newChangeHub(cfg.Behavior.DeliveryQueue),
+		clock:        clock.NewVirtual(),
+	}
+
+	// The seed file is kept to restore the initial state on reset
+	if cfg.State.Seed != "" {
+		seed, err := state.ReadSeedFile(cfg.State.Seed)
+		if err != nil {
+			log.Printf("Warning: seed data unavailable for reset: %v", err)
+		}
+		s.seed = seed
 	}
 
+	// Timestamps, generated dates and IDs all follow the server's clock. The
+	// generator clock is process-wide, so a process runs one server at a time.
+	manager.SetClock(s.clock)
+	generator.SetClock(s.clock)
+
+	manager.SetLimit(cfg.State.MaxItems, cfg.State.EvictionPolicy)
+	if cfg.State.TTL.Duration > 0 {
+		manager.StartExpiry(cfg.State.TTL.Duration, cfg.State.SweepInterval.Duration)
+	}
+
+	if len(cfg.Behavior.Webhooks) > 0 {
+		s.webhooks = newDeliveryQueue[webhookDelivery](cfg.Behavior.DeliveryQueue, &s.webhooksDropped)
+		for i := 0; i < webhookWorkers; i++ {
+			go s.runWebhookWorker()
+		}
+	}
+
+	s.basePath = resolveBasePath(spec, cfg)
 	s.compilePaths()
 	s.handler = s.createHandler()
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Address, cfg.Server.Port),
+		Handler:      s.handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
 
 	return s
 }
@@ -95,6 +151,57 @@ func (s *Server) compilePaths() {
 	}
 }
 
+// resolveBasePath returns the prefix the API is served under: the configured
+// base path, or else the path of the spec's first server URL. A root prefix
+// is returned as "".
+func resolveBasePath(spec *openapi3.T, cfg *config.Config) string {
+	basePath := cfg.Server.BasePath
+	if basePath == "" && spec != nil && len(spec.Servers) > 0 {
+		basePath = serverBasePath(spec.Servers[0])
+	}
+	return strings.TrimSuffix(basePath, "/")
+}
+
+// serverBasePath returns the path of a server URL. Variables in the URL
+// template are replaced with their default, or their first enum value when
+// no default is given.
+func serverBasePath(server *openapi3.Server) string {
+	serverURL := server.URL
+	for name, variable := range server.Variables {
+		if variable == nil {
+			continue
+		}
+		value := variable.Default
+		if value == "" && len(variable.Enum) > 0 {
+			value = variable.Enum[0]
+		}
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", value)
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil || strings.ContainsAny(parsed.Path, "{}") {
+		log.Printf("Warning: ignoring base path of server %s: unresolved URL %s", server.URL, serverURL)
+		return ""
+	}
+	return "/" + strings.Trim(parsed.Path, "/")
+}
+
+// stripBasePath removes the base path from a request path, giving the path
+// as declared in the spec. It reports false when the request is outside the
+// base path.
+func (s *Server) stripBasePath(requestPath string) (string, bool) {
+	if s.basePath == "" {
+		return requestPath, true
+	}
+	if requestPath == s.basePath {
+		return "/", true
+	}
+	if strings.HasPrefix(requestPath, s.basePath+"/") {
+		return requestPath[len(s.basePath):], true
+	}
+	return requestPath, false
+}
+
 func (s *Server) matchPath(requestPath string) (*openapi3.PathItem, map[string]string) {
 	for _, matcher := range s.pathMatchers {
 		matches := matcher.pattern.FindStringSubmatch(requestPath)
@@ -111,25 +218,121 @@ func (s *Server) matchPath(requestPath string) (*openapi3.PathItem, map[string]s
 	return nil, nil
 }
 
-func StartServer(spec *openapi3.T, cfg *config.Config) error {
-	s := NewServer(spec, cfg)
+// resourceAllowed reports whether the configured allow and deny lists let the
+// server serve a resource. Nested resources also need their parent allowed.
+func (s *Server) resourceAllowed(resourceName string, nestedInfo *NestedResourceInfo) bool {
+	names := []string{resourceName}
+	if nestedInfo != nil && nestedInfo.IsNested {
+		names = append(names, nestedInfo.ParentResource)
+	}
 
-	addr := fmt.Sprintf("%s:%d", cfg.Server.Address, cfg.Server.Port)
-	s.httpServer = &http.Server{
-		Addr:         addr,
-		Handler:      s.createHandler(),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if containsString(s.cfg.Behavior.DeniedResources, name) {
+			return false
+		}
+		if len(s.cfg.Behavior.AllowedResources) > 0 && !containsString(s.cfg.Behavior.AllowedResources, name) {
+			return false
+		}
 	}
+	return true
+}
 
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyGeneratorConfig applies the generator and validation settings of a
+// configuration to the process-wide generator and validator. Every setting is
+// applied, defaults included, so state left by a previous configuration is
+// replaced.
+func ApplyGeneratorConfig(cfg *config.Config) error {
+	if cfg.Generator.DeterministicUUIDs {
+		generator.SetDeterministicUUIDs(cfg.Generator.Seed)
+	} else {
+		generator.DisableDeterministicUUIDs()
+	}
+
+	generator.SetExtendedEmail(cfg.Generator.ExtendedEmail)
+	if cfg.Generator.OptionalProbability != nil {
+		generator.SetOptionalProbability(*cfg.Generator.OptionalProbability)
+	} else {
+		generator.SetOptionalProbability(generator.DefaultOptionalProbability)
+	}
+	if cfg.Generator.NullProbability != nil {
+		generator.SetNullProbability(*cfg.Generator.NullProbability)
+	} else {
+		generator.SetNullProbability(generator.DefaultNullProbability)
+	}
+	generator.SetEnumWeights(cfg.Generator.EnumWeights)
+	generator.SetMaxDepth(cfg.Generator.MaxDepth)
+	validation.SetExtendedEmail(cfg.Generator.ExtendedEmail)
+	validation.SetMaxDepth(cfg.Behavior.MaxValidationDepth)
+
+	generator.ResetSemantics()
+	for _, field := range cfg.Generator.SemanticFields {
+		generate, err := generator.SemanticGeneratorFor(field.Pattern, field.Type, field.Values)
+		if err == nil {
+			err = generator.RegisterSemantic(field.Field, generate)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to register semantic field %s: %w", field.Field, err)
+		}
+	}
+	return nil
+}
+
+// AutoSeedConfig builds the auto seed settings from the configuration,
+// generating 5 items per resource when no count is configured
+func AutoSeedConfig(cfg *config.Config) generator.AutoSeedConfig {
+	countRanges := make(map[string]generator.CountRange, len(cfg.State.AutoSeed.CountRanges))
+	for resource, r := range cfg.State.AutoSeed.CountRanges {
+		countRanges[resource] = generator.CountRange{Min: r.Min, Max: r.Max}
+	}
+	seedConfig := generator.AutoSeedConfig{
+		ItemsPerResource: cfg.State.AutoSeed.ItemsPerResource,
+		IncludeResources: cfg.State.AutoSeed.IncludeResources,
+		ExcludeResources: cfg.State.AutoSeed.ExcludeResources,
+		Relationships:    cfg.State.RelationshipTypes(),
+		FKDistribution:   cfg.State.AutoSeed.FKDistribution,
+		CountsByResource: cfg.State.AutoSeed.CountsByResource,
+		CountRanges:      countRanges,
+	}
+	if seedConfig.ItemsPerResource <= 0 {
+		seedConfig.ItemsPerResource = 5
+	}
+	return seedConfig
+}
+
+func StartServer(spec *openapi3.T, cfg *config.Config) error {
+	return NewServer(spec, cfg).ListenAndServe()
+}
+
+// ListenAndServe serves the mock API on the configured address until the
+// server is shut down
+func (s *Server) ListenAndServe() error {
+	addr := s.httpServer.Addr
 	log.Printf("Starting Meridian mock server on http://%s", addr)
 	log.Printf("Web interface available at http://%s/_meridian/", addr)
+	if s.basePath != "" {
+		log.Printf("API served under http://%s%s", addr, s.basePath)
+	}
 
 	return s.httpServer.ListenAndServe()
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stateManager.StopExpiry()
+	if s.webhooks != nil {
+		s.webhooks.close()
+	}
 	if s.httpServer == nil {
 		return nil
 	}
@@ -141,25 +344,42 @@ func (s *Server) createHandler() http.Handler {
 
 	mux.HandleFunc("/_meridian/status", s.handleStatus)
 	mux.HandleFunc("/_meridian/state", s.handleStateAPI)
+	mux.HandleFunc("/_meridian/state/snapshot", s.handleSnapshot)
+	mux.HandleFunc("/_meridian/state/restore", s.handleRestore)
+	mux.HandleFunc("/_meridian/seed", s.handleSeed)
+	mux.HandleFunc("/_meridian/reset", s.handleReset)
+	mux.HandleFunc("/_meridian/resources", s.handleResources)
+	mux.HandleFunc("/_meridian/clock", s.handleClock)
 	mux.HandleFunc("/_meridian/spec", s.handleSpec)
+	mux.HandleFunc(eventsPath, s.handleEvents)
 	mux.HandleFunc("/_meridian/", s.handleWebUI)
 	mux.HandleFunc("/", s.handleAPI)
 
 	var handler http.Handler = mux
 
-	if s.cfg.Behavior.Compression {
-		handler = s.compressionMiddleware(handler)
+	if s.cfg.Behavior.ValidateResponses {
+		handler = s.responseValidationMiddleware(handler)
 	}
 
+	// Caching sits inside compression, so ETags and cache entries are for the
+	// uncompressed body whatever encoding the client accepts
 	if s.cfg.Behavior.Caching.Enabled {
 		handler = s.cachingMiddleware(handler)
 	}
 
+	if s.cfg.Behavior.Compression.Enabled {
+		handler = s.compressionMiddleware(handler)
+	}
+
+	if s.cfg.Behavior.Drip.Enabled {
+		handler = s.dripMiddleware(handler)
+	}
+
 	if s.cfg.Behavior.RateLimit.Enabled {
 		handler = s.rateLimitMiddleware(handler)
 	}
 
-	if s.cfg.Behavior.Errors.Enabled {
+	if s.cfg.Behavior.Errors.Enabled || s.cfg.Behavior.Errors.Network.Enabled {
 		handler = s.errorSimulationMiddleware(handler)
 	}
 
@@ -242,39 +462,376 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":  "online",
-		"version": "1.0.0",
-		"spec":    specInfo,
+		"status":    "online",
+		"version":   "1.0.0",
+		"spec":      specInfo,
+		"base_path": s.basePath,
+		"dropped_events": map[string]uint64{
+			"events":   s.changes.dropped.Load(),
+			"webhooks": s.webhooksDropped.Load(),
+		},
 	})
 }
 
+// handleStateAPI exports the state on GET, imports an export on POST
+// (merging into the current state with ?merge=true) and resets it on DELETE
 func (s *Server) handleStateAPI(w http.ResponseWriter, r *http.Request) {
-	data, err := s.stateManager.Export()
+	switch r.Method {
+	case http.MethodGet:
+		// Resources are streamed, so a failure once the response has started
+		// can only be logged
+		w.Header().Set("Content-Type", "application/json")
+		out := &trackingWriter{w: w}
+		if err := s.stateManager.ExportTo(out); err != nil {
+			if !out.written {
+				http.Error(w, fmt.Sprintf("failed to export state: %v", err), http.StatusInternalServerError)
+				return
+			}
+			log.Printf("Error exporting state: %v", err)
+		}
+
+	case http.MethodPost:
+		var data state.ExportData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			s.writeError(w, r, http.StatusBadRequest, "invalid_json", "Failed to parse state export")
+			return
+		}
+		if resourceType, ok := invalidExportResource(&data); !ok {
+			s.writeErrorWithFields(w, r, http.StatusBadRequest, "invalid_state", "Resources must be objects", map[string]interface{}{
+				"resource": resourceType,
+			})
+			return
+		}
+
+		merge := r.URL.Query().Get("merge") == "true"
+		if err := s.stateManager.Import(&data, merge); err != nil {
+			http.Error(w, fmt.Sprintf("failed to import state: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if err := s.stateManager.Reset(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to reset state: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// invalidExportResource finds a resource type with items that aren't objects,
+// which can't be imported
+func invalidExportResource(data *state.ExportData) (string, bool) {
+	for resourceType, items := range data.Resources {
+		for _, item := range items {
+			if _, ok := item.(map[string]interface{}); !ok {
+				return resourceType, false
+			}
+		}
+	}
+	return "", true
+}
+
+// trackingWriter records whether anything was written through it
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
+
+// handleSnapshot lists the saved snapshots on GET and saves the current state
+// as ?name= on POST
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		names, err := s.stateManager.Snapshots()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to list snapshots: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"snapshots": names})
+
+	case http.MethodPost:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			s.writeError(w, r, http.StatusBadRequest, "missing_name", "Snapshot name required")
+			return
+		}
+		if err := s.stateManager.SaveSnapshot(name); err != nil {
+			http.Error(w, fmt.Sprintf("failed to save snapshot: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"name": name})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRestore replaces the state with the snapshot named by ?name=
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		s.writeError(w, r, http.StatusBadRequest, "missing_name", "Snapshot name required")
+		return
+	}
+
+	if err := s.stateManager.RestoreSnapshot(name); err != nil {
+		if errors.Is(err, state.ErrSnapshotNotFound) {
+			s.writeErrorWithFields(w, r, http.StatusNotFound, "not_found", "Snapshot not found", map[string]interface{}{
+				"name": name,
+			})
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to restore snapshot: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// nextID returns an ID for a new resource from the server clock. IDs keep
+// increasing while the clock is frozen or set back.
+func (s *Server) nextID() int64 {
+	for {
+		last := s.lastID.Load()
+		id := s.clock.Now().UnixNano()
+		if id <= last {
+			id = last + 1
+		}
+		if s.lastID.CompareAndSwap(last, id) {
+			return id
+		}
+	}
+}
+
+// handleResources lists the resource types in state with their counts,
+// without the resources themselves
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	counts, err := s.stateManager.ResourceCounts()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to export state: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("failed to count resources: %v", err), http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"resources": counts})
+}
+
+// handleReset replaces the state with the seed file the server was started
+// with, or clears it when there is none
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.seed == nil {
+		if err := s.stateManager.Reset(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to reset state: %v", err), http.StatusInternalServerError)
+			return
+		}
+	} else if err := s.stateManager.Import(s.seed, false); err != nil {
+		http.Error(w, fmt.Sprintf("failed to import seed data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// seedRequest configures a run of the auto seeder, with the settings of
+// state.auto_seed
+type seedRequest struct {
+	ItemsPerResource int                             `json:"items_per_resource"`
+	IncludeResources []string                        `json:"include_resources"`
+	ExcludeResources []string                        `json:"exclude_resources"`
+	CountsByResource map[string]int                  `json:"counts_by_resource"`
+	CountRanges      map[string]generator.CountRange `json:"count_ranges"`
+	FKDistribution   string                          `json:"fk_distribution"`
+}
+
+// handleSeed generates seed data for the loaded spec and imports it, replacing
+// the state unless merge=true. It responds with the number of resources
+// generated per resource type.
+func (s *Server) handleSeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// An empty body seeds with the configured defaults, and each field given
+	// overrides its configured setting
+	var req seedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		s.writeError(w, r, http.StatusBadRequest, "invalid_json", "Failed to parse request body")
+		return
+	}
+	if !generator.IsFKDistribution(req.FKDistribution) {
+		s.writeError(w, r, http.StatusBadRequest, "invalid_seed", "fk_distribution must be one of: even, random, skewed")
+		return
+	}
+
+	seedConfig := AutoSeedConfig(s.cfg)
+	if req.ItemsPerResource > 0 {
+		seedConfig.ItemsPerResource = req.ItemsPerResource
+	}
+	if req.IncludeResources != nil {
+		seedConfig.IncludeResources = req.IncludeResources
+	}
+	if req.ExcludeResources != nil {
+		seedConfig.ExcludeResources = req.ExcludeResources
+	}
+	if req.CountsByResource != nil {
+		seedConfig.CountsByResource = req.CountsByResource
+	}
+	if req.CountRanges != nil {
+		seedConfig.CountRanges = req.CountRanges
+	}
+	if req.FKDistribution != "" {
+		seedConfig.FKDistribution = req.FKDistribution
+	}
+
+	seeder := generator.NewAutoSeeder(s.spec, seedConfig)
+	resources, err := seeder.Generate()
+	if err != nil {
+		s.writeErrorWithFields(w, r, http.StatusUnprocessableEntity, "seed_failed", "Failed to generate seed data", map[string]interface{}{
+			"reason": err.Error(),
+		})
+		return
+	}
+
+	merge := r.URL.Query().Get("merge") == "true"
+	if err := s.stateManager.Import(state.NewExportData(resources, seeder.Relations()), merge); err != nil {
+		http.Error(w, fmt.Sprintf("failed to import seed data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	counts := make(map[string]int, len(resources))
+	for resourceType, items := range resources {
+		counts[resourceType] = len(items)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"counts": counts})
+}
+
+// clockRequest sets the server clock to a fixed time or to an offset from the
+// system time
+type clockRequest struct {
+	Time   string `json:"time"`
+	Offset string `json:"offset"`
+}
+
+// handleClock reports the server clock on GET, freezes or shifts it on POST
+// and makes it follow the system clock again on DELETE
+func (s *Server) handleClock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+
+	case http.MethodPost:
+		var req clockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, r, http.StatusBadRequest, "invalid_json", "Failed to parse request body")
+			return
+		}
+		if (req.Time == "") == (req.Offset == "") {
+			s.writeError(w, r, http.StatusBadRequest, "invalid_clock", "Exactly one of time or offset required")
+			return
+		}
+
+		if req.Time != "" {
+			t, err := time.Parse(time.RFC3339, req.Time)
+			if err != nil {
+				s.writeError(w, r, http.StatusBadRequest, "invalid_clock", "Time must be in RFC 3339 format")
+				return
+			}
+			s.clock.Freeze(t)
+		} else {
+			offset, err := time.ParseDuration(req.Offset)
+			if err != nil {
+				s.writeError(w, r, http.StatusBadRequest, "invalid_clock", "Offset must be a duration such as -24h")
+				return
+			}
+			s.clock.Shift(offset)
+		}
+
+	case http.MethodDelete:
+		s.clock.Reset()
+		w.WriteHeader(http.StatusNoContent)
+		return
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"now":    s.clock.Now().UTC().Format(time.RFC3339Nano),
+		"frozen": s.clock.Frozen(),
+		"offset": s.clock.Offset().String(),
+	})
+}
+
+// handleEvents streams resource changes as Server-Sent Events until the
+// client disconnects
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	changes, cancel := s.changes.stream()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case change := <-changes.items:
+			data, err := json.Marshal(change)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", change.Event, data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
 }
 
 func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.spec)
+	writeJSON(w, http.StatusOK, s.spec)
 }
 
 func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
+	path, ok := s.stripBasePath(r.URL.Path)
 	method := r.Method
 
-	pathItem, pathParams := s.matchPath(path)
+	var pathItem *openapi3.PathItem
+	var pathParams map[string]string
+	if ok {
+		pathItem, pathParams = s.matchPath(path)
+	}
 	if pathItem == nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Path not found",
-			"code":  "not_found",
-			"path":  path,
+		s.writeErrorWithFields(w, r, http.StatusNotFound, "not_found", "Path not found", map[string]interface{}{
+			"path": r.URL.Path,
 		})
 		return
 	}
@@ -305,23 +862,83 @@ func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	results := s.validator.ValidatePathParams(op, pathItem, pathParams)
+	if errs := results.Errors(); len(errs) > 0 {
+		// A malformed ID can't address an existing resource, so specs may
+		// prefer to report it as not found
+		if s.cfg.Behavior.InvalidPathParamStatus == http.StatusNotFound {
+			s.writeErrorWithFields(w, r, http.StatusNotFound, "not_found", "Resource not found", map[string]interface{}{
+				"details": errs,
+			})
+			return
+		}
+		s.writeErrorWithFields(w, r, http.StatusBadRequest, "invalid_parameter", "Invalid path parameters", map[string]interface{}{
+			"details": errs,
+		})
+		return
+	}
+
+	// Warnings don't block the request but are reported to the client
+	if warnings := results.Warnings(); len(warnings) > 0 {
+		w.Header().Set("X-Meridian-Validation-Warnings", warnings.Error())
+	}
+
 	resourceName, nestedInfo := ExtractResourceInfo(path, pathParams)
+	if !s.resourceAllowed(resourceName, nestedInfo) {
+		s.writeErrorWithFields(w, r, http.StatusForbidden, "forbidden", "Resource not available", map[string]interface{}{
+			"resource": resourceName,
+		})
+		return
+	}
+
+	// Prefer: code=NNN and Prefer: example=name pick a response from the spec
+	if s.servePreferred(w, r, op) {
+		return
+	}
+
+	if s.shouldInjectInvalid(r) {
+		s.serveInvalid(w, op, func(w http.ResponseWriter) {
+			s.serveOperation(w, r, op, resourceName, pathParams, nestedInfo)
+		})
+		return
+	}
+
+	s.serveOperation(w, r, op, resourceName, pathParams, nestedInfo)
+}
+
+// serveOperation produces the response of a matched operation
+func (s *Server) serveOperation(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	if tmpl := s.responseTemplate(op); tmpl != "" {
+		s.handleTemplate(w, r, op, tmpl, pathParams)
+		return
+	}
+
+	if statusCode, mediaType, ok := binaryResponse(op); ok {
+		s.handleBinary(w, r, op, statusCode, mediaType)
+		return
+	}
+
 	if resourceName == "" {
 		http.Error(w, "invalid path", http.StatusBadRequest)
 		return
 	}
 
-	switch method {
+	if action, ok := s.action(op); ok {
+		s.handleAction(w, r, action, resourceName, pathParams, nestedInfo)
+		return
+	}
+
+	switch r.Method {
 	case http.MethodGet:
-		s.handleGet(w, r, resourceName, pathParams, nestedInfo)
+		s.handleGet(w, r, op, resourceName, pathParams, nestedInfo)
 	case http.MethodPost:
-		s.handlePost(w, r, resourceName, nestedInfo)
+		s.handlePost(w, r, op, resourceName, pathParams, nestedInfo)
 	case http.MethodPut:
-		s.handlePut(w, r, resourceName, pathParams, nestedInfo)
+		s.handlePut(w, r, op, resourceName, pathParams, nestedInfo)
 	case http.MethodDelete:
-		s.handleDelete(w, r, resourceName, pathParams, nestedInfo)
+		s.handleDelete(w, r, op, resourceName, pathParams, nestedInfo)
 	case http.MethodPatch:
-		s.handlePatch(w, r, resourceName, pathParams, nestedInfo)
+		s.handlePatch(w, r, op, resourceName, pathParams, nestedInfo)
 	}
 }
 
@@ -329,25 +946,128 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.handler.ServeHTTP(w, r)
 }
 
-func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
-	// For nested resources, use the child ID if present
-	var resourceID string
-	if nestedInfo.IsNested && nestedInfo.ChildID != "" {
-		resourceID = nestedInfo.ChildID
-	} else {
-		resourceID = pathParams["id"]
-		if resourceID == "" {
-			for key, value := range pathParams {
-				if strings.HasSuffix(strings.ToLower(key), "id") && key != nestedInfo.ParentIDParam {
-					resourceID = value
-					break
-				}
-			}
+// defaultStatuses lists, per method, the success status codes that fit the
+// method's intent, best first. The first one is used when the operation
+// defines no 2xx response.
+var defaultStatuses = map[string][]int{
+	http.MethodGet:    {http.StatusOK},
+	http.MethodPost:   {http.StatusCreated, http.StatusOK, http.StatusAccepted, http.StatusNoContent},
+	http.MethodPut:    {http.StatusOK, http.StatusNoContent, http.StatusAccepted, http.StatusCreated},
+	http.MethodPatch:  {http.StatusOK, http.StatusNoContent, http.StatusAccepted},
+	http.MethodDelete: {http.StatusNoContent, http.StatusOK, http.StatusAccepted},
+}
+
+// successStatus chooses the status code of a successful CRUD response from
+// the 2xx responses the operation defines
+func successStatus(op *openapi3.Operation, method string) int {
+	preferred := defaultStatuses[method]
+	if len(preferred) == 0 {
+		preferred = []int{http.StatusOK}
+	}
+	if op == nil || op.Responses == nil {
+		return preferred[0]
+	}
+
+	for _, code := range preferred {
+		if op.Responses.Value(strconv.Itoa(code)) != nil {
+			return code
 		}
 	}
+	if code, _ := firstSuccessResponse(op); code != 0 {
+		return code
+	}
+	return preferred[0]
+}
+
+// decodeBody decodes a request body into v: multipart/form-data forms and
+// XML the operation accepts are read as objects, anything else as JSON, or
+// as a JSON:API document when behavior.jsonapi is set. Relationships in a
+// JSON:API document are stored in the foreign key fields of existing, the
+// resource being updated, if there is one.
+func (s *Server) decodeBody(r *http.Request, op *openapi3.Operation, existing map[string]interface{}, v *map[string]interface{}) error {
+	if isMultipartRequest(r) {
+		data, err := decodeMultipart(r, op)
+		if err != nil {
+			return err
+		}
+		*v = data
+		return nil
+	}
+
+	if schema, ok := xmlRequest(r, op); ok {
+		data, err := decodeXML(r.Body, schema)
+		if err != nil {
+			return err
+		}
+		*v = data
+		return nil
+	}
+
+	if !s.cfg.Behavior.JSONAPI {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return err
+	}
+	schema := jsonAPIRequestSchema(op)
+	data, err := fromJSONAPI(body, func(relationship string) string {
+		return foreignKeyFor(relationship, existing, schema)
+	})
+	if err != nil {
+		return err
+	}
+	*v = data
+	return nil
+}
+
+// writeBodyError answers a request whose body decodeBody rejected
+func (s *Server) writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var formErr *formError
+	if errors.As(err, &formErr) {
+		s.writeErrorWithFields(w, r, http.StatusBadRequest, "invalid_form", "Invalid form data", map[string]interface{}{
+			"details": formErr.details,
+		})
+		return
+	}
+	s.writeError(w, r, http.StatusBadRequest, "invalid_json", "Failed to parse request body")
+}
+
+// writeResult writes a successful CRUD response, leaving out the body when
+// the status doesn't allow one. Responses are XML when the operation
+// declares XML for the status, see xmlResponse, and JSON:API documents when
+// behavior.jsonapi is set. The fields in redact_fields are masked.
+func (s *Server) writeResult(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, status int, data interface{}) {
+	if status == http.StatusNoContent || status == http.StatusResetContent {
+		w.WriteHeader(status)
+		return
+	}
+	data = s.redact(data)
+	if mediaType, schema, ok := xmlResponse(w, r, op, status); ok {
+		writeXML(w, status, mediaType, schema, resourceName, data)
+		return
+	}
+	if s.cfg.Behavior.JSONAPI {
+		writeJSONAPI(w, r, resourceName, status, data)
+		return
+	}
+	s.writeData(w, status, data)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	// For nested resources, use the child ID if present
+	resourceID := resolveResourceID(pathParams, nestedInfo)
 
 	if resourceID == "" {
-		data, err := s.stateManager.GetResources(resourceName)
+		// Nested collections only load the items of the parent
+		var data []interface{}
+		var err error
+		if nestedInfo.IsNested && nestedInfo.ParentID != "" {
+			data, err = s.childrenOf(resourceName, nestedInfo)
+		} else {
+			data, err = s.stateManager.GetResources(resourceName)
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("failed to get resources: %v", err), http.StatusInternalServerError)
 			return
@@ -357,57 +1077,123 @@ func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, resourceName
 			data = []interface{}{}
 		}
 
-		// Filter by parent ID for nested resources
-		if nestedInfo.IsNested && nestedInfo.ParentID != "" {
-			data = s.filterByParentID(data, nestedInfo)
+		if query := s.searchQuery(r); query != "" {
+			data = s.search(data, resourceName, query)
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(data)
+		response := selectFields(r, s.embedIncludes(r, resourceName, s.coerceResponse(op, data)))
+		addVary(w.Header(), "Accept")
+		if items, ok := response.([]interface{}); ok && wantsCSV(r) {
+			writeCSV(w, successStatus(op, r.Method), items)
+			return
+		}
+		s.writeResult(w, r, op, resourceName, successStatus(op, r.Method), response)
 		return
 	}
 
-	data, err := s.stateManager.GetResource(resourceName, resourceID)
+	// Subscribe before reading so a change between the read and the wait
+	// isn't missed
+	wait, waiting := preferWait(r)
+	var changed <-chan struct{}
+	if waiting {
+		var unsubscribe func()
+		changed, unsubscribe = s.changes.subscribe(resourceName, resourceID)
+		defer unsubscribe()
+	}
+
+	data, updatedAt, err := s.stateManager.GetResourceVersion(resourceName, resourceID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource not found",
-			"code":  "not_found",
-		})
+		if s.cfg.Behavior.GenerateOnMiss {
+			if generated, ok := generateResource(op, resourceID, pathParams); ok {
+				if s.cfg.Behavior.PersistGenerated {
+					s.persistGenerated(resourceName, resourceID, generated)
+				}
+				s.writeResult(w, r, op, resourceName, successStatus(op, r.Method), selectFields(r, generated))
+				return
+			}
+		}
+
+		s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 		return
 	}
 
 	// Verify the resource belongs to the parent for nested resources
 	if nestedInfo.IsNested && nestedInfo.ParentID != "" {
 		if !s.belongsToParent(data, nestedInfo) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	if waiting {
+		w.Header().Set("Preference-Applied", fmt.Sprintf("wait=%d", int(wait.Seconds())))
+		if awaitChange(r, changed, wait) {
+			data, updatedAt, err = s.stateManager.GetResourceVersion(resourceName, resourceID)
+			if err != nil {
+				s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
+				return
+			}
+		}
+	}
+
+	// The ETag identifies the stored version, whatever the query parameters
+	etag := resourceETag(data, updatedAt)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data = s.embedIncludes(r, resourceName, s.coerceResponse(op, data))
+	s.writeResult(w, r, op, resourceName, successStatus(op, r.Method), selectFields(r, data))
 }
 
-// filterByParentID filters a list of resources by parent ID
-func (s *Server) filterByParentID(data []interface{}, nestedInfo *NestedResourceInfo) []interface{} {
-	if nestedInfo == nil || !nestedInfo.IsNested || nestedInfo.ParentID == "" {
-		return data
+// childrenOf returns the resources of resourceName that belong to the parent
+// of a nested path, looking them up by each of the foreign key fields that
+// belongsToParent accepts
+func (s *Server) childrenOf(resourceName string, nestedInfo *NestedResourceInfo) ([]interface{}, error) {
+	children := make([]interface{}, 0)
+	queried := make(map[string]bool)
+	found := make(map[string]bool)
+	for _, field := range parentKeyFields(nestedInfo) {
+		if queried[field] {
+			continue
+		}
+		queried[field] = true
+
+		items, err := s.stateManager.GetResourcesByField(resourceName, field, nestedInfo.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok {
+				id := fmt.Sprintf("%v", obj["id"])
+				if found[id] {
+					continue
+				}
+				found[id] = true
+			}
+			children = append(children, item)
+		}
 	}
+	return children, nil
+}
 
-	filtered := make([]interface{}, 0)
-	for _, item := range data {
-		if s.belongsToParent(item, nestedInfo) {
-			filtered = append(filtered, item)
+// parentKeyFields returns the fields of a child resource that may hold the ID
+// of its parent
+func parentKeyFields(nestedInfo *NestedResourceInfo) []string {
+	fields := make([]string, 0, 4)
+	for _, field := range []string{
+		nestedInfo.ForeignKeyField,
+		nestedInfo.ParentResource + "_id",
+		nestedInfo.ParentResource + "Id",
+		nestedInfo.ParentIDParam,
+	} {
+		if field != "" {
+			fields = append(fields, field)
 		}
 	}
-	return filtered
+	return fields
 }
 
 // belongsToParent checks if a resource belongs to the specified parent
@@ -422,14 +1208,7 @@ func (s *Server) belongsToParent(item interface{}, nestedInfo *NestedResourceInf
 	}
 
 	// Check common foreign key patterns
-	foreignKeyFields := []string{
-		nestedInfo.ForeignKeyField,
-		nestedInfo.ParentResource + "_id",
-		nestedInfo.ParentResource + "Id",
-		nestedInfo.ParentIDParam,
-	}
-
-	for _, field := range foreignKeyFields {
+	for _, field := range parentKeyFields(nestedInfo) {
 		if val, exists := obj[field]; exists {
 			if fmt.Sprintf("%v", val) == nestedInfo.ParentID {
 				return true
@@ -440,20 +1219,21 @@ func (s *Server) belongsToParent(item interface{}, nestedInfo *NestedResourceInf
 	return false
 }
 
-func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, resourceName string, nestedInfo *NestedResourceInfo) {
+func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
 	var data map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Failed to parse request body",
-			"code":  "invalid_json",
-		})
+	if err := s.decodeBody(r, op, nil, &data); err != nil {
+		s.writeBodyError(w, r, err)
 		return
 	}
 
+	// Keep the body as sent for callback URL expressions
+	requestBody := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		requestBody[k] = v
+	}
+
 	if _, ok := data["id"]; !ok {
-		data["id"] = fmt.Sprintf("%d", time.Now().UnixNano())
+		data["id"] = fmt.Sprintf("%d", s.nextID())
 	}
 
 	// Automatically set foreign key for nested resources
@@ -461,64 +1241,72 @@ func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, resourceName
 		data[nestedInfo.ForeignKeyField] = nestedInfo.ParentID
 	}
 
+	if !s.checkUnique(w, r, resourceName, fmt.Sprintf("%v", data["id"]), data) {
+		return
+	}
+
 	if err := s.stateManager.AddResource(resourceName, data); err != nil {
+		if errors.Is(err, state.ErrResourceLimit) {
+			s.writeError(w, r, http.StatusInsufficientStorage, "resource_limit", fmt.Sprintf("Cannot store more than %d %s", s.cfg.State.MaxItems, resourceName))
+			return
+		}
 		http.Error(w, fmt.Sprintf("failed to add resource: %v", err), http.StatusInternalServerError)
 		return
 	}
+	// Record the edge from the parent, so exports include the relationship
+	if nestedInfo.IsNested && nestedInfo.ParentID != "" {
+		relType := s.cfg.State.RelationshipTypes()[nestedInfo.ParentResource][resourceName]
+		if relType == "" {
+			relType = "one_to_many"
+		}
+		if err := s.stateManager.AddRelation(nestedInfo.ParentResource, nestedInfo.ParentID, resourceName, fmt.Sprintf("%v", data["id"]), relType); err != nil {
+			log.Printf("Warning: relationship from %s %s not recorded: %v", nestedInfo.ParentResource, nestedInfo.ParentID, err)
+		}
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(data)
+	status := successStatus(op, r.Method)
+	s.changes.publish(eventCreated, resourceName, fmt.Sprintf("%v", data["id"]))
+	s.fireWebhooks(eventCreated, resourceName, fmt.Sprintf("%v", data["id"]), data)
+
+	if s.cfg.Behavior.Callbacks && len(op.Callbacks) > 0 {
+		s.fireCallbacks(op, callbackContext{
+			request:      r,
+			pathParams:   pathParams,
+			requestBody:  requestBody,
+			statusCode:   status,
+			responseBody: data,
+		})
+	}
+
+	// The new resource lives under the collection it was posted to, which
+	// already includes any parent IDs of nested routes
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+url.PathEscape(fmt.Sprintf("%v", data["id"])))
+
+	s.writeResult(w, r, op, resourceName, status, data)
 }
 
-func (s *Server) handlePut(w http.ResponseWriter, r *http.Request, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
-	var resourceID string
-	if nestedInfo.IsNested && nestedInfo.ChildID != "" {
-		resourceID = nestedInfo.ChildID
-	} else {
-		resourceID = pathParams["id"]
-		if resourceID == "" {
-			for key, value := range pathParams {
-				if strings.HasSuffix(strings.ToLower(key), "id") && key != nestedInfo.ParentIDParam {
-					resourceID = value
-					break
-				}
-			}
-		}
-	}
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	resourceID := resolveResourceID(pathParams, nestedInfo)
 
 	if resourceID == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource ID required",
-			"code":  "missing_id",
-		})
+		s.writeError(w, r, http.StatusBadRequest, "missing_id", "Resource ID required")
 		return
 	}
 
+	existing, err := s.stateManager.GetResource(resourceName, resourceID)
+
 	// Verify the resource belongs to the parent for nested resources
 	if nestedInfo.IsNested && nestedInfo.ParentID != "" {
-		existing, err := s.stateManager.GetResource(resourceName, resourceID)
 		if err == nil && !s.belongsToParent(existing, nestedInfo) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 	}
 
+	existingMap, _ := existing.(map[string]interface{})
 	var data map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Failed to parse request body",
-			"code":  "invalid_json",
-		})
+	if err := s.decodeBody(r, op, existingMap, &data); err != nil {
+		s.writeBodyError(w, r, err)
 		return
 	}
 
@@ -529,91 +1317,58 @@ func (s *Server) handlePut(w http.ResponseWriter, r *http.Request, resourceName
 		data[nestedInfo.ForeignKeyField] = nestedInfo.ParentID
 	}
 
+	if !s.checkUnique(w, r, resourceName, resourceID, data) {
+		return
+	}
+
 	if err := s.stateManager.UpdateResource(resourceName, resourceID, data); err != nil {
 		if err.Error() == "resource not found" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 		http.Error(w, fmt.Sprintf("failed to update resource: %v", err), http.StatusInternalServerError)
 		return
 	}
+	s.changes.publish(eventUpdated, resourceName, resourceID)
+	s.fireWebhooks(eventUpdated, resourceName, resourceID, data)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	s.writeResult(w, r, op, resourceName, successStatus(op, r.Method), data)
 }
 
-func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
-	var resourceID string
-	if nestedInfo.IsNested && nestedInfo.ChildID != "" {
-		resourceID = nestedInfo.ChildID
-	} else {
-		resourceID = pathParams["id"]
-		if resourceID == "" {
-			for key, value := range pathParams {
-				if strings.HasSuffix(strings.ToLower(key), "id") && key != nestedInfo.ParentIDParam {
-					resourceID = value
-					break
-				}
-			}
-		}
-	}
+func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	resourceID := resolveResourceID(pathParams, nestedInfo)
 
 	if resourceID == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource ID required",
-			"code":  "missing_id",
-		})
+		s.writeError(w, r, http.StatusBadRequest, "missing_id", "Resource ID required")
 		return
 	}
 
 	existing, err := s.stateManager.GetResource(resourceName, resourceID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource not found",
-			"code":  "not_found",
-		})
+		s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 		return
 	}
 
 	// Verify the resource belongs to the parent for nested resources
 	if nestedInfo.IsNested && nestedInfo.ParentID != "" {
 		if !s.belongsToParent(existing, nestedInfo) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 	}
 
-	var patchData map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&patchData); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Failed to parse request body",
-			"code":  "invalid_json",
-		})
-		return
-	}
-
 	existingMap, ok := existing.(map[string]interface{})
 	if !ok {
 		http.Error(w, "invalid resource format", http.StatusInternalServerError)
 		return
 	}
 
+	var patchData map[string]interface{}
+	if err := s.decodeBody(r, op, existingMap, &patchData); err != nil {
+		s.writeBodyError(w, r, err)
+		return
+	}
+
 	for key, value := range patchData {
 		existingMap[key] = value
 	}
@@ -623,38 +1378,25 @@ func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request, resourceNam
 		existingMap[nestedInfo.ForeignKeyField] = nestedInfo.ParentID
 	}
 
+	if !s.checkUnique(w, r, resourceName, resourceID, existingMap) {
+		return
+	}
+
 	if err := s.stateManager.UpdateResource(resourceName, resourceID, existingMap); err != nil {
 		http.Error(w, fmt.Sprintf("failed to update resource: %v", err), http.StatusInternalServerError)
 		return
 	}
+	s.changes.publish(eventUpdated, resourceName, resourceID)
+	s.fireWebhooks(eventUpdated, resourceName, resourceID, existingMap)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(existingMap)
+	s.writeResult(w, r, op, resourceName, successStatus(op, r.Method), existingMap)
 }
 
-func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
-	var resourceID string
-	if nestedInfo.IsNested && nestedInfo.ChildID != "" {
-		resourceID = nestedInfo.ChildID
-	} else {
-		resourceID = pathParams["id"]
-		if resourceID == "" {
-			for key, value := range pathParams {
-				if strings.HasSuffix(strings.ToLower(key), "id") && key != nestedInfo.ParentIDParam {
-					resourceID = value
-					break
-				}
-			}
-		}
-	}
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, resourceName string, pathParams map[string]string, nestedInfo *NestedResourceInfo) {
+	resourceID := resolveResourceID(pathParams, nestedInfo)
 
 	if resourceID == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Resource ID required",
-			"code":  "missing_id",
-		})
+		s.writeError(w, r, http.StatusBadRequest, "missing_id", "Resource ID required")
 		return
 	}
 
@@ -662,29 +1404,36 @@ func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request, resourceNa
 	if nestedInfo.IsNested && nestedInfo.ParentID != "" {
 		existing, err := s.stateManager.GetResource(resourceName, resourceID)
 		if err == nil && !s.belongsToParent(existing, nestedInfo) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 	}
 
+	// Keep the resource body for webhook subscribers and for specs that
+	// respond to a delete with the removed resource
+	status := successStatus(op, r.Method)
+	var deleted interface{}
+	if len(s.cfg.Behavior.Webhooks) > 0 || status != http.StatusNoContent {
+		deleted, _ = s.stateManager.GetResource(resourceName, resourceID)
+	}
+
 	if err := s.stateManager.DeleteResource(resourceName, resourceID); err != nil {
 		if err.Error() == "resource not found" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"error": "Resource not found",
-				"code":  "not_found",
-			})
+			s.writeError(w, r, http.StatusNotFound, "not_found", "Resource not found")
 			return
 		}
 		http.Error(w, fmt.Sprintf("failed to delete resource: %v", err), http.StatusInternalServerError)
 		return
 	}
+	s.changes.publish(eventDeleted, resourceName, resourceID)
+	s.fireWebhooks(eventDeleted, resourceName, resourceID, deleted)
 
-	w.WriteHeader(http.StatusNoContent)
+	s.writeResult(w, r, op, resourceName, status, deleted)
+}
+
+// writeJSON encodes a value as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
 }
@@ -2,13 +2,18 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/felipevolpatto/meridian/internal/generator"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -183,6 +188,51 @@ func TestNewServer(t *testing.T) {
 	assert.NotNil(t, server.stateManager)
 }
 
+func TestListenAndServe_Shutdown(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Server.Port = 0
+	server := NewServer(createTestSpec(), cfg)
+
+	served := make(chan error, 1)
+	go func() { served <- server.ListenAndServe() }()
+
+	// Shutting down the server stops the listener it serves on
+	require.NoError(t, server.Shutdown(context.Background()))
+	select {
+	case err := <-served:
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server still serving after shutdown")
+	}
+}
+
+func TestApplyGeneratorConfig(t *testing.T) {
+	stringSchema := openapi3.NewStringSchema().NewRef()
+
+	cfg := config.New()
+	cfg.Generator.SemanticFields = []config.SemanticFieldConfig{{Field: "^plan$", Values: []string{"gold"}}}
+	require.NoError(t, ApplyGeneratorConfig(cfg))
+	value, err := generator.GenerateAdvancedData(stringSchema, "plan")
+	require.NoError(t, err)
+	assert.Equal(t, "gold", value)
+
+	// A configuration without the field drops it again
+	require.NoError(t, ApplyGeneratorConfig(config.New()))
+	for i := 0; i < 20; i++ {
+		value, err = generator.GenerateAdvancedData(stringSchema, "plan")
+		require.NoError(t, err)
+		if value != "gold" {
+			return
+		}
+	}
+	t.Errorf("Expected the semantic field to be reset, got %v every time", value)
+}
+
 func TestServerHandlers(t *testing.T) {
 	// Create temp db file
 	tmpFile, err := os.CreateTemp("", "test-*.db")
@@ -318,6 +368,190 @@ func TestServerHandlers(t *testing.T) {
 	})
 }
 
+func TestPostSetsLocation(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	handler := NewServer(createTestSpec(), createTestConfig(tmpFile.Name())).createHandler()
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+		return w
+	}
+
+	w := post(`{"id": "a b", "name": "Alice"}`)
+	assert.Equal(t, "/users/a%20b", w.Header().Get("Location"))
+
+	// Generated IDs are used as well
+	w = post(`{"name": "Bob"}`)
+	var user map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&user))
+	assert.Equal(t, "/users/"+user["id"].(string), w.Header().Get("Location"))
+
+	// The location resolves to the new resource
+	w2 := httptest.NewRecorder()
+	handler.ServeHTTP(w2, httptest.NewRequest(http.MethodGet, w.Header().Get("Location"), nil))
+	assert.Equal(t, http.StatusOK, w2.Code)
+}
+
+func TestReadOnlyID(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	id := openapi3.NewStringSchema()
+	id.ReadOnly = true
+	user := openapi3.NewObjectSchema().WithProperty("id", id).WithProperty("name", openapi3.NewStringSchema())
+	user.Required = []string{"id", "name"}
+
+	spec := createTestSpec()
+	spec.Paths.Value("/users").Post.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(user)}
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	// The client can't send the server-assigned id, so the body is valid
+	body := []byte(`{"name": "Alice"}`)
+	assert.Empty(t, server.validator.ValidateRequest(http.MethodPost, "/users", nil, nil, body))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// ...and it's present when the user is read back
+	location := w.Header().Get("Location")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, location, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&created))
+	assert.NotEmpty(t, created["id"])
+	assert.Equal(t, "Alice", created["name"])
+}
+
+func TestBasePath(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	get := func(handler http.Handler, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("from spec servers", func(t *testing.T) {
+		spec := createTestSpec()
+		spec.Servers = openapi3.Servers{
+			{URL: "https://example.com/api/v1/"},
+			{URL: "http://localhost/other"},
+		}
+		server := NewServer(spec, createTestConfig(tmpFile.Name()))
+		require.NoError(t, server.stateManager.AddResource("users", map[string]interface{}{"id": "1", "name": "Alice"}))
+		handler := server.createHandler()
+
+		assert.Equal(t, http.StatusOK, get(handler, "/api/v1/users").Code)
+		assert.Equal(t, http.StatusOK, get(handler, "/api/v1/users/1").Code)
+		assert.Equal(t, http.StatusNotFound, get(handler, "/users").Code)
+		assert.Equal(t, http.StatusNotFound, get(handler, "/api/v1users").Code)
+		assert.Equal(t, http.StatusNotFound, get(handler, "/other/users").Code)
+		assert.Equal(t, http.StatusOK, get(handler, "/_meridian/status").Code)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewBufferString(`{"id": "2", "name": "Bob"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/api/v1/users/2", w.Header().Get("Location"))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		spec := createTestSpec()
+		spec.Servers = openapi3.Servers{{URL: "https://example.com/api/v1"}}
+		cfg := createTestConfig(tmpFile.Name())
+		cfg.Server.BasePath = "/mock/"
+		handler := NewServer(spec, cfg).createHandler()
+
+		assert.Equal(t, http.StatusOK, get(handler, "/mock/users").Code)
+		assert.Equal(t, http.StatusNotFound, get(handler, "/api/v1/users").Code)
+	})
+
+	t.Run("root server", func(t *testing.T) {
+		spec := createTestSpec()
+		spec.Servers = openapi3.Servers{{URL: "https://example.com"}}
+		handler := NewServer(spec, createTestConfig(tmpFile.Name())).createHandler()
+
+		assert.Equal(t, http.StatusOK, get(handler, "/users").Code)
+	})
+}
+
+func TestBasePath_ServerVariables(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	tests := []struct {
+		name      string
+		server    *openapi3.Server
+		wantPath  string
+		wantRoute string
+	}{
+		{
+			name: "defaults",
+			server: &openapi3.Server{URL: "https://{host}/{basePath}", Variables: map[string]*openapi3.ServerVariable{
+				"host":     {Default: "api.example.com"},
+				"basePath": {Default: "api/v2"},
+			}},
+			wantPath:  "/api/v2",
+			wantRoute: "/api/v2/users",
+		},
+		{
+			name: "enum without default",
+			server: &openapi3.Server{URL: "{scheme}://example.com/{stage}/v1", Variables: map[string]*openapi3.ServerVariable{
+				"scheme": {Default: "https"},
+				"stage":  {Enum: []string{"prod", "staging"}},
+			}},
+			wantPath:  "/prod/v1",
+			wantRoute: "/prod/v1/users",
+		},
+		{
+			name:      "relative url",
+			server:    &openapi3.Server{URL: "/{version}", Variables: map[string]*openapi3.ServerVariable{"version": {Default: "v3"}}},
+			wantPath:  "/v3",
+			wantRoute: "/v3/users",
+		},
+		{
+			name:      "undeclared variable",
+			server:    &openapi3.Server{URL: "https://example.com/{tenant}/api"},
+			wantPath:  "",
+			wantRoute: "/users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := createTestSpec()
+			spec.Servers = openapi3.Servers{tt.server}
+			server := NewServer(spec, createTestConfig(tmpFile.Name()))
+			assert.Equal(t, tt.wantPath, server.basePath)
+
+			w := httptest.NewRecorder()
+			server.createHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.wantRoute, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
 func TestAdminEndpoints(t *testing.T) {
 	// Create temp db file
 	tmpFile, err := os.CreateTemp("", "test-*.db")
@@ -357,6 +591,324 @@ func TestAdminEndpoints(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, response, "version")
 	})
+
+	countUsers := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var users []interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &users))
+		return len(users)
+	}
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("POST /_meridian/state", func(t *testing.T) {
+		w := do(http.MethodPost, "/_meridian/state", `{"version": "1.0", "resources": {"users": [{"id": "1", "name": "Alice"}]}}`)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 1, countUsers())
+
+		w = do(http.MethodPost, "/_meridian/state?merge=true", `{"version": "1.0", "resources": {"users": [{"id": "2", "name": "Bob"}]}}`)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 2, countUsers())
+
+		w = do(http.MethodPost, "/_meridian/state", `{"version": "1.0", "resources": {"users": ["Alice"]}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 2, countUsers())
+	})
+
+	t.Run("snapshot and restore", func(t *testing.T) {
+		w := do(http.MethodPost, "/_meridian/state/snapshot?name=two-users", "")
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = do(http.MethodDelete, "/_meridian/state", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 0, countUsers())
+
+		w = do(http.MethodPost, "/_meridian/state/restore?name=two-users", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 2, countUsers())
+
+		w = do(http.MethodGet, "/_meridian/state/snapshot", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"snapshots": ["two-users"]}`, w.Body.String())
+
+		w = do(http.MethodPost, "/_meridian/state/restore?name=missing", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("GET /_meridian/resources", func(t *testing.T) {
+		w := do(http.MethodGet, "/_meridian/resources", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"resources": {"users": 2}}`, w.Body.String())
+
+		w = do(http.MethodPost, "/_meridian/resources", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("POST /_meridian/seed", func(t *testing.T) {
+		w := do(http.MethodPost, "/_meridian/seed", `{"items_per_resource": 3}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"counts": {"users": 3}}`, w.Body.String())
+		assert.Equal(t, 3, countUsers())
+
+		w = do(http.MethodPost, "/_meridian/seed?merge=true", `{"counts_by_resource": {"users": 4}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"counts": {"users": 4}}`, w.Body.String())
+		assert.Equal(t, 4, countUsers(), "merging replaces users with the same generated IDs")
+
+		w = do(http.MethodPost, "/_meridian/seed", `{"count_ranges": {"users": {"min": 2, "max": 2}}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, countUsers())
+
+		w = do(http.MethodPost, "/_meridian/seed", `{"fk_distribution": "zipf"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		cfg.State.AutoSeed.CountsByResource = map[string]int{"users": 6}
+		defer func() { cfg.State.AutoSeed.CountsByResource = nil }()
+		w = do(http.MethodPost, "/_meridian/seed", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 6, countUsers(), "an empty body seeds with the configured counts")
+
+		w = do(http.MethodPost, "/_meridian/seed", `{"fk_distribution": "random"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 6, countUsers(), "request fields override only their own setting")
+
+		w = do(http.MethodGet, "/_meridian/seed", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestResetToSeed(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, []byte(`{"users": [{"id": "1", "name": "Alice"}]}`), 0644))
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.State.Seed = seedPath
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/users", `{"name": "Bob"}`).Code)
+
+	// The seed file changing after startup doesn't affect reset
+	require.NoError(t, os.WriteFile(seedPath, []byte(`{"users": []}`), 0644))
+
+	for i := 0; i < 2; i++ {
+		w := do(http.MethodPost, "/_meridian/reset", "")
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodGet, "/users", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id": "1", "name": "Alice"}]`, w.Body.String())
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodGet, "/_meridian/reset", "").Code)
+
+	t.Run("without a seed file", func(t *testing.T) {
+		server := NewServer(createTestSpec(), createTestConfig(tmpFile.Name()))
+		handler := server.createHandler()
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/_meridian/reset", nil))
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		users, err := server.stateManager.GetResources("users")
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+}
+
+func TestPathParamValidation(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	spec := createTestSpec()
+	userPath := spec.Paths.Value("/users/{id}")
+	userPath.Parameters = openapi3.Parameters{
+		{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewIntegerSchema())},
+	}
+
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	t.Run("non-integer id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response struct {
+			Code    string `json:"code"`
+			Details []struct {
+				Field   string `json:"field"`
+				Message string `json:"message"`
+			} `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "invalid_parameter", response.Code)
+		require.Len(t, response.Details, 1)
+		assert.Equal(t, "path.id", response.Details[0].Field)
+		assert.Equal(t, "must be an integer", response.Details[0].Message)
+	})
+
+	t.Run("integer id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("X-Meridian-Validation-Warnings"))
+	})
+}
+
+func TestPathParamValidation_Status(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	spec := createTestSpec()
+	uuidSchema := openapi3.NewStringSchema()
+	uuidSchema.Format = "uuid"
+	spec.Paths.Value("/users/{id}").Parameters = openapi3.Parameters{
+		{Value: openapi3.NewPathParameter("id").WithSchema(uuidSchema)},
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "default", status: 0, wantStatus: http.StatusBadRequest, wantCode: "invalid_parameter"},
+		{name: "bad request", status: http.StatusBadRequest, wantStatus: http.StatusBadRequest, wantCode: "invalid_parameter"},
+		{name: "not found", status: http.StatusNotFound, wantStatus: http.StatusNotFound, wantCode: "not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(tmpFile.Name())
+			cfg.Behavior.InvalidPathParamStatus = tt.status
+			handler := NewServer(spec, cfg).createHandler()
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/not-a-uuid", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response struct {
+				Code    string `json:"code"`
+				Details []struct {
+					Field string `json:"field"`
+					Code  string `json:"code"`
+				} `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCode, response.Code)
+			require.Len(t, response.Details, 1)
+			assert.Equal(t, "path.id", response.Details[0].Field)
+			assert.Equal(t, "invalid_format", response.Details[0].Code)
+
+			// Well-formed IDs are routed as usual
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/7c9e6679-7425-40de-944b-e07fc1f90ae7", nil))
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), "Resource not found")
+		})
+	}
+}
+
+func TestPathParamValidation_Warnings(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	spec := createTestSpec()
+	userPath := spec.Paths.Value("/users/{id}")
+	userPath.Parameters = openapi3.Parameters{
+		{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewStringSchema().WithFormat("slug"))},
+	}
+
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/ada-lovelace", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("X-Meridian-Validation-Warnings"), `unknown format "slug"`)
+}
+
+func TestResourceAccessLists(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	spec := createTestSpec()
+	spec.Paths.Set("/orders", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "listOrders",
+			Responses:   spec.Paths.Value("/users").Get.Responses,
+		},
+	})
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.DeniedResources = []string{"orders"}
+
+	server := NewServer(spec, cfg)
+	handler := server.createHandler()
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/orders")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "forbidden", response["code"])
+	assert.Equal(t, "orders", response["resource"])
+
+	assert.Equal(t, http.StatusOK, do("/users").Code)
+
+	// Admin endpoints aren't subject to the lists
+	assert.Equal(t, http.StatusOK, do("/_meridian/status").Code)
+
+	// With an allowlist, everything not on it is forbidden
+	cfg.Behavior.DeniedResources = nil
+	cfg.Behavior.AllowedResources = []string{"orders"}
+	assert.Equal(t, http.StatusOK, do("/orders").Code)
+	assert.Equal(t, http.StatusForbidden, do("/users").Code)
 }
 
 func TestCORSMiddleware_Integration(t *testing.T) {
@@ -394,3 +946,123 @@ func TestCORSMiddleware_Integration(t *testing.T) {
 		assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Origin"))
 	})
 }
+
+func TestClock(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	handler := NewServer(createTestSpec(), createTestConfig(tmpFile.Name())).createHandler()
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("freeze", func(t *testing.T) {
+		w := do(http.MethodPost, "/_meridian/clock", `{"time": "2030-06-01T12:00:00Z"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var status map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+		assert.Equal(t, "2030-06-01T12:00:00Z", status["now"])
+		assert.Equal(t, true, status["frozen"])
+
+		frozen := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC).UnixNano()
+		for i := int64(0); i < 2; i++ {
+			w = do(http.MethodPost, "/users", `{"name": "Alice"}`)
+			require.Equal(t, http.StatusCreated, w.Code)
+			var user map[string]interface{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&user))
+			// IDs stay unique while the clock stands still
+			assert.Equal(t, strconv.FormatInt(frozen+i, 10), user["id"])
+		}
+
+		w = do(http.MethodGet, "/_meridian/state", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var export map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&export))
+		assert.Equal(t, "2030-06-01T12:00:00Z", export["timestamps"].(map[string]interface{})["created_at"])
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		w := do(http.MethodPost, "/_meridian/clock", `{"offset": "-24h"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var status map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+		assert.Equal(t, false, status["frozen"])
+		assert.Equal(t, "-24h0m0s", status["offset"])
+
+		now, err := time.Parse(time.RFC3339Nano, status["now"].(string))
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(-24*time.Hour), now, time.Minute)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/_meridian/clock", `{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/_meridian/clock", `{"time": "tomorrow"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/_meridian/clock", `{"offset": "1 day"}`).Code)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/_meridian/clock", "").Code)
+		w := do(http.MethodGet, "/_meridian/clock", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var status map[string]interface{}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+		assert.Equal(t, false, status["frozen"])
+		assert.Equal(t, "0s", status["offset"])
+	})
+}
+
+func TestSuccessStatusFromSpec(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	responses := func(code string) *openapi3.Responses {
+		responses := openapi3.NewResponses()
+		responses.Set(code, &openapi3.ResponseRef{Value: &openapi3.Response{}})
+		return responses
+	}
+
+	spec := createTestSpec()
+	spec.Paths.Set("/jobs", &openapi3.PathItem{
+		Post: &openapi3.Operation{OperationID: "createJob", Responses: responses("202")},
+	})
+	spec.Paths.Set("/jobs/{id}", &openapi3.PathItem{
+		Delete: &openapi3.Operation{OperationID: "deleteJob", Responses: responses("200")},
+		Put:    &openapi3.Operation{OperationID: "updateJob", Responses: responses("204")},
+	})
+	handler := NewServer(spec, createTestConfig(tmpFile.Name())).createHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewBufferString(`{"id": "1", "kind": "export"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"export"`)
+
+	req = httptest.NewRequest(http.MethodPut, "/jobs/1", bytes.NewBufferString(`{"kind": "import"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// A delete that documents 200 returns the removed resource
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/jobs/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"import"`)
+
+	// Operations without a 2xx response keep the default mapping
+	req = httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(`{"id": "1", "name": "Alice"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
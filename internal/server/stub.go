@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// resolveResourceID finds the ID of the single resource a path addresses, or ""
+// for collection paths
+func resolveResourceID(pathParams map[string]string, nestedInfo *NestedResourceInfo) string {
+	if nestedInfo != nil && nestedInfo.IsNested && nestedInfo.ChildID != "" {
+		return nestedInfo.ChildID
+	}
+
+	if id := pathParams["id"]; id != "" {
+		return id
+	}
+
+	for key, value := range pathParams {
+		if strings.HasSuffix(strings.ToLower(key), "id") && (nestedInfo == nil || key != nestedInfo.ParentIDParam) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// echoPathIDs returns a copy of a generated object with the id fields taken
+// from the request path, so a stub for /users/42 has id 42. The id is converted
+// to the type the schema declares for it, and fields named after a path
+// parameter (e.g. userId) get that parameter's value. The generated data is
+// copied first, since it may be an example shared with the spec.
+func echoPathIDs(data interface{}, schema *openapi3.SchemaRef, resourceID string, pathParams map[string]string) interface{} {
+	obj, ok := copyValue(data).(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	if resourceID != "" {
+		obj["id"] = pathIDValue(schema, "id", resourceID)
+	}
+
+	for name, value := range pathParams {
+		if name == "id" || !strings.HasSuffix(strings.ToLower(name), "id") {
+			continue
+		}
+		if _, exists := obj[name]; exists {
+			obj[name] = pathIDValue(schema, name, value)
+		}
+	}
+	return obj
+}
+
+// pathIDValue converts a path value to the type of the named property of
+// schema, so an integer id stays an integer
+func pathIDValue(schema *openapi3.SchemaRef, name, value string) interface{} {
+	if schema == nil || schema.Value == nil {
+		return value
+	}
+	prop := schema.Value.Properties[name]
+	if prop == nil || prop.Value == nil {
+		return value
+	}
+	return coerceToSchema(value, prop.Value)
+}
+
+// generateResource generates a single resource from an operation's success
+// response schema, with its ids taken from the request path
+func generateResource(op *openapi3.Operation, resourceID string, pathParams map[string]string) (interface{}, bool) {
+	if op == nil {
+		return nil, false
+	}
+
+	_, _, schema := successResponse(op, "")
+	if schema == nil {
+		return nil, false
+	}
+
+	data, err := generator.GenerateData(schema)
+	if err != nil {
+		return nil, false
+	}
+
+	return echoPathIDs(data, schema, resourceID, pathParams), true
+}
+
+// persistGenerated stores a resource generated on a miss, so later requests for
+// the same ID return it instead of generating new data. Only objects whose id
+// matches the requested ID can be stored.
+func (s *Server) persistGenerated(resourceName, resourceID string, data interface{}) {
+	obj, ok := data.(map[string]interface{})
+	if !ok || fmt.Sprintf("%v", obj["id"]) != resourceID {
+		return
+	}
+
+	if err := s.stateManager.AddResource(resourceName, obj); err != nil {
+		log.Printf("Failed to persist generated %s %s: %v", resourceName, resourceID, err)
+	}
+}
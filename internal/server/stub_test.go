@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createStubTestSpec() *openapi3.T {
+	stringSchema := func() *openapi3.SchemaRef {
+		s := &openapi3.Schema{}
+		s.Type = "string"
+		return &openapi3.SchemaRef{Value: s}
+	}
+
+	postSchema := &openapi3.Schema{}
+	postSchema.Type = "object"
+	postSchema.Properties = map[string]*openapi3.SchemaRef{
+		"id":     stringSchema(),
+		"userId": stringSchema(),
+		"title":  stringSchema(),
+	}
+
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{
+		Value: &openapi3.Response{
+			Content: openapi3.Content{
+				"application/json": &openapi3.MediaType{
+					Schema: &openapi3.SchemaRef{Value: postSchema},
+				},
+			},
+		},
+	})
+
+	paths := openapi3.NewPaths()
+	paths.Set("/users/{userId}/posts/{id}", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			OperationID: "getUserPost",
+			Responses:   responses,
+		},
+	})
+
+	return &openapi3.T{Paths: paths}
+}
+
+func TestGenerateOnMiss_EchoesPathIDs(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.GenerateOnMiss = true
+
+	server := NewServer(createStubTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/u-7/posts/p-42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "p-42", response["id"])
+	assert.Equal(t, "u-7", response["userId"])
+	assert.NotEmpty(t, response["title"])
+}
+
+func TestGenerateOnMiss_Disabled(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	server := NewServer(createStubTestSpec(), createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/u-7/posts/p-42", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestEchoPathIDs(t *testing.T) {
+	data := map[string]interface{}{
+		"userId": "random",
+		"name":   "Ada",
+	}
+
+	echoed := echoPathIDs(data, nil, "42", map[string]string{"userId": "7", "id": "42", "slug": "x"}).(map[string]interface{})
+
+	assert.Equal(t, "42", echoed["id"], "id is set even when the generated object has none")
+	assert.Equal(t, "7", echoed["userId"])
+	assert.Equal(t, "Ada", echoed["name"])
+	assert.NotContains(t, echoed, "slug")
+
+	// The generated data is left unchanged
+	assert.Equal(t, "random", data["userId"])
+	assert.NotContains(t, data, "id")
+}
+
+func TestGenerateOnMiss_ExampleUnchanged(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.GenerateOnMiss = true
+
+	spec := createStubTestSpec()
+	op := spec.Paths.Find("/users/{userId}/posts/{id}").Get
+	schema := op.Responses.Status(200).Value.Content["application/json"].Schema.Value
+	schema.Properties["id"].Value.Type = "integer"
+	example := map[string]interface{}{"id": 1, "userId": "u-1", "title": "Example"}
+	schema.Example = example
+
+	handler := NewServer(spec, cfg).createHandler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/u-7/posts/%d", id), nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, float64(id), response["id"], "the id is an integer, as the schema declares")
+			assert.Equal(t, "u-7", response["userId"])
+		}(i + 100)
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]interface{}{"id": 1, "userId": "u-1", "title": "Example"}, example)
+}
+
+func TestGenerateOnMiss_PersistGenerated(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.GenerateOnMiss = true
+	cfg.Behavior.PersistGenerated = true
+
+	server := NewServer(createStubTestSpec(), cfg)
+	handler := server.createHandler()
+
+	get := func() []byte {
+		req := httptest.NewRequest(http.MethodGet, "/users/u-7/posts/p-42", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.Bytes()
+	}
+
+	first := get()
+	second := get()
+	assert.JSONEq(t, string(first), string(second))
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// templateExtension is the spec extension that holds an operation's response template
+const templateExtension = "x-meridian-template"
+
+// TemplateContext is the data available to response templates
+type TemplateContext struct {
+	// Method is the HTTP method of the request
+	Method string
+
+	// Path is the request path
+	Path string
+
+	// PathParams holds the path parameters extracted from the request path
+	PathParams map[string]string
+
+	// Query holds the first value of each query parameter
+	Query map[string]string
+
+	// Headers holds the first value of each request header
+	Headers map[string]string
+
+	// Body is the decoded JSON request body, if any
+	Body interface{}
+
+	// Generated is an object generated from the operation's success response schema
+	Generated interface{}
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// responseTemplate returns the response template configured for an operation.
+// Templates in the config take precedence over the spec extension.
+func (s *Server) responseTemplate(op *openapi3.Operation) string {
+	if op.OperationID != "" {
+		if tmpl, ok := s.cfg.Behavior.Templates[op.OperationID]; ok {
+			return tmpl
+		}
+	}
+
+	if raw, ok := op.Extensions[templateExtension]; ok {
+		switch v := raw.(type) {
+		case string:
+			return v
+		case json.RawMessage:
+			var tmpl string
+			if err := json.Unmarshal(v, &tmpl); err == nil {
+				return tmpl
+			}
+		}
+	}
+
+	return ""
+}
+
+// handleTemplate renders an operation's response template with the request context
+func (s *Server) handleTemplate(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, tmplText string, pathParams map[string]string) {
+	tmpl, err := template.New(op.OperationID).Funcs(templateFuncs).Parse(tmplText)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, "template_error", fmt.Sprintf("failed to parse response template: %v", err))
+		return
+	}
+
+	statusCode, mediaType, schema := successResponse(op, r.Method)
+
+	ctx := TemplateContext{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		PathParams: pathParams,
+		Query:      make(map[string]string),
+		Headers:    make(map[string]string),
+	}
+	if ctx.PathParams == nil {
+		ctx.PathParams = make(map[string]string)
+	}
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			ctx.Query[key] = values[0]
+		}
+	}
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			ctx.Headers[key] = values[0]
+		}
+	}
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err == nil && len(bytes.TrimSpace(body)) > 0 {
+			var decoded interface{}
+			if err := json.Unmarshal(body, &decoded); err == nil {
+				ctx.Body = decoded
+			}
+		}
+	}
+
+	if schema != nil {
+		if generated, err := generator.GenerateData(schema); err == nil {
+			specPath, _ := s.stripBasePath(r.URL.Path)
+			_, nestedInfo := ExtractResourceInfo(specPath, pathParams)
+			ctx.Generated = echoPathIDs(generated, schema, resolveResourceID(ctx.PathParams, nestedInfo), ctx.PathParams)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, "template_error", fmt.Sprintf("failed to render response template: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(statusCode)
+	w.Write(buf.Bytes())
+}
+
+// successResponse finds the first 2xx response of an operation and returns its
+// status code, media type and schema
+func successResponse(op *openapi3.Operation, method string) (int, string, *openapi3.SchemaRef) {
+	statusCode := http.StatusOK
+	if method == http.MethodPost {
+		statusCode = http.StatusCreated
+	}
+	mediaType := "application/json"
+
+	code, resp := firstSuccessResponse(op)
+	if resp == nil {
+		return statusCode, mediaType, nil
+	}
+
+	if mt := resp.Content.Get("application/json"); mt != nil {
+		return code, mediaType, mt.Schema
+	}
+	for ct, mt := range resp.Content {
+		return code, ct, mt.Schema
+	}
+	return code, mediaType, nil
+}
+
+// firstSuccessResponse returns the lowest 2xx response defined for an operation
+func firstSuccessResponse(op *openapi3.Operation) (int, *openapi3.Response) {
+	if op.Responses == nil {
+		return 0, nil
+	}
+
+	for code := 200; code < 300; code++ {
+		resp := op.Responses.Value(strconv.Itoa(code))
+		if resp != nil && resp.Value != nil {
+			return code, resp.Value
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseTemplate_EchoesPathParam(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Templates = map[string]string{
+		"getUser": `{"id": "{{.PathParams.id}}", "source": "template"}`,
+	}
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/abc-123", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "abc-123", response["id"])
+	assert.Equal(t, "template", response["source"])
+}
+
+func TestResponseTemplate_EchoesBodyAndQuery(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Templates = map[string]string{
+		"createUser": `{"name": {{json .Body.name}}, "tag": "{{.Query.tag}}"}`,
+	}
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/users?tag=vip", bytes.NewReader([]byte(`{"name":"Ada"}`)))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Ada", response["name"])
+	assert.Equal(t, "vip", response["tag"])
+}
+
+func TestResponseTemplate_FromSpecExtension(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	spec := createTestSpec()
+	spec.Paths.Value("/users/{id}").Get.Extensions = map[string]interface{}{
+		templateExtension: `{"echo": "{{upper .PathParams.id}}"}`,
+	}
+
+	server := NewServer(spec, createTestConfig(tmpFile.Name()))
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/xyz", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"echo": "XYZ"}`, w.Body.String())
+}
+
+func TestResponseTemplate_RenderError(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Templates = map[string]string{
+		"getUser": `{{.Missing.field}`,
+	}
+
+	server := NewServer(createTestSpec(), cfg)
+	handler := server.createHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "template_error", response["code"])
+	assert.Contains(t, response["error"], "response template")
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// findUniqueConflict checks the configured unique fields of a resource against
+// the stored resources, returning the first field whose value is already taken
+// by a different resource
+func (s *Server) findUniqueConflict(resourceName, resourceID string, data map[string]interface{}) (string, error) {
+	for _, field := range s.cfg.State.UniqueFields[resourceName] {
+		value, ok := data[field]
+		if !ok {
+			continue
+		}
+
+		// Only scalar values can be compared in the JSON column
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			continue
+		}
+
+		existingID, err := s.stateManager.FindResourceIDByField(resourceName, field, value)
+		if err != nil {
+			return "", err
+		}
+		if existingID != "" && existingID != resourceID {
+			return field, nil
+		}
+	}
+
+	return "", nil
+}
+
+// checkUnique writes a 409 response and returns false if the resource
+// violates a unique field constraint
+func (s *Server) checkUnique(w http.ResponseWriter, r *http.Request, resourceName, resourceID string, data map[string]interface{}) bool {
+	field, err := s.findUniqueConflict(resourceName, resourceID, data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check unique fields: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	if field != "" {
+		message := fmt.Sprintf("A %s resource with this %s already exists", resourceName, field)
+		s.writeErrorWithFields(w, r, http.StatusConflict, "conflict", message, map[string]interface{}{
+			"field": field,
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueFields(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.State.UniqueFields = map[string][]string{
+		"users": {"email"},
+	}
+
+	spec := createTestSpec()
+	userPath := spec.Paths.Value("/users/{id}")
+	userPath.Patch = &openapi3.Operation{
+		OperationID: "patchUser",
+		Responses:   userPath.Put.Responses,
+	}
+
+	server := NewServer(spec, cfg)
+	handler := server.createHandler()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/users", `{"id": "1", "name": "Ada", "email": "ada@example.com"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = do(http.MethodPost, "/users", `{"id": "2", "name": "Ada Again", "email": "ada@example.com"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"conflict"`)
+	assert.Contains(t, w.Body.String(), `"field":"email"`)
+
+	w = do(http.MethodPost, "/users", `{"id": "2", "name": "Grace", "email": "grace@example.com"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// Updating a resource may keep its own value
+	w = do(http.MethodPut, "/users/1", `{"name": "Ada Lovelace", "email": "ada@example.com"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do(http.MethodPut, "/users/2", `{"name": "Grace", "email": "ada@example.com"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = do(http.MethodPatch, "/users/2", `{"email": "ada@example.com"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// webhookTimeout bounds each delivery attempt so a dead endpoint can't pile up requests
+	webhookTimeout = 5 * time.Second
+
+	// webhookRetryDelay is the pause before the single retry of a failed delivery
+	webhookRetryDelay = 500 * time.Millisecond
+
+	// webhookWorkers is how many deliveries are in flight at once
+	webhookWorkers = 4
+)
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// webhookPayload is the JSON body posted to webhook subscribers
+type webhookPayload struct {
+	Event     string      `json:"event"`
+	Resource  string      `json:"resource"`
+	ID        string      `json:"id"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// webhookDelivery is a payload waiting to be posted to a subscriber
+type webhookDelivery struct {
+	url  string
+	body []byte
+}
+
+// fireWebhooks queues the event for every subscribed URL. The deliveries are
+// posted in the background by the webhook workers.
+func (s *Server) fireWebhooks(event, resourceName, resourceID string, data interface{}) {
+	if len(s.cfg.Behavior.Webhooks) == 0 {
+		return
+	}
+
+	// Encode now so later changes to data can't race with delivery
+	body, err := json.Marshal(webhookPayload{
+		Event:     event,
+		Resource:  resourceName,
+		ID:        resourceID,
+		Data:      data,
+		Timestamp: s.clock.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("Failed to encode webhook payload for %s %s/%s: %v", event, resourceName, resourceID, err)
+		return
+	}
+
+	for _, webhook := range s.cfg.Behavior.Webhooks {
+		if webhook.Event != "*" && webhook.Event != event {
+			continue
+		}
+		s.webhooks.push(webhookDelivery{url: webhook.URL, body: body})
+	}
+}
+
+// runWebhookWorker delivers queued webhooks until the queue is closed
+func (s *Server) runWebhookWorker() {
+	for {
+		select {
+		case delivery := <-s.webhooks.items:
+			deliverWebhook(delivery.url, delivery.body)
+		case <-s.webhooks.done:
+			return
+		}
+	}
+}
+
+// deliverWebhook posts body to url, retrying once on failure
+func deliverWebhook(url string, body []byte) {
+	err := postWebhook(url, body)
+	if err == nil {
+		return
+	}
+
+	time.Sleep(webhookRetryDelay)
+	if err := postWebhook(url, body); err != nil {
+		log.Printf("Webhook delivery to %s failed: %v", url, err)
+	}
+}
+
+func postWebhook(url string, body []byte) error {
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
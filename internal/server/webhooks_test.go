@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/felipevolpatto/meridian/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks_FireOnMutations(t *testing.T) {
+	events := make(chan webhookPayload, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil {
+			events <- payload
+		}
+	}))
+	defer receiver.Close()
+
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	cfg := createTestConfig(tmpFile.Name())
+	cfg.Behavior.Webhooks = []config.WebhookConfig{
+		{Event: "created", URL: receiver.URL},
+		{Event: "deleted", URL: receiver.URL},
+	}
+	handler := NewServer(createTestSpec(), cfg).createHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(`{"id": "1", "name": "Alice"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	created := receiveWebhook(t, events)
+	assert.Equal(t, "created", created.Event)
+	assert.Equal(t, "users", created.Resource)
+	assert.Equal(t, "1", created.ID)
+	assert.Equal(t, "Alice", created.Data.(map[string]interface{})["name"])
+
+	// Updates have no subscriber
+	req = httptest.NewRequest(http.MethodPut, "/users/1", bytes.NewBufferString(`{"name": "Bob"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	deleted := receiveWebhook(t, events)
+	assert.Equal(t, "deleted", deleted.Event)
+	assert.Equal(t, "1", deleted.ID)
+	assert.Equal(t, "Bob", deleted.Data.(map[string]interface{})["name"])
+}
+
+func TestDeliverWebhook_RetriesOnce(t *testing.T) {
+	var attempts int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	deliverWebhook(receiver.URL, []byte(`{}`))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func receiveWebhook(t *testing.T, events <-chan webhookPayload) webhookPayload {
+	t.Helper()
+	select {
+	case payload := <-events:
+		return payload
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook")
+		return webhookPayload{}
+	}
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// isXMLMediaType checks if a media type is application/xml, text/xml or an
+// +xml structured syntax such as application/atom+xml
+func isXMLMediaType(contentType string) bool {
+	ct := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return ct == "application/xml" || ct == "text/xml" || strings.HasSuffix(ct, "+xml")
+}
+
+// xmlMediaType returns the first XML media type declared in content, in
+// sorted order so the choice is stable
+func xmlMediaType(content openapi3.Content) (string, *openapi3.MediaType, bool) {
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		if isXMLMediaType(ct) {
+			types = append(types, ct)
+		}
+	}
+	if len(types) == 0 {
+		return "", nil, false
+	}
+	sort.Strings(types)
+	return types[0], content[types[0]], true
+}
+
+// xmlResponse reports whether a CRUD response with the given status is
+// written as XML, returning the media type and its schema. JSON stays the
+// default: XML is used when the response declares an XML media type and
+// either no JSON, or the client's Accept header prefers XML to JSON, in which
+// case Accept is added to Vary.
+func xmlResponse(w http.ResponseWriter, r *http.Request, op *openapi3.Operation, status int) (string, *openapi3.Schema, bool) {
+	if op == nil || op.Responses == nil {
+		return "", nil, false
+	}
+	resp := op.Responses.Status(status)
+	if resp == nil || resp.Value == nil {
+		return "", nil, false
+	}
+
+	ct, mt, ok := xmlMediaType(resp.Value.Content)
+	if !ok {
+		return "", nil, false
+	}
+
+	if resp.Value.Content.Get("application/json") != nil {
+		addVary(w.Header(), "Accept")
+		if negotiateMediaType(r.Header.Get("Accept"), "application/json", ct) != ct {
+			return "", nil, false
+		}
+	}
+
+	var schema *openapi3.Schema
+	if mt != nil && mt.Schema != nil {
+		schema = mt.Schema.Value
+	}
+	return ct, schema, true
+}
+
+// xmlRequest reports whether a request body is XML that the operation
+// accepts, returning the schema of the body if there is one
+func xmlRequest(r *http.Request, op *openapi3.Operation) (*openapi3.Schema, bool) {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !isXMLMediaType(ct) {
+		return nil, false
+	}
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil, false
+	}
+
+	content := op.RequestBody.Value.Content
+	mt := content.Get(ct)
+	if mt == nil {
+		var ok bool
+		if _, mt, ok = xmlMediaType(content); !ok {
+			return nil, false
+		}
+	}
+
+	if mt == nil || mt.Schema == nil {
+		return nil, true
+	}
+	return mt.Schema.Value, true
+}
+
+// writeXML encodes data as an XML response. Objects are written as the
+// element rootName, lists as rootName wrapping one itemName element per item.
+func writeXML(w http.ResponseWriter, status int, mediaType string, schema *openapi3.Schema, resourceName string, data interface{}) {
+	root, item := xmlNames(schema, resourceName, data)
+
+	var body strings.Builder
+	body.WriteString(xml.Header)
+	enc := xml.NewEncoder(&body)
+
+	var err error
+	if items, ok := data.([]interface{}); ok {
+		var itemSchema *openapi3.Schema
+		if schema != nil && schema.Items != nil {
+			itemSchema = schema.Items.Value
+		}
+		err = encodeXMLList(enc, root, item, items, itemSchema)
+	} else {
+		err = encodeXMLValue(enc, root, data, schema)
+	}
+	if err == nil {
+		err = enc.Flush()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode XML: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	io.WriteString(w, body.String())
+}
+
+// xmlNames returns the root element name of a response body and the element
+// name of list items, from the schema's xml.name when set and from the
+// resource name otherwise, such as <users><user/></users>
+func xmlNames(schema *openapi3.Schema, resourceName string, data interface{}) (string, string) {
+	singular := generator.Singularize(resourceName)
+	if singular == "" {
+		singular = "item"
+	}
+
+	if _, ok := data.([]interface{}); !ok {
+		return xmlName(schema, singular), ""
+	}
+
+	list := resourceName
+	if list == "" || list == singular {
+		list = "items"
+	}
+	var itemSchema *openapi3.Schema
+	if schema != nil && schema.Items != nil {
+		itemSchema = schema.Items.Value
+	}
+	return xmlName(schema, list), xmlName(itemSchema, singular)
+}
+
+// xmlName returns the schema's xml.name, or fallback
+func xmlName(schema *openapi3.Schema, fallback string) string {
+	if schema != nil && schema.XML != nil && schema.XML.Name != "" {
+		return schema.XML.Name
+	}
+	return fallback
+}
+
+// encodeXMLList writes items as itemName elements inside a name element
+func encodeXMLList(enc *xml.Encoder, name, itemName string, items []interface{}, itemSchema *openapi3.Schema) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := encodeXMLValue(enc, itemName, item, itemSchema); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// encodeXMLValue writes value as a name element. Object properties become
+// child elements named after them, in sorted order. Array properties repeat
+// the element, or are wrapped in it when the schema sets xml.wrapped. Null
+// values are left out.
+func encodeXMLValue(enc *xml.Encoder, name string, value interface{}, schema *openapi3.Schema) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return enc.EncodeElement(xmlText(value), start)
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if obj[key] == nil {
+			continue
+		}
+
+		prop := propertySchema(schema, key)
+		element := xmlName(prop, key)
+
+		items, isList := obj[key].([]interface{})
+		if !isList {
+			if err := encodeXMLValue(enc, element, obj[key], prop); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var itemSchema *openapi3.Schema
+		if prop != nil && prop.Items != nil {
+			itemSchema = prop.Items.Value
+		}
+		if prop != nil && prop.XML != nil && prop.XML.Wrapped {
+			if err := encodeXMLList(enc, element, xmlName(itemSchema, key), items, itemSchema); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, item := range items {
+			if err := encodeXMLValue(enc, element, item, itemSchema); err != nil {
+				return err
+			}
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlText formats a scalar as element text
+func xmlText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// propertySchema returns the schema of an object property, or nil
+func propertySchema(schema *openapi3.Schema, name string) *openapi3.Schema {
+	if schema == nil {
+		return nil
+	}
+	if prop := schema.Properties[name]; prop != nil {
+		return prop.Value
+	}
+	return nil
+}
+
+// decodeXML decodes an XML document into an object with a property per child
+// element of the root. Attributes are read as properties too, and repeated
+// elements as arrays. With a schema, properties are mapped back from their
+// xml.name, arrays of one element are recognized and values are converted to
+// the declared integer, number and boolean types.
+func decodeXML(r io.Reader, schema *openapi3.Schema) (map[string]interface{}, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		value, err := decodeXMLElement(dec, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XML: %w", err)
+		}
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			obj = map[string]interface{}{}
+		}
+		if schema != nil {
+			fromXML(obj, schema)
+		}
+		return obj, nil
+	}
+}
+
+// decodeXMLElement reads the content of an element up to its end, returning
+// its text when it has no attributes or child elements and an object
+// otherwise
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	obj := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		obj[attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := obj[name].(type) {
+			case nil:
+				obj[name] = child
+			case []interface{}:
+				obj[name] = append(existing, child)
+			default:
+				obj[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(obj) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+			return obj, nil
+		}
+	}
+}
+
+// fromXML shapes decoded XML after a schema: renamed elements get their
+// property names back, single or wrapped elements of array properties become
+// arrays and scalars are converted to their declared types
+func fromXML(value interface{}, schema *openapi3.Schema) interface{} {
+	switch {
+	case schema.Type == "object" || len(schema.Properties) > 0:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			if text, isText := value.(string); isText && text == "" {
+				return map[string]interface{}{}
+			}
+			return value
+		}
+		for name, ref := range schema.Properties {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			element := xmlName(ref.Value, name)
+			v, exists := obj[element]
+			if !exists {
+				continue
+			}
+			if element != name {
+				delete(obj, element)
+			}
+			obj[name] = fromXML(v, ref.Value)
+		}
+		return obj
+	case schema.Type == "array":
+		var itemSchema *openapi3.Schema
+		if schema.Items != nil {
+			itemSchema = schema.Items.Value
+		}
+		if schema.XML != nil && schema.XML.Wrapped {
+			wrapper, ok := value.(map[string]interface{})
+			if !ok {
+				return []interface{}{}
+			}
+			for _, v := range wrapper {
+				value = v
+			}
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		if itemSchema != nil {
+			for i, item := range items {
+				items[i] = fromXML(item, itemSchema)
+			}
+		}
+		return items
+	default:
+		return coerceToSchema(value, schema)
+	}
+}
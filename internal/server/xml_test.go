@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const xmlTestSpec = `
+openapi: 3.0.0
+info:
+  title: XML API
+  version: 1.0.0
+components:
+  schemas:
+    Book:
+      type: object
+      xml:
+        name: book
+      properties:
+        id:
+          type: string
+        title:
+          type: string
+        pages:
+          type: integer
+        available:
+          type: boolean
+        isbn:
+          type: string
+          xml:
+            name: ISBN
+        tags:
+          type: array
+          xml:
+            wrapped: true
+          items:
+            type: string
+            xml:
+              name: tag
+        authors:
+          type: array
+          items:
+            type: string
+paths:
+  /books:
+    get:
+      responses:
+        '200':
+          description: Books
+          content:
+            application/xml:
+              schema:
+                type: array
+                xml:
+                  name: library
+                items:
+                  $ref: '#/components/schemas/Book'
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Book'
+      responses:
+        '201':
+          description: Created
+          content:
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Book'
+  /books/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Book
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Book'
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Book'
+`
+
+func newXMLTestServer(t *testing.T) http.Handler {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(xmlTestSpec))
+	require.NoError(t, err)
+
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(tmpFile.Name()) })
+	tmpFile.Close()
+
+	return NewServer(spec, createTestConfig(tmpFile.Name())).createHandler()
+}
+
+func TestXML_RoundTrip(t *testing.T) {
+	handler := newXMLTestServer(t)
+
+	body := `<?xml version="1.0"?>
+<book>
+  <id>1</id>
+  <title>Go &amp; XML</title>
+  <pages>320</pages>
+  <available>true</available>
+  <ISBN>978-0</ISBN>
+  <tags><tag>go</tag></tags>
+  <authors>Alice</authors>
+  <authors>Bob</authors>
+</book>`
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<book><authors>Alice</authors><authors>Bob</authors><available>true</available><id>1</id>`+
+		`<ISBN>978-0</ISBN><pages>320</pages><tags><tag>go</tag></tags><title>Go &amp; XML</title></book>`, w.Body.String())
+
+	// The decoded body is stored with the types of the schema
+	req = httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var book map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &book))
+	assert.Equal(t, map[string]interface{}{
+		"id":        "1",
+		"title":     "Go & XML",
+		"pages":     float64(320),
+		"available": true,
+		"isbn":      "978-0",
+		"tags":      []interface{}{"go"},
+		"authors":   []interface{}{"Alice", "Bob"},
+	}, book)
+
+	req = httptest.NewRequest(http.MethodGet, "/books", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<library><book><authors>Alice</authors>`), w.Body.String())
+	assert.True(t, strings.HasSuffix(w.Body.String(), `</book></library>`), w.Body.String())
+}
+
+func TestXML_JSONStaysDefault(t *testing.T) {
+	handler := newXMLTestServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`<book><id>1</id><title>Dune</title></book>`))
+	req.Header.Set("Content-Type", "text/xml")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Operations declaring both use XML only when the client asks for it
+	for accept, want := range map[string]string{
+		"":                                  "application/json",
+		"application/json, application/xml": "application/json",
+		"application/xml":                   "application/xml",
+		"text/xml":                          "application/xml",
+		"application/xml;q=0.5, */*":        "application/json",
+	} {
+		req = httptest.NewRequest(http.MethodGet, "/books/1", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, want, w.Header().Get("Content-Type"), accept)
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	}
+
+	// JSON bodies are still decoded as JSON
+	req = httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`{"id": "2", "title": "Emma"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(`<book><id>3</title></book>`))
+	req.Header.Set("Content-Type", "application/xml")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
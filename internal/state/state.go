@@ -1,12 +1,20 @@
 package state
 
 import (
+	"bufio"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/felipevolpatto/meridian/internal/clock"
 	"github.com/felipevolpatto/meridian/internal/generator"
 	"github.com/getkin/kin-openapi/openapi3"
 	_ "github.com/mattn/go-sqlite3"
@@ -78,17 +86,7 @@ func runAutoSeed(spec *openapi3.T, config generator.AutoSeedConfig) error {
 		return nil
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
-	importData := &ExportData{
-		Version:   "1.0",
-		Resources: seedData,
-		Timestamps: Timestamps{
-			ExportedAt: now,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		},
-	}
-
+	importData := NewExportData(seedData, seeder.Relations())
 	if err := globalManager.Import(importData, false); err != nil {
 		return fmt.Errorf("failed to import auto-generated seed data: %w", err)
 	}
@@ -110,42 +108,113 @@ func GetManager() *Manager {
 }
 
 func loadSeedFile(seedPath string) error {
+	importData, err := ReadSeedFile(seedPath)
+	if err != nil || importData == nil {
+		return err
+	}
+
+	if err := globalManager.Import(importData, false); err != nil {
+		return fmt.Errorf("failed to import seed data: %w", err)
+	}
+
+	return nil
+}
+
+// ReadSeedFile reads and parses a seed file. It returns nil data when the
+// file doesn't exist.
+func ReadSeedFile(seedPath string) (*ExportData, error) {
 	if _, err := os.Stat(seedPath); os.IsNotExist(err) {
-		return nil
+		return nil, nil
 	}
 
 	data, err := os.ReadFile(seedPath)
 	if err != nil {
-		return fmt.Errorf("failed to read seed file: %w", err)
+		return nil, fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	importData, err := parseSeedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seed data: %w", err)
+	}
+	return importData, nil
+}
+
+// parseSeedData reads a seed file, which is either a map of resource names to
+// items or a full export as written by `meridian export` and `meridian seed`
+func parseSeedData(data []byte) (*ExportData, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	_, hasVersion := fields["version"]
+	_, hasResources := fields["resources"]
+	if hasVersion && hasResources {
+		var export ExportData
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, err
+		}
+		return &export, nil
 	}
 
 	var seedData map[string][]interface{}
 	if err := json.Unmarshal(data, &seedData); err != nil {
-		return fmt.Errorf("failed to parse seed data: %w", err)
+		return nil, err
 	}
+	return NewExportData(seedData, nil), nil
+}
 
-	now := time.Now().UTC().Format(time.RFC3339)
-	importData := &ExportData{
+// NewExportData wraps generated or seeded resources in an export stamped with
+// the current time. Their created_at and updated_at timestamps are left empty,
+// so Import stamps them with the manager's clock.
+func NewExportData(resources map[string][]interface{}, relations map[string]map[string]string) *ExportData {
+	return &ExportData{
 		Version:   "1.0",
-		Resources: seedData,
+		Resources: resources,
+		Relations: relations,
 		Timestamps: Timestamps{
-			ExportedAt: now,
-			CreatedAt:  now,
-			UpdatedAt:  now,
+			ExportedAt: time.Now().UTC().Format(time.RFC3339),
 		},
 	}
-
-	if err := globalManager.Import(importData, false); err != nil {
-		return fmt.Errorf("failed to import seed data: %w", err)
-	}
-
-	return nil
 }
 
 type Manager struct {
-	db *sql.DB
+	db    *sql.DB
+	clock clock.Clock
+
+	// indexedFields records the fields an index has been created for
+	indexedFields sync.Map
+
+	// maxItems caps the resources of each type, 0 means no limit
+	maxItems int
+	eviction string
+
+	// expiryMu guards the sweeper started by StartExpiry
+	expiryMu   sync.Mutex
+	stopExpiry chan struct{}
+	expiryDone chan struct{}
 }
 
+// DefaultSweepInterval is how often expired resources are deleted when no
+// interval is given
+const DefaultSweepInterval = time.Minute
+
+// Eviction policies applied when a resource type is at its item limit
+const (
+	// EvictionFIFO removes the oldest resources of the type to make room
+	EvictionFIFO = "fifo"
+	// EvictionReject refuses the new resource
+	EvictionReject = "reject"
+)
+
+// ErrResourceLimit is returned by AddResource when a resource type is at its
+// item limit and the eviction policy is EvictionReject
+var ErrResourceLimit = errors.New("resource limit reached")
+
+// ErrSnapshotNotFound is returned by RestoreSnapshot when no snapshot has the
+// given name
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
 type Resource struct {
 	ID        string          `json:"id"`
 	Type      string          `json:"type"`
@@ -189,6 +258,12 @@ func New(dbPath string) (*Manager, error) {
 		return nil, fmt.Errorf("failed to create resources table: %w", err)
 	}
 
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_resources_type ON resources(type)")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create resources index: %w", err)
+	}
+
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS relationships (
 			source_id TEXT NOT NULL,
@@ -207,10 +282,40 @@ func New(dbPath string) (*Manager, error) {
 		return nil, fmt.Errorf("failed to create relationships table: %w", err)
 	}
 
-	return &Manager{db: db}, nil
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS snapshots (
+			name TEXT PRIMARY KEY,
+			data JSON NOT NULL,
+			created_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create snapshots table: %w", err)
+	}
+
+	return &Manager{db: db, clock: clock.Real{}}, nil
+}
+
+// SetClock sets the clock that created_at and updated_at timestamps are taken from
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// SetLimit caps the number of resources of each type that AddResource
+// stores. maxItems 0 removes the limit. The policy decides what happens to a
+// resource added at the limit, and defaults to EvictionFIFO. Imports aren't
+// capped.
+func (m *Manager) SetLimit(maxItems int, policy string) {
+	if policy == "" {
+		policy = EvictionFIFO
+	}
+	m.maxItems = maxItems
+	m.eviction = policy
 }
 
 func (m *Manager) Close() error {
+	m.StopExpiry()
 	if m.db == nil {
 		return nil
 	}
@@ -239,7 +344,7 @@ func (m *Manager) Export() (*ExportData, error) {
 		Relations:  make(map[string]map[string]string),
 		Metadata:   make(map[string]map[string]interface{}),
 		Timestamps: Timestamps{
-			ExportedAt: time.Now().UTC().Format(time.RFC3339),
+			ExportedAt: m.clock.Now().UTC().Format(time.RFC3339),
 		},
 	}
 
@@ -302,7 +407,117 @@ func (m *Manager) Export() (*ExportData, error) {
 	return data, nil
 }
 
+// ExportTo writes the same document as Export to w, streaming resources
+// from the database one at a time instead of loading them all in memory.
+// Nothing is written if the summary queries fail, but an error while
+// streaming leaves the document incomplete.
+func (m *Manager) ExportTo(w io.Writer) error {
+	// Relations, counts and timestamps are small aggregates, so they are
+	// computed up front and written after the resources
+	summary := struct {
+		Relations  map[string]map[string]string      `json:"relations"`
+		Metadata   map[string]map[string]interface{} `json:"metadata"`
+		Timestamps Timestamps                        `json:"timestamps"`
+	}{
+		Relations: make(map[string]map[string]string),
+		Metadata:  make(map[string]map[string]interface{}),
+		Timestamps: Timestamps{
+			ExportedAt: m.clock.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	var createdAt, updatedAt sql.NullString
+	if err := m.db.QueryRow("SELECT MIN(created_at), MAX(updated_at) FROM resources").Scan(&createdAt, &updatedAt); err != nil {
+		return fmt.Errorf("failed to query timestamps: %w", err)
+	}
+	summary.Timestamps.CreatedAt = createdAt.String
+	summary.Timestamps.UpdatedAt = updatedAt.String
+
+	counts, err := m.ResourceCounts()
+	if err != nil {
+		return err
+	}
+	for resourceType, count := range counts {
+		summary.Metadata[resourceType] = map[string]interface{}{"total_count": count}
+	}
+
+	relRows, err := m.db.Query("SELECT source_type, target_type, type FROM relationships GROUP BY source_type, target_type, type")
+	if err != nil {
+		return fmt.Errorf("failed to query relationships: %w", err)
+	}
+	for relRows.Next() {
+		var sourceType, targetType, relType string
+		if err := relRows.Scan(&sourceType, &targetType, &relType); err != nil {
+			relRows.Close()
+			return fmt.Errorf("failed to scan relationship: %w", err)
+		}
+		if summary.Relations[sourceType] == nil {
+			summary.Relations[sourceType] = make(map[string]string)
+		}
+		summary.Relations[sourceType][targetType] = relType
+	}
+	relRows.Close()
+
+	rows, err := m.db.Query("SELECT type, data FROM resources ORDER BY type, rowid")
+	if err != nil {
+		return fmt.Errorf("failed to query resources: %w", err)
+	}
+	defer rows.Close()
+
+	buf := bufio.NewWriter(w)
+	buf.WriteString(`{"version":"1.0","resources":{`)
+	current := ""
+	first := true
+	for rows.Next() {
+		var resourceType string
+		var data []byte
+		if err := rows.Scan(&resourceType, &data); err != nil {
+			return fmt.Errorf("failed to scan resource: %w", err)
+		}
+
+		if first || resourceType != current {
+			if !first {
+				buf.WriteString("],")
+			}
+			key, _ := json.Marshal(resourceType)
+			buf.Write(key)
+			buf.WriteString(":[")
+			current = resourceType
+		} else {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(data)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read resources: %w", err)
+	}
+	if !first {
+		buf.WriteByte(']')
+	}
+	buf.WriteString("},")
+
+	// The summary completes the document, without its opening brace
+	tail, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode export summary: %w", err)
+	}
+	buf.Write(tail[1:])
+	buf.WriteByte('\n')
+	return buf.Flush()
+}
+
+// Import loads an export into the state, replacing it unless merge is true.
+// Imported resources are updated as of the manager's clock, so the TTL of an
+// old export or snapshot starts over; an empty created_at is taken from the
+// clock as well.
 func (m *Manager) Import(data *ExportData, merge bool) error {
+	updatedAt := m.clock.Now().UTC().Format(time.RFC3339)
+	createdAt := data.Timestamps.CreatedAt
+	if createdAt == "" {
+		createdAt = updatedAt
+	}
+
 	tx, err := m.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
@@ -341,8 +556,8 @@ func (m *Manager) Import(data *ExportData, merge bool) error {
 				id,
 				resourceType,
 				resourceData,
-				data.Timestamps.CreatedAt,
-				data.Timestamps.UpdatedAt,
+				createdAt,
+				updatedAt,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to insert resource: %w", err)
@@ -379,7 +594,7 @@ func (m *Manager) Import(data *ExportData, merge bool) error {
 							targetID,
 							targetType,
 							relType,
-							data.Timestamps.CreatedAt,
+							createdAt,
 						)
 						if err != nil {
 							return fmt.Errorf("failed to insert relationship: %w", err)
@@ -396,7 +611,7 @@ func (m *Manager) Import(data *ExportData, merge bool) error {
 							targetID,
 							targetType,
 							relType,
-							data.Timestamps.CreatedAt,
+							createdAt,
 						)
 						if err != nil {
 							return fmt.Errorf("failed to insert relationship: %w", err)
@@ -427,6 +642,66 @@ func (m *Manager) Reset() error {
 	return tx.Commit()
 }
 
+// SaveSnapshot stores the current state under a name, replacing any
+// snapshot with the same name
+func (m *Manager) SaveSnapshot(name string) error {
+	data, err := m.Export()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	_, err = m.db.Exec(
+		"INSERT OR REPLACE INTO snapshots (name, data, created_at) VALUES (?, ?, ?)",
+		name, encoded, m.clock.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the current state with a saved snapshot
+func (m *Manager) RestoreSnapshot(name string) error {
+	var encoded []byte
+	err := m.db.QueryRow("SELECT data FROM snapshots WHERE name = ?", name).Scan(&encoded)
+	if err == sql.ErrNoRows {
+		return ErrSnapshotNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	var data ExportData
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return m.Import(&data, false)
+}
+
+// Snapshots returns the names of the saved snapshots in name order
+func (m *Manager) Snapshots() ([]string, error) {
+	rows, err := m.db.Query("SELECT name FROM snapshots ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 type ExportData struct {
 	Version    string                            `json:"version"`
 	Resources  map[string][]interface{}          `json:"resources"`
@@ -441,6 +716,26 @@ type Timestamps struct {
 	UpdatedAt  string `json:"updated_at"`
 }
 
+// ResourceCounts returns the number of stored resources of each type
+func (m *Manager) ResourceCounts() (map[string]int, error) {
+	rows, err := m.db.Query("SELECT type, COUNT(*) FROM resources GROUP BY type")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count resources: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var resourceType string
+		var count int
+		if err := rows.Scan(&resourceType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan resource count: %w", err)
+		}
+		counts[resourceType] = count
+	}
+	return counts, rows.Err()
+}
+
 func (m *Manager) GetResources(resourceType string) ([]interface{}, error) {
 	if m.db == nil {
 		return nil, fmt.Errorf("database connection not initialized")
@@ -471,25 +766,129 @@ func (m *Manager) GetResources(resourceType string) ([]interface{}, error) {
 }
 
 func (m *Manager) GetResource(resourceType, id string) (interface{}, error) {
+	resource, _, err := m.GetResourceVersion(resourceType, id)
+	return resource, err
+}
+
+// GetResourceVersion returns a resource along with its updated_at timestamp,
+// read together so that the two always match
+func (m *Manager) GetResourceVersion(resourceType, id string) (interface{}, string, error) {
 	if m.db == nil {
-		return nil, fmt.Errorf("database connection not initialized")
+		return nil, "", fmt.Errorf("database connection not initialized")
 	}
 
 	var data []byte
-	err := m.db.QueryRow("SELECT data FROM resources WHERE type = ? AND id = ?", resourceType, id).Scan(&data)
+	var updatedAt string
+	err := m.db.QueryRow("SELECT data, updated_at FROM resources WHERE type = ? AND id = ?", resourceType, id).Scan(&data, &updatedAt)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("resource not found")
+		return nil, "", fmt.Errorf("resource not found")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get resource: %w", err)
+		return nil, "", fmt.Errorf("failed to get resource: %w", err)
 	}
 
 	var resource interface{}
 	if err := json.Unmarshal(data, &resource); err != nil {
-		return nil, fmt.Errorf("failed to parse resource data: %w", err)
+		return nil, "", fmt.Errorf("failed to parse resource data: %w", err)
+	}
+
+	return resource, updatedAt, nil
+}
+
+// FindResourceIDByField returns the ID of a resource of the given type whose
+// top-level field equals value, or "" if there is none
+func (m *Manager) FindResourceIDByField(resourceType, field string, value interface{}) (string, error) {
+	if m.db == nil {
+		return "", fmt.Errorf("database connection not initialized")
+	}
+
+	path := fmt.Sprintf(`$."%s"`, strings.ReplaceAll(field, `"`, `\"`))
+
+	var id string
+	err := m.db.QueryRow(
+		"SELECT id FROM resources WHERE type = ? AND json_extract(data, ?) = ? LIMIT 1",
+		resourceType, path, value,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query resources: %w", err)
 	}
 
-	return resource, nil
+	return id, nil
+}
+
+// GetResourcesByField returns the resources of the given type whose top-level
+// field equals value. Values are compared by their text form, so "1" matches
+// both 1 and "1". Foreign key fields such as owner_id or ownerId are indexed
+// the first time they are queried.
+func (m *Manager) GetResourcesByField(resourceType, field string, value interface{}) ([]interface{}, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database connection not initialized")
+	}
+
+	expr := fieldExpression(field)
+	if indexableField.MatchString(field) {
+		if err := m.ensureFieldIndex(field, expr); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := m.db.Query(
+		fmt.Sprintf("SELECT data FROM resources WHERE type = ? AND %s = ? ORDER BY rowid", expr),
+		resourceType, fmt.Sprintf("%v", value),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resources: %w", err)
+	}
+	defer rows.Close()
+
+	var resources []interface{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan resource: %w", err)
+		}
+
+		var resource interface{}
+		if err := json.Unmarshal(data, &resource); err != nil {
+			return nil, fmt.Errorf("failed to parse resource data: %w", err)
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// indexableField matches the foreign key fields that get an index
+var indexableField = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(_id|Id)$`)
+
+// fieldExpression returns the SQL expression for the text form of a
+// top-level field of the resource data. The JSON path is inlined rather than
+// bound so that the expression can match an index.
+func fieldExpression(field string) string {
+	path := fmt.Sprintf(`$."%s"`, strings.ReplaceAll(field, `"`, `\"`))
+	return fmt.Sprintf("CAST(json_extract(data, '%s') AS TEXT)", strings.ReplaceAll(path, "'", "''"))
+}
+
+// ensureFieldIndex creates an index on the type and the expression of a field
+// unless one was already created
+func (m *Manager) ensureFieldIndex(field, expr string) error {
+	if _, done := m.indexedFields.Load(field); done {
+		return nil
+	}
+
+	_, err := m.db.Exec(fmt.Sprintf(
+		"CREATE INDEX IF NOT EXISTS idx_resources_%s ON resources(type, %s)", field, expr,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to create index on %s: %w", field, err)
+	}
+
+	m.indexedFields.Store(field, true)
+	return nil
 }
 
 func (m *Manager) AddResource(resourceType string, data interface{}) error {
@@ -509,9 +908,21 @@ func (m *Manager) AddResource(resourceType string, data interface{}) error {
 	}
 
 	id := fmt.Sprintf("%v", idVal)
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := m.clock.Now().UTC().Format(time.RFC3339)
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if m.maxItems > 0 {
+		if err := m.makeRoom(tx, resourceType); err != nil {
+			return err
+		}
+	}
 
-	_, err = m.db.Exec(`
+	_, err = tx.Exec(`
 		INSERT INTO resources (id, type, data, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?)
 	`, id, resourceType, resourceData, now, now)
@@ -519,6 +930,139 @@ func (m *Manager) AddResource(resourceType string, data interface{}) error {
 		return fmt.Errorf("failed to insert resource: %w", err)
 	}
 
+	return tx.Commit()
+}
+
+// makeRoom applies the eviction policy when a resource type is at its item
+// limit, removing its oldest resources by created_at with EvictionFIFO
+func (m *Manager) makeRoom(tx *sql.Tx, resourceType string) error {
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM resources WHERE type = ?", resourceType).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count resources: %w", err)
+	}
+	if count < m.maxItems {
+		return nil
+	}
+	if m.eviction == EvictionReject {
+		return fmt.Errorf("%w: %s has %d items", ErrResourceLimit, resourceType, count)
+	}
+
+	oldest := `
+		SELECT id FROM resources WHERE type = ?
+		ORDER BY created_at, rowid LIMIT ?
+	`
+	excess := count - m.maxItems + 1
+
+	_, err := tx.Exec(`
+		DELETE FROM relationships
+		WHERE source_id IN (`+oldest+`) OR target_id IN (`+oldest+`)
+	`, resourceType, excess, resourceType, excess)
+	if err != nil {
+		return fmt.Errorf("failed to delete relationships: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM resources WHERE id IN (`+oldest+`)`, resourceType, excess)
+	if err != nil {
+		return fmt.Errorf("failed to evict resources: %w", err)
+	}
+
+	return nil
+}
+
+// StartExpiry starts deleting, every interval, the resources that haven't
+// been created or updated within ttl, as of the manager's clock. A non
+// positive interval means DefaultSweepInterval. A sweeper that is already
+// running is replaced. Close or StopExpiry stop it.
+func (m *Manager) StartExpiry(ttl, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+
+	m.StopExpiry()
+
+	m.expiryMu.Lock()
+	defer m.expiryMu.Unlock()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	m.stopExpiry, m.expiryDone = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := m.ExpireResources(ttl); err != nil {
+					log.Printf("Warning: failed to expire resources: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// StopExpiry stops the sweeper started by StartExpiry and waits for it to
+// return. It does nothing if no sweeper is running.
+func (m *Manager) StopExpiry() {
+	m.expiryMu.Lock()
+	defer m.expiryMu.Unlock()
+
+	if m.stopExpiry == nil {
+		return
+	}
+	close(m.stopExpiry)
+	<-m.expiryDone
+	m.stopExpiry, m.expiryDone = nil, nil
+}
+
+// ExpireResources deletes the resources, and their relationships, whose
+// updated_at is older than ttl, and returns how many were deleted
+func (m *Manager) ExpireResources(ttl time.Duration) (int, error) {
+	cutoff := m.clock.Now().Add(-ttl).UTC().Format(time.RFC3339)
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired := "SELECT id FROM resources WHERE updated_at < ?"
+
+	_, err = tx.Exec(`
+		DELETE FROM relationships
+		WHERE source_id IN (`+expired+`) OR target_id IN (`+expired+`)
+	`, cutoff, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete relationships: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM resources WHERE updated_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete resources: %w", err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(count), tx.Commit()
+}
+
+// AddRelation records that the source resource relates to the target
+// resource with a relationship type such as one_to_many. Both resources must
+// exist. Exports list the types of relationships between resource types.
+func (m *Manager) AddRelation(sourceType, sourceID, targetType, targetID, relType string) error {
+	_, err := m.db.Exec(`
+		INSERT OR REPLACE INTO relationships (source_id, source_type, target_id, target_type, type, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, sourceID, sourceType, targetID, targetType, relType, m.clock.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to insert relationship: %w", err)
+	}
 	return nil
 }
 
@@ -528,7 +1072,7 @@ func (m *Manager) UpdateResource(resourceType, id string, data interface{}) erro
 		return fmt.Errorf("failed to marshal resource data: %w", err)
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := m.clock.Now().UTC().Format(time.RFC3339)
 
 	result, err := m.db.Exec(`
 		UPDATE resources
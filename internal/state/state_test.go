@@ -1,9 +1,16 @@
 package state
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/felipevolpatto/meridian/internal/clock"
+	"github.com/felipevolpatto/meridian/internal/generator"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,6 +95,232 @@ func TestStateManagement(t *testing.T) {
 	assert.Empty(t, users)
 }
 
+func TestFindResourceIDByField(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{
+		"id":     "1",
+		"email":  "alice@example.com",
+		"age":    30,
+		"active": true,
+	}))
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{
+		"id":    "2",
+		"email": "bob@example.com",
+	}))
+
+	id, err := manager.FindResourceIDByField("users", "email", "alice@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	id, err = manager.FindResourceIDByField("users", "age", float64(30))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	id, err = manager.FindResourceIDByField("users", "active", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	// Matches are scoped to the resource type
+	id, err = manager.FindResourceIDByField("users", "email", "bob@example.com")
+	assert.NoError(t, err)
+	assert.Empty(t, id)
+}
+
+func TestGetResourcesByField(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	for _, post := range []map[string]interface{}{
+		{"id": "1", "owner_id": "10", "title": "First"},
+		{"id": "2", "owner_id": 10, "title": "Second"},
+		{"id": "3", "owner_id": "11", "title": "Third"},
+		{"id": "4", "title": "Orphan"},
+	} {
+		assert.NoError(t, manager.AddResource("posts", post))
+	}
+	assert.NoError(t, manager.AddResource("comments", map[string]interface{}{
+		"id": "5", "owner_id": "10",
+	}))
+
+	// String and numeric values match by their text form
+	posts, err := manager.GetResourcesByField("posts", "owner_id", "10")
+	assert.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, "First", posts[0].(map[string]interface{})["title"])
+	assert.Equal(t, "Second", posts[1].(map[string]interface{})["title"])
+
+	posts, err = manager.GetResourcesByField("posts", "title", "Third")
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+
+	posts, err = manager.GetResourcesByField("posts", "owner_id", "12")
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+
+	// Foreign key fields are looked up through an index
+	var plan, detail string
+	var id, parent, notUsed int
+	rows, err := manager.db.Query("EXPLAIN QUERY PLAN SELECT data FROM resources WHERE type = ? AND "+fieldExpression("owner_id")+" = ?", "posts", "10")
+	assert.NoError(t, err)
+	for rows.Next() {
+		assert.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+		plan += detail
+	}
+	rows.Close()
+	assert.Contains(t, plan, "idx_resources_owner_id")
+}
+
+func TestResourceLimit(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	addUsers := func(from, to int) {
+		for i := from; i < to; i++ {
+			assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": fmt.Sprintf("u%d", i)}))
+		}
+	}
+
+	// fifo keeps the newest items
+	manager.SetLimit(3, EvictionFIFO)
+	addUsers(0, 3)
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{"id": "p1"}))
+	assert.NoError(t, manager.AddRelation("users", "u0", "posts", "p1", "one_to_many"))
+	addUsers(3, 5)
+
+	counts, err := manager.ResourceCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"users": 3, "posts": 1}, counts)
+
+	_, err = manager.GetResource("users", "u0")
+	assert.Error(t, err)
+	_, err = manager.GetResource("users", "u4")
+	assert.NoError(t, err)
+
+	exported, err := manager.Export()
+	assert.NoError(t, err)
+	assert.Empty(t, exported.Relations)
+
+	// reject refuses items past the limit
+	manager.SetLimit(3, EvictionReject)
+	err = manager.AddResource("users", map[string]interface{}{"id": "u5"})
+	assert.ErrorIs(t, err, ErrResourceLimit)
+
+	counts, err = manager.ResourceCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counts["users"])
+
+	// Other types have their own limit
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{"id": "p2"}))
+}
+
+func TestExpiry(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	c := clock.NewVirtual()
+	manager.SetClock(c)
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "1"}))
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{"id": "2"}))
+	assert.NoError(t, manager.AddRelation("users", "1", "posts", "2", "one_to_many"))
+
+	c.Shift(30 * time.Minute)
+	assert.NoError(t, manager.UpdateResource("posts", "2", map[string]interface{}{"id": "2", "title": "Updated"}))
+
+	manager.StartExpiry(time.Hour, 10*time.Millisecond)
+	c.Shift(75 * time.Minute)
+
+	// users/1 is past its TTL, posts/2 was updated since
+	assert.Eventually(t, func() bool {
+		_, err := manager.GetResource("users", "1")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = manager.GetResource("posts", "2")
+	assert.NoError(t, err)
+
+	exported, err := manager.Export()
+	assert.NoError(t, err)
+	assert.Empty(t, exported.Relations)
+
+	manager.StopExpiry()
+	c.Shift(3 * time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	_, err = manager.GetResource("posts", "2")
+	assert.NoError(t, err)
+}
+
+func TestAddRelation(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "1"}))
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{"id": "2", "user_id": "1"}))
+
+	assert.NoError(t, manager.AddRelation("users", "1", "posts", "2", "one_to_many"))
+	assert.NoError(t, manager.AddRelation("users", "1", "posts", "2", "one_to_many"), "recording an edge twice is harmless")
+	assert.Error(t, manager.AddRelation("users", "1", "posts", "3", "one_to_many"), "both resources must exist")
+
+	data, err := manager.Export()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"users": {"posts": "one_to_many"}}, data.Relations)
+
+	// Deleting a resource removes its edges
+	assert.NoError(t, manager.DeleteResource("posts", "2"))
+	data, err = manager.Export()
+	assert.NoError(t, err)
+	assert.Empty(t, data.Relations)
+}
+
+func TestResourceCounts(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	counts, err := manager.ResourceCounts()
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "1"}))
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "2"}))
+	assert.NoError(t, manager.AddResource("posts", map[string]interface{}{"id": "3"}))
+
+	counts, err = manager.ResourceCounts()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"users": 2, "posts": 1}, counts)
+}
+
 func TestImportExport(t *testing.T) {
 	// Create temporary file for testing
 	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
@@ -161,6 +394,216 @@ func TestImportExport(t *testing.T) {
 	assert.Contains(t, users, map[string]interface{}{"id": float64(3), "name": "Charlie"})
 }
 
+func TestExportTo(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	// exportBoth returns Export and the decoded output of ExportTo
+	exportBoth := func() (*ExportData, *ExportData) {
+		exported, err := manager.Export()
+		assert.NoError(t, err)
+		data, err := json.Marshal(exported)
+		assert.NoError(t, err)
+		var want ExportData
+		assert.NoError(t, json.Unmarshal(data, &want))
+
+		var buf bytes.Buffer
+		assert.NoError(t, manager.ExportTo(&buf))
+		var got ExportData
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+		got.Timestamps.ExportedAt = want.Timestamps.ExportedAt
+		return &want, &got
+	}
+
+	want, got := exportBoth()
+	assert.Equal(t, want, got)
+	assert.Empty(t, got.Resources)
+
+	for i := 0; i < 500; i++ {
+		resourceType := "users"
+		if i%3 == 0 {
+			resourceType = "posts"
+		}
+		assert.NoError(t, manager.AddResource(resourceType, map[string]interface{}{
+			"id":   fmt.Sprintf("%d", i),
+			"name": fmt.Sprintf("Item %d", i),
+		}))
+	}
+	assert.NoError(t, manager.AddRelation("users", "1", "posts", "0", "one_to_many"))
+
+	want, got = exportBoth()
+	assert.Equal(t, want, got)
+	assert.Len(t, got.Resources["posts"], 167)
+	assert.Len(t, got.Resources["users"], 333)
+}
+
+func TestSnapshots(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "1", "name": "Alice"}))
+	assert.NoError(t, manager.SaveSnapshot("baseline"))
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "2", "name": "Bob"}))
+	assert.NoError(t, manager.RestoreSnapshot("baseline"))
+
+	users, err := manager.GetResources("users")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "1", "name": "Alice"}}, users)
+
+	names, err := manager.Snapshots()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baseline"}, names)
+
+	err = manager.RestoreSnapshot("missing")
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestSetClock(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	c := clock.NewVirtual()
+	c.Freeze(time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC))
+	manager.SetClock(c)
+
+	assert.NoError(t, manager.AddResource("users", map[string]interface{}{"id": "1", "name": "Alice"}))
+
+	data, err := manager.Export()
+	assert.NoError(t, err)
+	assert.Equal(t, "2030-06-01T12:00:00Z", data.Timestamps.CreatedAt)
+	assert.Equal(t, "2030-06-01T12:00:00Z", data.Timestamps.ExportedAt)
+}
+
+func TestImport_StampsWithClock(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	manager, err := New(tmpDB.Name())
+	assert.NoError(t, err)
+	defer manager.Close()
+
+	c := clock.NewVirtual()
+	c.Freeze(time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC))
+	manager.SetClock(c)
+
+	// A full export without timestamps
+	data, err := parseSeedData([]byte(`{"version": "1.0", "resources": {"users": [{"id": "1"}]}}`))
+	assert.NoError(t, err)
+	assert.NoError(t, manager.Import(data, false))
+
+	expired, err := manager.ExpireResources(time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	// An export older than the TTL starts over too
+	old := NewExportData(map[string][]interface{}{"users": {map[string]interface{}{"id": "3"}}}, nil)
+	old.Timestamps.CreatedAt = "2020-01-01T00:00:00Z"
+	old.Timestamps.UpdatedAt = "2020-01-01T00:00:00Z"
+	assert.NoError(t, manager.Import(old, true))
+
+	expired, err = manager.ExpireResources(time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, expired)
+
+	assert.NoError(t, manager.Import(NewExportData(map[string][]interface{}{"posts": {map[string]interface{}{"id": "2"}}}, nil), true))
+
+	export, err := manager.Export()
+	assert.NoError(t, err)
+	assert.Equal(t, "2020-01-01T00:00:00Z", export.Timestamps.CreatedAt)
+	assert.Equal(t, "2030-06-01T12:00:00Z", export.Timestamps.UpdatedAt)
+	assert.Len(t, export.Resources["users"], 2)
+	assert.Len(t, export.Resources["posts"], 1)
+}
+
+func TestAutoSeedRelationships(t *testing.T) {
+	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
+	assert.NoError(t, err)
+	defer os.Remove(tmpDB.Name())
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Test API", Version: "1.0.0"},
+		Paths:   &openapi3.Paths{},
+	}
+	for _, resource := range []string{"/users", "/posts", "/profiles"} {
+		spec.Paths.Set(resource, &openapi3.PathItem{
+			Post: &openapi3.Operation{
+				RequestBody: &openapi3.RequestBodyRef{
+					Value: openapi3.NewRequestBody().WithJSONSchema(
+						openapi3.NewObjectSchema().WithProperty("name", openapi3.NewStringSchema()),
+					),
+				},
+			},
+		})
+	}
+
+	err = InitializeWithOptions(InitializeOptions{
+		DBPath:          tmpDB.Name(),
+		AutoSeedEnabled: true,
+		AutoSeedConfig: generator.AutoSeedConfig{
+			ItemsPerResource: 2,
+			Relationships: map[string]map[string]string{
+				"users": {
+					"posts":    "one_to_many",
+					"profiles": "one_to_one",
+					"missing":  "one_to_many",
+				},
+			},
+		},
+		Spec: spec,
+	})
+	assert.NoError(t, err)
+	defer Close()
+
+	exportData, err := GetManager().Export()
+	assert.NoError(t, err)
+	assert.Len(t, exportData.Resources["users"], 2)
+	assert.Equal(t, map[string]map[string]string{
+		"users": {
+			"posts":    "one_to_many",
+			"profiles": "one_to_one",
+		},
+	}, exportData.Relations)
+}
+
+func TestParseSeedData(t *testing.T) {
+	plain, err := parseSeedData([]byte(`{"users": [{"id": "1", "name": "Alice"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", plain.Version)
+	assert.Len(t, plain.Resources["users"], 1)
+
+	export, err := parseSeedData([]byte(`{
+		"version": "1.0",
+		"resources": {"users": [{"id": "1"}, {"id": "2"}], "posts": [{"id": "1", "userId": "1"}]},
+		"relations": {"users": {"posts": "one_to_many"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Len(t, export.Resources["users"], 2)
+	assert.Len(t, export.Resources["posts"], 1)
+	assert.Equal(t, "one_to_many", export.Relations["users"]["posts"])
+
+	_, err = parseSeedData([]byte(`[1, 2, 3]`))
+	assert.Error(t, err)
+}
+
 func TestInvalidOperations(t *testing.T) {
 	// Create temporary file for testing
 	tmpDB, err := os.CreateTemp("", "meridian_test_*.db")
@@ -0,0 +1,277 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/felipevolpatto/meridian/internal/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// mergeAllOf flattens a schema's allOf subschemas into a single schema, the
+// same way GenerateFromAllOf combines them when generating data. Properties
+// and required lists are unioned, and the stricter of each constraint wins.
+// Schemas without allOf are returned unchanged.
+func mergeAllOf(schema *openapi3.Schema) *openapi3.Schema {
+	if schema == nil || len(schema.AllOf) == 0 {
+		return schema
+	}
+
+	merged := *schema
+	merged.AllOf = nil
+	merged.Properties = make(openapi3.Schemas, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		merged.Properties[name] = prop
+	}
+	merged.Required = append([]string(nil), schema.Required...)
+
+	for _, ref := range schema.AllOf {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		mergeSchemaInto(&merged, mergeAllOf(ref.Value))
+	}
+
+	return &merged
+}
+
+// mergeSchemaInto adds the constraints of sub to merged
+func mergeSchemaInto(merged, sub *openapi3.Schema) {
+	if merged.Type == "" {
+		merged.Type = sub.Type
+	}
+	if merged.Format == "" {
+		merged.Format = sub.Format
+	}
+	if merged.Pattern == "" {
+		merged.Pattern = sub.Pattern
+	}
+	if len(merged.Enum) == 0 {
+		merged.Enum = sub.Enum
+	}
+	if merged.Items == nil {
+		merged.Items = sub.Items
+	}
+
+	// Properties declared directly on the schema override inherited ones
+	for name, prop := range sub.Properties {
+		if _, exists := merged.Properties[name]; !exists {
+			merged.Properties[name] = prop
+		}
+	}
+
+	for _, name := range sub.Required {
+		if !containsString(merged.Required, name) {
+			merged.Required = append(merged.Required, name)
+		}
+	}
+
+	if sub.AdditionalProperties.Has != nil && !*sub.AdditionalProperties.Has {
+		merged.AdditionalProperties.Has = sub.AdditionalProperties.Has
+	} else if merged.AdditionalProperties.Schema == nil && merged.AdditionalProperties.Has == nil {
+		merged.AdditionalProperties.Schema = sub.AdditionalProperties.Schema
+	}
+
+	if sub.MinLength > merged.MinLength {
+		merged.MinLength = sub.MinLength
+	}
+	merged.MaxLength = minUint64Ptr(merged.MaxLength, sub.MaxLength)
+
+	if sub.MinItems > merged.MinItems {
+		merged.MinItems = sub.MinItems
+	}
+	merged.MaxItems = minUint64Ptr(merged.MaxItems, sub.MaxItems)
+	merged.UniqueItems = merged.UniqueItems || sub.UniqueItems
+
+	if sub.MinProps > merged.MinProps {
+		merged.MinProps = sub.MinProps
+	}
+	merged.MaxProps = minUint64Ptr(merged.MaxProps, sub.MaxProps)
+
+	if sub.Min != nil && (merged.Min == nil || *sub.Min > *merged.Min) {
+		merged.Min = sub.Min
+		merged.ExclusiveMin = sub.ExclusiveMin
+	}
+	if sub.Max != nil && (merged.Max == nil || *sub.Max < *merged.Max) {
+		merged.Max = sub.Max
+		merged.ExclusiveMax = sub.ExclusiveMax
+	}
+	if merged.MultipleOf == nil {
+		merged.MultipleOf = sub.MultipleOf
+	}
+}
+
+// composition is a keyword that validates a value against other schemas:
+// oneOf, anyOf, not or if (with then/else)
+type composition struct {
+	keyword     string
+	branches    openapi3.SchemaRefs
+	conditional *openapi.Conditional
+}
+
+// compositionsOf collects the composition keywords of a schema and of its
+// allOf subschemas, all of which a value must satisfy
+func compositionsOf(schema *openapi3.Schema) []composition {
+	if schema == nil {
+		return nil
+	}
+
+	var result []composition
+	if len(schema.OneOf) > 0 {
+		result = append(result, composition{keyword: "oneOf", branches: schema.OneOf})
+	}
+	if len(schema.AnyOf) > 0 {
+		result = append(result, composition{keyword: "anyOf", branches: schema.AnyOf})
+	}
+	if schema.Not != nil && schema.Not.Value != nil {
+		result = append(result, composition{keyword: "not", branches: openapi3.SchemaRefs{schema.Not}})
+	}
+	if cond := openapi.SchemaConditional(schema); cond != nil {
+		result = append(result, composition{keyword: "if", conditional: cond})
+	}
+	for _, ref := range schema.AllOf {
+		if ref != nil {
+			result = append(result, compositionsOf(ref.Value)...)
+		}
+	}
+	return result
+}
+
+// check runs validate against the keyword's schemas and returns the error
+// code and message when the value doesn't satisfy the keyword
+func (c composition) check(validate func(*openapi3.Schema) []string) (code, message string) {
+	switch c.keyword {
+	case "not":
+		if len(validate(c.branches[0].Value)) == 0 {
+			return "not_matched", "value must not match the schema in not"
+		}
+		return "", ""
+	case "if":
+		return c.checkConditional(validate)
+	}
+
+	return c.checkBranches(validate)
+}
+
+// checkBranches counts the oneOf/anyOf branches the value matches. oneOf
+// requires exactly one match and anyOf at least one; failures list every
+// branch that was tried and why it failed.
+func (c composition) checkBranches(validate func(*openapi3.Schema) []string) (code, message string) {
+	var matched, failures []string
+
+	for i, ref := range c.branches {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		name := branchName(ref, i)
+		if errs := validate(ref.Value); len(errs) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, strings.Join(errs, "; ")))
+		} else {
+			matched = append(matched, name)
+		}
+	}
+
+	switch {
+	case len(matched) == 0 && len(failures) == 0:
+		return "", ""
+	case len(matched) == 0:
+		return strings.ToLower(c.keyword) + "_no_match",
+			fmt.Sprintf("value does not match any %s branch (%s)", c.keyword, strings.Join(failures, "; "))
+	case len(matched) > 1 && c.keyword == "oneOf":
+		return "oneof_multiple_match",
+			fmt.Sprintf("value matches more than one oneOf branch (%s)", strings.Join(matched, ", "))
+	}
+	return "", ""
+}
+
+// checkConditional applies then when the value matches if, and else otherwise
+func (c composition) checkConditional(validate func(*openapi3.Schema) []string) (code, message string) {
+	cond := c.conditional
+	if len(validate(cond.If.Value)) == 0 {
+		if cond.Then != nil && cond.Then.Value != nil {
+			if errs := validate(cond.Then.Value); len(errs) > 0 {
+				return "conditional_failed", fmt.Sprintf("value matches if but not then (%s)", strings.Join(errs, "; "))
+			}
+		}
+		return "", ""
+	}
+
+	if cond.Else != nil && cond.Else.Value != nil {
+		if errs := validate(cond.Else.Value); len(errs) > 0 {
+			return "conditional_failed", fmt.Sprintf("value does not match if or else (%s)", strings.Join(errs, "; "))
+		}
+	}
+	return "", ""
+}
+
+// validateComposition validates value against the oneOf, anyOf, not and
+// if/then/else keywords of schema and its allOf subschemas
+func validateComposition(schema *openapi3.Schema, value interface{}, path string, dir direction, depth int) ValidationErrors {
+	var errors ValidationErrors
+
+	for _, c := range compositionsOf(schema) {
+		code, message := c.check(func(branch *openapi3.Schema) []string {
+			var messages []string
+			for _, err := range validateValue(branch, value, path, dir, depth).Errors() {
+				messages = append(messages, err.Error())
+			}
+			return messages
+		})
+		if code != "" {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: message,
+				Code:    code,
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateSchemaComposition is validateComposition for ValidateSchemaValue
+func validateSchemaComposition(schema *openapi3.Schema, value interface{}) []string {
+	var errors []string
+
+	for _, c := range compositionsOf(schema) {
+		_, message := c.check(func(branch *openapi3.Schema) []string {
+			return ValidateSchemaValue(branch, value)
+		})
+		if message != "" {
+			errors = append(errors, message)
+		}
+	}
+
+	return errors
+}
+
+// branchName names a composition branch after its component schema, falling
+// back to its position for inline schemas
+func branchName(ref *openapi3.SchemaRef, index int) string {
+	if ref.Ref != "" {
+		return ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+	}
+	if ref.Value.Title != "" {
+		return ref.Value.Title
+	}
+	return fmt.Sprintf("branch %d", index)
+}
+
+func minUint64Ptr(a, b *uint64) *uint64 {
+	if a == nil {
+		return b
+	}
+	if b != nil && *b < *a {
+		return b
+	}
+	return a
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package validation
+
+import "sync/atomic"
+
+// DefaultMaxDepth is how deeply objects and arrays may nest in validated
+// data when no depth is configured
+const DefaultMaxDepth = 64
+
+// maxDepth holds the configured nesting depth, or 0 for DefaultMaxDepth
+var maxDepth atomic.Int64
+
+// SetMaxDepth sets how deeply objects and arrays may nest in validated data.
+// Deeper values fail with max_depth_exceeded instead of being validated. A
+// depth of 0 or less selects DefaultMaxDepth.
+func SetMaxDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	maxDepth.Store(int64(depth))
+}
+
+// currentMaxDepth returns the configured nesting depth
+func currentMaxDepth() int {
+	if depth := maxDepth.Load(); depth > 0 {
+		return int(depth)
+	}
+	return DefaultMaxDepth
+}
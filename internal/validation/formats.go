@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// extendedEmail enables RFC 5322 display-name addresses and address lists in
+// email format validation
+var extendedEmail atomic.Bool
+
+// SetExtendedEmail toggles extended email validation. When enabled, values of
+// format email are parsed as RFC 5322 address lists, so display-name forms
+// such as "Jane Doe <jane@example.com>" and comma-separated lists are valid.
+func SetExtendedEmail(enabled bool) {
+	extendedEmail.Store(enabled)
+}
+
+// knownFormats lists the string formats the validator recognizes. password
+// and binary are annotations with nothing to check; any other format is
+// reported as a warning instead of silently passing.
+var knownFormats = map[string]bool{
+	"email":     true,
+	"uri":       true,
+	"uuid":      true,
+	"hostname":  true,
+	"ipv4":      true,
+	"ipv6":      true,
+	"time":      true,
+	"byte":      true,
+	"date":      true,
+	"date-time": true,
+	"password":  true,
+	"binary":    true,
+}
+
+// unknownFormatWarning reports a string format the validator cannot check
+func unknownFormatWarning(format, path string) *ValidationError {
+	return &ValidationError{
+		Field:    path,
+		Message:  fmt.Sprintf("unknown format %q is not validated", format),
+		Code:     "unknown_format",
+		Severity: SeverityWarning,
+	}
+}
+
+// MatchesFormat reports whether value satisfies a string format. Formats the
+// validator cannot check are treated as satisfied.
+func MatchesFormat(format, value string) bool {
+	for _, err := range validateStringFormat(format, value, "") {
+		if !err.IsWarning() {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidEmail reports whether value is a valid email in the configured mode
+func isValidEmail(value string) bool {
+	if extendedEmail.Load() {
+		_, err := mail.ParseAddressList(value)
+		return err == nil
+	}
+
+	// Without extended mode only bare addresses are accepted
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Name == "" && address.Address == value
+}
+
+// isValidURI reports whether value is an absolute URI
+func isValidURI(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	return err == nil && u.Scheme != ""
+}
+
+// isValidUUID reports whether value is a UUID in its canonical hyphenated form
+func isValidUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+// isValidHostname reports whether value is an RFC 1123 hostname
+func isValidHostname(value string) bool {
+	if value == "" || len(value) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(value, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isValidIPv4 reports whether value is a dotted-quad IPv4 address
+func isValidIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+}
+
+// isValidIPv6 reports whether value is an IPv6 address
+func isValidIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+// timeLayouts are the accepted layouts for format time, matching the CLI validator
+var timeLayouts = []string{
+	"15:04:05",
+	"15:04:05.0",
+	"15:04:05.00",
+	"15:04:05.000",
+	"15:04:05.0000",
+	"15:04:05.00000",
+	"15:04:05.000000",
+}
+
+// isValidTime reports whether value is a time of day in HH:MM:SS[.ffffff] form
+func isValidTime(value string) bool {
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidByte reports whether value is standard padded base64
+func isValidByte(value string) bool {
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
@@ -208,7 +208,7 @@ func (v *ResponseValidator) validateResponseContent(response *openapi3.Response,
 			}
 		}
 
-		if errs := validateValue(matchedContent.Schema.Value, data, ""); len(errs) > 0 {
+		if errs := validateValue(matchedContent.Schema.Value, data, "", directionResponse, 0); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
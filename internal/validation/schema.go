@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
@@ -12,6 +13,10 @@ import (
 func ValidateSchemaValue(schema *openapi3.Schema, value interface{}) []string {
 	var errors []string
 
+	// Inherited allOf constraints apply as if declared on the schema itself
+	original := schema
+	schema = mergeAllOf(schema)
+
 	// Handle nil value
 	if value == nil {
 		if schema.Nullable {
@@ -20,15 +25,21 @@ func ValidateSchemaValue(schema *openapi3.Schema, value interface{}) []string {
 		return []string{"value cannot be null"}
 	}
 
-	// Get the schema type
-	if schema.Type == "" {
-		return nil
+	// Composition keywords (oneOf, anyOf, not, if/then/else) must hold
+	if errs := validateSchemaComposition(original, value); len(errs) > 0 {
+		return errs
 	}
 
 	valueType := reflect.TypeOf(value)
 
+	// Untyped schemas apply their constraints to values of the matching type
+	schemaType := schema.Type
+	if schemaType == "" {
+		schemaType = kindType(valueType.Kind())
+	}
+
 	// Validate type
-	switch schema.Type {
+	switch schemaType {
 	case "string":
 		if valueType.Kind() != reflect.String {
 			errors = append(errors, fmt.Sprintf("expected string, got %T", value))
@@ -101,17 +112,37 @@ func validateSchemaString(schema *openapi3.Schema, value string) []string {
 	if schema.Format != "" {
 		switch schema.Format {
 		case "email":
-			if !strings.Contains(value, "@") {
+			if !isValidEmail(value) {
 				errors = append(errors, "invalid email format")
 			}
 		case "uri":
-			if !strings.Contains(value, "://") {
+			if !isValidURI(value) {
 				errors = append(errors, "invalid URI format")
 			}
 		case "uuid":
-			if len(value) != 36 {
+			if !isValidUUID(value) {
 				errors = append(errors, "invalid UUID format")
 			}
+		case "hostname":
+			if !isValidHostname(value) {
+				errors = append(errors, "invalid hostname format")
+			}
+		case "ipv4":
+			if !isValidIPv4(value) {
+				errors = append(errors, "invalid IPv4 format")
+			}
+		case "ipv6":
+			if !isValidIPv6(value) {
+				errors = append(errors, "invalid IPv6 format")
+			}
+		case "time":
+			if !isValidTime(value) {
+				errors = append(errors, "invalid time format (expected HH:MM:SS[.fff])")
+			}
+		case "byte":
+			if !isValidByte(value) {
+				errors = append(errors, "invalid byte format (expected base64)")
+			}
 		case "date":
 			if len(value) != 10 {
 				errors = append(errors, "invalid date format (expected YYYY-MM-DD)")
@@ -177,7 +208,21 @@ func validateSchemaArray(schema *openapi3.Schema, value interface{}) []string {
 	}
 
 	// Validate items
-	if schema.Items != nil && schema.Items.Value != nil {
+	if tuple := openapi.ArrayTuple(schema); tuple != nil {
+		if tuple.Closed && arr.Len() > len(tuple.Items) {
+			errors = append(errors, fmt.Sprintf("array must have at most %d items, additional items are not allowed", len(tuple.Items)))
+		}
+		for i := 0; i < arr.Len(); i++ {
+			itemSchema := tuple.ItemSchema(i)
+			if itemSchema == nil || itemSchema.Value == nil {
+				continue
+			}
+			itemErrors := ValidateSchemaValue(itemSchema.Value, arr.Index(i).Interface())
+			for _, err := range itemErrors {
+				errors = append(errors, fmt.Sprintf("item %d: %s", i, err))
+			}
+		}
+	} else if schema.Items != nil && schema.Items.Value != nil {
 		for i := 0; i < arr.Len(); i++ {
 			itemErrors := ValidateSchemaValue(schema.Items.Value, arr.Index(i).Interface())
 			for _, err := range itemErrors {
@@ -248,6 +293,23 @@ func validateSchemaObject(schema *openapi3.Schema, value interface{}) []string {
 	return errors
 }
 
+// kindType returns the schema type matching a Go value kind
+func kindType(kind reflect.Kind) string {
+	switch {
+	case kind == reflect.String:
+		return "string"
+	case isNumeric(kind):
+		return "number"
+	case kind == reflect.Bool:
+		return "boolean"
+	case kind == reflect.Slice || kind == reflect.Array:
+		return "array"
+	case kind == reflect.Map:
+		return "object"
+	}
+	return ""
+}
+
 func isNumeric(kind reflect.Kind) bool {
 	switch kind {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -123,7 +123,7 @@ func TestValidateSchema(t *testing.T) {
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				data, _ := json.Marshal(tt.data)
-				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data, directionAny)
 				assert.Equal(t, tt.expectedValid, len(errs) == 0)
 			})
 		}
@@ -190,7 +190,7 @@ func TestValidateSchema(t *testing.T) {
 			t.Run(tt.name, func(t *testing.T) {
 				schema := createSchemaWithFormat("string", tt.format)
 				data, _ := json.Marshal(tt.data)
-				errs := validator.validateSchema(&openapi3.SchemaRef{Value: schema}, data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: schema}, data, directionAny)
 				assert.Equal(t, tt.expectedValid, len(errs) == 0)
 			})
 		}
@@ -232,7 +232,7 @@ func TestValidateSchema(t *testing.T) {
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				data, _ := json.Marshal(tt.data)
-				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data, directionAny)
 				assert.Equal(t, tt.expectedValid, len(errs) == 0)
 			})
 		}
@@ -294,7 +294,7 @@ func TestValidateSchema(t *testing.T) {
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				data, _ := json.Marshal(tt.data)
-				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data, directionAny)
 				assert.Equal(t, tt.expectedValid, len(errs) == 0)
 			})
 		}
@@ -336,9 +336,224 @@ func TestValidateSchema(t *testing.T) {
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
 				data, _ := json.Marshal(tt.data)
-				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data, directionAny)
 				assert.Equal(t, tt.expectedValid, len(errs) == 0)
 			})
 		}
 	})
-}
\ No newline at end of file
+
+	t.Run("ValidateSchema_AllOfInheritance", func(t *testing.T) {
+		base := createObjectSchema(
+			map[string]*openapi3.SchemaRef{
+				"id":   {Value: createSchema("string")},
+				"name": {Value: createSchemaWithMinLength(2)},
+			},
+			[]string{"id", "name"},
+		)
+		extension := createObjectSchema(
+			map[string]*openapi3.SchemaRef{
+				"breed": {Value: createSchema("string")},
+			},
+			[]string{"breed"},
+		)
+
+		// No type at the top level, as is common for allOf inheritance
+		dog := &openapi3.Schema{
+			AllOf: openapi3.SchemaRefs{{Value: base}, {Value: extension}},
+		}
+
+		tests := []struct {
+			name          string
+			data          interface{}
+			expectedValid bool
+		}{
+			{
+				name:          "Valid Inherited Object",
+				data:          map[string]interface{}{"id": "1", "name": "Rex", "breed": "collie"},
+				expectedValid: true,
+			},
+			{
+				name:          "Missing Inherited Required Field",
+				data:          map[string]interface{}{"id": "1", "breed": "collie"},
+				expectedValid: false,
+			},
+			{
+				name:          "Missing Extension Required Field",
+				data:          map[string]interface{}{"id": "1", "name": "Rex"},
+				expectedValid: false,
+			},
+			{
+				name:          "Inherited Property Constraint",
+				data:          map[string]interface{}{"id": "1", "name": "R", "breed": "collie"},
+				expectedValid: false,
+			},
+			{
+				name:          "Wrong Type",
+				data:          "not an object",
+				expectedValid: false,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, _ := json.Marshal(tt.data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: dog}, data, directionAny)
+				assert.Equal(t, tt.expectedValid, len(errs) == 0)
+				assert.Equal(t, tt.expectedValid, len(ValidateSchemaValue(dog, tt.data)) == 0)
+			})
+		}
+	})
+
+	t.Run("ValidateSchema_TupleAdditionalItems", func(t *testing.T) {
+		tuple := createSchema("array")
+		tuple.Extensions = map[string]interface{}{
+			"prefixItems":     []interface{}{map[string]interface{}{"type": "number"}, map[string]interface{}{"type": "string"}},
+			"additionalItems": false,
+		}
+
+		tests := []struct {
+			name          string
+			data          interface{}
+			expectedValid bool
+		}{
+			{
+				name:          "Valid Tuple",
+				data:          []interface{}{1.5, "north"},
+				expectedValid: true,
+			},
+			{
+				name:          "Over-long Tuple",
+				data:          []interface{}{1.5, "north", "extra"},
+				expectedValid: false,
+			},
+			{
+				name:          "Wrong Position Type",
+				data:          []interface{}{"north", 1.5},
+				expectedValid: false,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, _ := json.Marshal(tt.data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tuple}, data, directionAny)
+				assert.Equal(t, tt.expectedValid, len(errs) == 0)
+				assert.Equal(t, tt.expectedValid, len(ValidateSchemaValue(tuple, tt.data)) == 0)
+			})
+		}
+	})
+
+	t.Run("ValidateSchema_Composition", func(t *testing.T) {
+		enumBranch := func(value string) *openapi3.SchemaRef {
+			schema := createSchema("string")
+			schema.Enum = []interface{}{value}
+			return &openapi3.SchemaRef{Value: schema}
+		}
+
+		oneOf := &openapi3.Schema{OneOf: openapi3.SchemaRefs{enumBranch("a"), enumBranch("b")}}
+		anyOf := &openapi3.Schema{AnyOf: openapi3.SchemaRefs{enumBranch("x"), enumBranch("y")}}
+		overlapping := &openapi3.Schema{OneOf: openapi3.SchemaRefs{
+			{Value: createSchema("string")},
+			{Value: createSchemaWithMinLength(3)},
+		}}
+		inherited := &openapi3.Schema{AllOf: openapi3.SchemaRefs{
+			{Value: createSchemaWithMinLength(1)},
+			{Value: oneOf},
+		}}
+
+		tests := []struct {
+			name          string
+			schema        *openapi3.Schema
+			data          interface{}
+			expectedValid bool
+			errorCode     string
+		}{
+			{name: "OneOf First Branch", schema: oneOf, data: "a", expectedValid: true},
+			{name: "OneOf Second Branch", schema: oneOf, data: "b", expectedValid: true},
+			{name: "OneOf No Branch", schema: oneOf, data: "c", errorCode: "oneof_no_match"},
+			{name: "OneOf Several Branches", schema: overlapping, data: "long", errorCode: "oneof_multiple_match"},
+			{name: "OneOf Single Overlapping Branch", schema: overlapping, data: "ab", expectedValid: true},
+			{name: "AnyOf Match", schema: anyOf, data: "y", expectedValid: true},
+			{name: "AnyOf No Match", schema: anyOf, data: "z", errorCode: "anyof_no_match"},
+			{name: "AllOf With OneOf Subschema", schema: inherited, data: "a", expectedValid: true},
+			{name: "AllOf With Failing OneOf Subschema", schema: inherited, data: "c", errorCode: "oneof_no_match"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, _ := json.Marshal(tt.data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: tt.schema}, data, directionAny)
+				assert.Equal(t, tt.expectedValid, len(errs) == 0)
+				assert.Equal(t, tt.expectedValid, len(ValidateSchemaValue(tt.schema, tt.data)) == 0)
+				if tt.errorCode != "" && assert.Len(t, errs, 1) {
+					assert.Equal(t, tt.errorCode, errs[0].Code)
+					assert.Contains(t, errs[0].Message, "branch")
+				}
+			})
+		}
+	})
+
+	t.Run("ValidateSchema_NotAndConditional", func(t *testing.T) {
+		loader := openapi3.NewLoader()
+		spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Payments API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Payment:
+      type: object
+      properties:
+        paymentType:
+          type: string
+        cardNumber:
+          type: string
+        reference:
+          type: string
+          not:
+            enum: [test]
+      if:
+        properties:
+          paymentType:
+            enum: [card]
+        required: [paymentType]
+      then:
+        required: [cardNumber]
+      else:
+        not:
+          required: [cardNumber]
+`))
+		if err != nil {
+			t.Fatalf("Failed to load spec: %v", err)
+		}
+		payment := spec.Components.Schemas["Payment"].Value
+
+		tests := []struct {
+			name      string
+			data      interface{}
+			errorCode string
+		}{
+			{name: "Card With Card Number", data: map[string]interface{}{"paymentType": "card", "cardNumber": "4111111111111111"}},
+			{name: "Card Without Card Number", data: map[string]interface{}{"paymentType": "card"}, errorCode: "conditional_failed"},
+			{name: "Transfer Without Card Number", data: map[string]interface{}{"paymentType": "transfer"}},
+			{name: "Transfer With Card Number", data: map[string]interface{}{"paymentType": "transfer", "cardNumber": "4111111111111111"}, errorCode: "conditional_failed"},
+			{name: "Reference Matching Not", data: map[string]interface{}{"paymentType": "transfer", "reference": "test"}, errorCode: "not_matched"},
+			{name: "Reference Not Matching Not", data: map[string]interface{}{"paymentType": "transfer", "reference": "inv-1"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				data, _ := json.Marshal(tt.data)
+				errs := validator.validateSchema(&openapi3.SchemaRef{Value: payment}, data, directionAny)
+				assert.Equal(t, tt.errorCode == "", len(ValidateSchemaValue(payment, tt.data)) == 0)
+				if tt.errorCode == "" {
+					assert.Empty(t, errs)
+				} else if assert.Len(t, errs, 1) {
+					assert.Equal(t, tt.errorCode, errs[0].Code)
+				}
+			})
+		}
+	})
+}
@@ -1,14 +1,11 @@
 package validation
 
 import (
-	"net/url"
-
-	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/felipevolpatto/meridian/internal/openapi"
 )
 
 func ValidateFile(path string) (bool, error) {
-	loader := openapi3.NewLoader()
-	_, err := loader.LoadFromFile(path)
+	_, err := openapi.ParseFile(path)
 	if err != nil {
 		return false, err
 	}
@@ -16,12 +13,7 @@ func ValidateFile(path string) (bool, error) {
 }
 
 func ValidateURL(rawURL string) (bool, error) {
-	loader := openapi3.NewLoader()
-	u, err := url.Parse(rawURL)
-	if err != nil {
-		return false, err
-	}
-	_, err = loader.LoadFromURI(u)
+	_, err := openapi.Load(rawURL, nil)
 	if err != nil {
 		return false, err
 	}
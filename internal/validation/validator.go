@@ -3,19 +3,37 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+// Validation severities. Errors fail validation; warnings flag questionable
+// usage, such as an unrecognized format or a deprecated parameter, without
+// failing it.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
 // ValidationError represents a validation error with details
 type ValidationError struct {
-	Field   string `json:"field,omitempty"`
-	Message string `json:"message"`
-	Code    string `json:"code"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+	Code     string `json:"code"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// IsWarning reports whether the entry is a non-fatal warning. Entries without
+// a severity are errors.
+func (e *ValidationError) IsWarning() bool {
+	return e.Severity == SeverityWarning
 }
 
 // ValidationErrors is a slice of ValidationError that implements the error interface
@@ -32,6 +50,28 @@ func (e ValidationErrors) Error() string {
 	return strings.Join(messages, "; ")
 }
 
+// Errors returns the entries that fail validation
+func (e ValidationErrors) Errors() ValidationErrors {
+	var errors ValidationErrors
+	for _, err := range e {
+		if !err.IsWarning() {
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+// Warnings returns the non-fatal entries
+func (e ValidationErrors) Warnings() ValidationErrors {
+	var warnings ValidationErrors
+	for _, err := range e {
+		if err.IsWarning() {
+			warnings = append(warnings, err)
+		}
+	}
+	return warnings
+}
+
 func (e *ValidationError) Error() string {
 	if e.Field != "" {
 		return fmt.Sprintf("%s: %s", e.Field, e.Message)
@@ -71,6 +111,14 @@ func (v *RequestValidator) ValidateRequest(method, path string, headers map[stri
 		})
 	}
 
+	if op.Deprecated {
+		errors = append(errors, &ValidationError{
+			Message:  fmt.Sprintf("Operation %s %s is deprecated", method, path),
+			Code:     "deprecated_operation",
+			Severity: SeverityWarning,
+		})
+	}
+
 	// Validate path parameters
 	pathParams := extractPathParams(path, v.findPathTemplate(path))
 	if pathErrs := v.validatePathParams(op, pathItem, pathParams); len(pathErrs) > 0 {
@@ -150,7 +198,7 @@ func (v *RequestValidator) ValidateResponse(method, path string, statusCode int,
 	}
 
 	// Validate response body
-	if bodyErrs := v.validateResponseBody(resp.Value, body); len(bodyErrs) > 0 {
+	if bodyErrs := v.validateResponseBody(resp.Value, http.Header(headers).Get("Content-Type"), body); len(bodyErrs) > 0 {
 		errors = append(errors, bodyErrs...)
 	}
 
@@ -255,6 +303,49 @@ func extractPathParams(path, template string) map[string]string {
 	return params
 }
 
+// ValidatePathParams validates path parameter values captured from a request
+// against the parameter schemas of the operation and its path item
+func (v *RequestValidator) ValidatePathParams(op *openapi3.Operation, pathItem *openapi3.PathItem, params map[string]string) ValidationErrors {
+	return v.validatePathParams(op, pathItem, params)
+}
+
+// ValidateResponseContentType checks that a response's Content-Type is one of
+// the media types the operation declares for the status code
+func (v *RequestValidator) ValidateResponseContentType(op *openapi3.Operation, statusCode int, contentType string) ValidationErrors {
+	if op == nil || op.Responses == nil {
+		return nil
+	}
+
+	resp := op.Responses.Status(statusCode)
+	if resp == nil {
+		resp = op.Responses.Default()
+	}
+	if resp == nil || resp.Value == nil || len(resp.Value.Content) == 0 {
+		return nil
+	}
+
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	declared := make([]string, 0, len(resp.Value.Content))
+	for mediaType := range resp.Value.Content {
+		if matchContentType(mediaType, contentType) {
+			return nil
+		}
+		declared = append(declared, mediaType)
+	}
+	sort.Strings(declared)
+
+	return ValidationErrors{
+		{
+			Field:   "Content-Type",
+			Message: fmt.Sprintf("response content type %s does not match declared types: %s", contentType, strings.Join(declared, ", ")),
+			Code:    "content_type_mismatch",
+		},
+	}
+}
+
 func (v *RequestValidator) validatePathParams(op *openapi3.Operation, pathItem *openapi3.PathItem, params map[string]string) ValidationErrors {
 	var errors ValidationErrors
 
@@ -290,6 +381,7 @@ func (v *RequestValidator) validatePathParams(op *openapi3.Operation, pathItem *
 					Code:    "invalid_format",
 				})
 			}
+			errors = append(errors, parameterWarnings(param.Value, fmt.Sprintf("path.%s", param.Value.Name))...)
 		}
 	}
 
@@ -312,6 +404,18 @@ func (v *RequestValidator) validateQueryParams(op *openapi3.Operation, query url
 				}
 				continue
 			}
+			errors = append(errors, parameterWarnings(param.Value, fmt.Sprintf("query.%s", param.Value.Name))...)
+
+			if isArrayParameter(param.Value) {
+				for _, err := range validateArrayParameter(param.Value, splitQueryArray(param.Value, values)) {
+					errors = append(errors, &ValidationError{
+						Field:   fmt.Sprintf("query.%s", param.Value.Name),
+						Message: err.Error(),
+						Code:    "invalid_format",
+					})
+				}
+				continue
+			}
 
 			for _, value := range values {
 				if err := validateParameterValue(param.Value, value); err != nil {
@@ -328,6 +432,93 @@ func (v *RequestValidator) validateQueryParams(op *openapi3.Operation, query url
 	return errors
 }
 
+// isArrayParameter reports whether a parameter's schema is an array
+func isArrayParameter(param *openapi3.Parameter) bool {
+	return param.Schema != nil && param.Schema.Value != nil && param.Schema.Value.Type == "array"
+}
+
+// splitQueryArray returns the elements of an array query parameter according
+// to its style and explode settings. Exploded form parameters repeat the key
+// (?tags=a&tags=b); otherwise elements are joined by the style's delimiter
+// (?tags=a,b for form, which is the default style).
+func splitQueryArray(param *openapi3.Parameter, values []string) []string {
+	explode := param.Style == "" || param.Style == openapi3.SerializationForm
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	if explode {
+		return values
+	}
+
+	delimiter := ","
+	switch param.Style {
+	case openapi3.SerializationSpaceDelimited:
+		delimiter = " "
+	case openapi3.SerializationPipeDelimited:
+		delimiter = "|"
+	}
+
+	var elements []string
+	for _, value := range values {
+		elements = append(elements, strings.Split(value, delimiter)...)
+	}
+	return elements
+}
+
+// validateArrayParameter validates the elements of an array parameter against
+// the array's item count limits and items schema
+func validateArrayParameter(param *openapi3.Parameter, elements []string) []error {
+	var errs []error
+	schema := param.Schema.Value
+
+	if uint64(len(elements)) < schema.MinItems {
+		errs = append(errs, fmt.Errorf("must have at least %d items", schema.MinItems))
+	}
+	if schema.MaxItems != nil && uint64(len(elements)) > *schema.MaxItems {
+		errs = append(errs, fmt.Errorf("must have at most %d items", *schema.MaxItems))
+	}
+
+	if schema.Items == nil {
+		return errs
+	}
+
+	itemParam := &openapi3.Parameter{Name: param.Name, In: param.In, Schema: schema.Items}
+	for i, element := range elements {
+		if err := validateParameterValue(itemParam, element); err != nil {
+			errs = append(errs, fmt.Errorf("item %d %s", i, err.Error()))
+		}
+	}
+	return errs
+}
+
+// parameterWarnings reports non-fatal issues with a parameter the request
+// supplied: deprecated parameters and string formats the validator does not
+// recognize
+func parameterWarnings(param *openapi3.Parameter, field string) ValidationErrors {
+	var warnings ValidationErrors
+
+	if param.Deprecated {
+		warnings = append(warnings, &ValidationError{
+			Field:    field,
+			Message:  fmt.Sprintf("parameter %s is deprecated", param.Name),
+			Code:     "deprecated_parameter",
+			Severity: SeverityWarning,
+		})
+	}
+
+	if param.Schema != nil && param.Schema.Value != nil {
+		schema := param.Schema.Value
+		if schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil {
+			schema = schema.Items.Value
+		}
+		if schema.Type == "string" && schema.Format != "" && !knownFormats[schema.Format] {
+			warnings = append(warnings, unknownFormatWarning(schema.Format, field))
+		}
+	}
+
+	return warnings
+}
+
 func (v *RequestValidator) validateHeaders(op *openapi3.Operation, headers map[string][]string) ValidationErrors {
 	var errors ValidationErrors
 
@@ -344,6 +535,7 @@ func (v *RequestValidator) validateHeaders(op *openapi3.Operation, headers map[s
 				}
 				continue
 			}
+			errors = append(errors, parameterWarnings(param.Value, fmt.Sprintf("header.%s", param.Value.Name))...)
 
 			for _, value := range values {
 				if err := validateParameterValue(param.Value, value); err != nil {
@@ -394,7 +586,7 @@ func (v *RequestValidator) validateRequestBody(op *openapi3.Operation, body []by
 		return errors
 	}
 
-	if schemaErrs := v.validateSchema(content.Schema, body); len(schemaErrs) > 0 {
+	if schemaErrs := v.validateSchema(content.Schema, body, directionRequest); len(schemaErrs) > 0 {
 		errors = append(errors, schemaErrs...)
 	}
 
@@ -435,7 +627,7 @@ func (v *RequestValidator) validateResponseHeaders(resp *openapi3.Response, head
 	return errors
 }
 
-func (v *RequestValidator) validateResponseBody(resp *openapi3.Response, body []byte) ValidationErrors {
+func (v *RequestValidator) validateResponseBody(resp *openapi3.Response, contentType string, body []byte) ValidationErrors {
 	var errors ValidationErrors
 
 	if len(body) == 0 {
@@ -446,9 +638,20 @@ func (v *RequestValidator) validateResponseBody(resp *openapi3.Response, body []
 		return errors
 	}
 
-	// Validate content type and schema
-	contentType := "application/json" // Default to JSON for now
-	content := resp.Content.Get(contentType)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	// Select the media type matching the actual response Content-Type
+	var content *openapi3.MediaType
+	var matchedType string
+	for ct, mt := range resp.Content {
+		if matchContentType(ct, contentType) {
+			content = mt
+			matchedType = ct
+			break
+		}
+	}
 	if content == nil {
 		errors = append(errors, &ValidationError{
 			Message: fmt.Sprintf("Unsupported content type: %s", contentType),
@@ -457,13 +660,27 @@ func (v *RequestValidator) validateResponseBody(resp *openapi3.Response, body []
 		return errors
 	}
 
-	if schemaErrs := v.validateSchema(content.Schema, body); len(schemaErrs) > 0 {
+	// Only JSON bodies can be checked against a schema. Other media types
+	// (application/xml, text/csv, ...) are accepted as long as the spec
+	// declares them.
+	if !isJSONMediaType(matchedType) && !isJSONMediaType(contentType) {
+		return errors
+	}
+
+	if schemaErrs := v.validateSchema(content.Schema, body, directionResponse); len(schemaErrs) > 0 {
 		errors = append(errors, schemaErrs...)
 	}
 
 	return errors
 }
 
+// isJSONMediaType reports whether a media type is application/json or a
+// structured +json type such as application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
 func validateParameterValue(param *openapi3.Parameter, value string) error {
 	if param.Schema == nil || param.Schema.Value == nil {
 		return nil
@@ -503,7 +720,7 @@ func validateParameterValue(param *openapi3.Parameter, value string) error {
 		}
 	case "string":
 		if schema.Format != "" {
-			if errs := validateStringFormat(schema.Format, value, ""); len(errs) > 0 {
+			if errs := validateStringFormat(schema.Format, value, "").Errors(); len(errs) > 0 {
 				return fmt.Errorf(errs[0].Message)
 			}
 		}
@@ -523,7 +740,7 @@ func validateParameterValue(param *openapi3.Parameter, value string) error {
 	return nil
 }
 
-func (v *RequestValidator) validateSchema(schema *openapi3.SchemaRef, data []byte) ValidationErrors {
+func (v *RequestValidator) validateSchema(schema *openapi3.SchemaRef, data []byte, dir direction) ValidationErrors {
 	var errors ValidationErrors
 
 	if schema == nil || schema.Value == nil {
@@ -540,7 +757,7 @@ func (v *RequestValidator) validateSchema(schema *openapi3.SchemaRef, data []byt
 	}
 
 	// Validate against schema
-	if errs := validateValue(schema.Value, jsonData, ""); len(errs) > 0 {
+	if errs := validateValue(schema.Value, jsonData, "", dir, 0); len(errs) > 0 {
 		errors = append(errors, errs...)
 	}
 
@@ -565,16 +782,62 @@ func ValidateSchema(schema *openapi3.SchemaRef, data []byte) ValidationErrors {
 	}
 
 	// Validate against schema
-	if errs := validateValue(schema.Value, jsonData, ""); len(errs) > 0 {
+	if errs := validateValue(schema.Value, jsonData, "", directionAny, 0); len(errs) > 0 {
 		errors = append(errors, errs...)
 	}
 
 	return errors
 }
 
-func validateValue(schema *openapi3.Schema, value interface{}, path string) ValidationErrors {
+// direction tells whether a value is sent in a request or a response, which
+// decides whether readOnly and writeOnly properties can be left out
+type direction int
+
+const (
+	// directionAny applies every required property
+	directionAny direction = iota
+
+	// directionRequest doesn't require readOnly properties, which only the
+	// server sets
+	directionRequest
+
+	// directionResponse doesn't require writeOnly properties, which the
+	// server never returns
+	directionResponse
+)
+
+// omittable reports whether a required property may be missing in the
+// given direction
+func (d direction) omittable(schema *openapi3.SchemaRef) bool {
+	if schema == nil || schema.Value == nil {
+		return false
+	}
+	switch d {
+	case directionRequest:
+		return schema.Value.ReadOnly
+	case directionResponse:
+		return schema.Value.WriteOnly
+	}
+	return false
+}
+
+// validateValue validates a value nested in depth objects and arrays
+func validateValue(schema *openapi3.Schema, value interface{}, path string, dir direction, depth int) ValidationErrors {
 	var errors ValidationErrors
 
+	// Stop before deeply nested objects and arrays exhaust the stack
+	if t := jsonType(value); (t == "object" || t == "array") && depth > currentMaxDepth() {
+		return append(errors, &ValidationError{
+			Field:   path,
+			Message: fmt.Sprintf("%s is nested deeper than %d levels", t, currentMaxDepth()),
+			Code:    "max_depth_exceeded",
+		})
+	}
+
+	// Inherited allOf constraints apply as if declared on the schema itself
+	original := schema
+	schema = mergeAllOf(schema)
+
 	// Handle nil value
 	if value == nil {
 		if !schema.Nullable {
@@ -587,13 +850,20 @@ func validateValue(schema *openapi3.Schema, value interface{}, path string) Vali
 		return errors
 	}
 
-	// Get the schema type
-	if schema.Type == "" {
-		return errors
+	// Composition keywords (oneOf, anyOf, not, if/then/else) must hold
+	if errs := validateComposition(original, value, path, dir, depth); len(errs) > 0 {
+		return append(errors, errs...)
+	}
+
+	// Untyped schemas (common in not and if/then/else) apply their
+	// constraints to values of the matching JSON type
+	schemaType := schema.Type
+	if schemaType == "" {
+		schemaType = jsonType(value)
 	}
 
 	// Validate type
-	switch schema.Type {
+	switch schemaType {
 	case "string":
 		str, ok := value.(string)
 		if !ok {
@@ -632,6 +902,9 @@ func validateValue(schema *openapi3.Schema, value interface{}, path string) Vali
 			})
 			return errors
 		}
+		if errs := validateEnum(schema, value, path); len(errs) > 0 {
+			errors = append(errors, errs...)
+		}
 
 	case "array":
 		arr, ok := value.([]interface{})
@@ -643,7 +916,7 @@ func validateValue(schema *openapi3.Schema, value interface{}, path string) Vali
 			})
 			return errors
 		}
-		if errs := validateArray(schema, arr, path); len(errs) > 0 {
+		if errs := validateArray(schema, arr, path, dir, depth); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 
@@ -657,7 +930,7 @@ func validateValue(schema *openapi3.Schema, value interface{}, path string) Vali
 			})
 			return errors
 		}
-		if errs := validateObject(schema, obj, path); len(errs) > 0 {
+		if errs := validateObject(schema, obj, path, dir, depth); len(errs) > 0 {
 			errors = append(errors, errs...)
 		}
 	}
@@ -665,6 +938,23 @@ func validateValue(schema *openapi3.Schema, value interface{}, path string) Vali
 	return errors
 }
 
+// jsonType returns the schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return ""
+}
+
 func validateString(schema *openapi3.Schema, value string, path string) ValidationErrors {
 	var errors ValidationErrors
 
@@ -703,32 +993,58 @@ func validateString(schema *openapi3.Schema, value string, path string) Validati
 	}
 
 	// Validate enum
-	if len(schema.Enum) > 0 {
-		valid := false
-		for _, enum := range schema.Enum {
-			if str, ok := enum.(string); ok && str == value {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			errors = append(errors, &ValidationError{
-				Field:   path,
-				Message: fmt.Sprintf("value must be one of: %v", schema.Enum),
-				Code:    "invalid_enum",
-			})
-		}
+	if errs := validateEnum(schema, value, path); len(errs) > 0 {
+		errors = append(errors, errs...)
 	}
 
 	return errors
 }
 
+// validateEnum checks that a string, number or boolean value is one of the
+// values of the schema's enum, if it has one
+func validateEnum(schema *openapi3.Schema, value interface{}, path string) ValidationErrors {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	for _, enum := range schema.Enum {
+		if enumEqual(enum, value) {
+			return nil
+		}
+	}
+	return ValidationErrors{&ValidationError{
+		Field:   path,
+		Message: fmt.Sprintf("value must be one of: %v", schema.Enum),
+		Code:    "invalid_enum",
+	}}
+}
+
+// enumEqual compares an enum value with a decoded JSON value. Numbers are
+// compared by value, as enums declared in Go may hold any numeric type.
+func enumEqual(enum, value interface{}) bool {
+	if num, ok := value.(float64); ok {
+		switch e := enum.(type) {
+		case float64:
+			return e == num
+		case float32:
+			return float64(e) == num
+		case int:
+			return float64(e) == num
+		case int64:
+			return float64(e) == num
+		case int32:
+			return float64(e) == num
+		}
+		return false
+	}
+	return enum == value
+}
+
 func validateStringFormat(format string, value string, path string) ValidationErrors {
 	var errors ValidationErrors
 
 	switch format {
 	case "email":
-		if !strings.Contains(value, "@") {
+		if !isValidEmail(value) {
 			errors = append(errors, &ValidationError{
 				Field:   path,
 				Message: "invalid email format",
@@ -736,7 +1052,7 @@ func validateStringFormat(format string, value string, path string) ValidationEr
 			})
 		}
 	case "uri":
-		if !strings.Contains(value, "://") {
+		if !isValidURI(value) {
 			errors = append(errors, &ValidationError{
 				Field:   path,
 				Message: "invalid URI format",
@@ -744,13 +1060,53 @@ func validateStringFormat(format string, value string, path string) ValidationEr
 			})
 		}
 	case "uuid":
-		if len(value) != 36 {
+		if !isValidUUID(value) {
 			errors = append(errors, &ValidationError{
 				Field:   path,
 				Message: "invalid UUID format",
 				Code:    "invalid_format",
 			})
 		}
+	case "hostname":
+		if !isValidHostname(value) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: "invalid hostname format",
+				Code:    "invalid_format",
+			})
+		}
+	case "ipv4":
+		if !isValidIPv4(value) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: "invalid IPv4 format",
+				Code:    "invalid_format",
+			})
+		}
+	case "ipv6":
+		if !isValidIPv6(value) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: "invalid IPv6 format",
+				Code:    "invalid_format",
+			})
+		}
+	case "time":
+		if !isValidTime(value) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: "invalid time format (expected HH:MM:SS[.fff])",
+				Code:    "invalid_format",
+			})
+		}
+	case "byte":
+		if !isValidByte(value) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: "invalid byte format (expected base64)",
+				Code:    "invalid_format",
+			})
+		}
 	case "date":
 		// Validate date format: YYYY-MM-DD
 		datePattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
@@ -769,6 +1125,10 @@ func validateStringFormat(format string, value string, path string) ValidationEr
 				Code:    "invalid_format",
 			})
 		}
+	default:
+		if !knownFormats[format] {
+			errors = append(errors, unknownFormatWarning(format, path))
+		}
 	}
 
 	return errors
@@ -822,10 +1182,15 @@ func validateNumber(schema *openapi3.Schema, value float64, path string) Validat
 		}
 	}
 
+	// Validate enum
+	if errs := validateEnum(schema, value, path); len(errs) > 0 {
+		errors = append(errors, errs...)
+	}
+
 	return errors
 }
 
-func validateArray(schema *openapi3.Schema, value []interface{}, path string) ValidationErrors {
+func validateArray(schema *openapi3.Schema, value []interface{}, path string, dir direction, depth int) ValidationErrors {
 	var errors ValidationErrors
 
 	// Validate length
@@ -845,20 +1210,67 @@ func validateArray(schema *openapi3.Schema, value []interface{}, path string) Va
 	}
 
 	// Validate items
-	if schema.Items != nil && schema.Items.Value != nil {
+	if tuple := openapi.ArrayTuple(schema); tuple != nil {
+		if tuple.Closed && len(value) > len(tuple.Items) {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: fmt.Sprintf("array must have at most %d items, additional items are not allowed", len(tuple.Items)),
+				Code:    "additional_items",
+			})
+		}
 		for i, item := range value {
+			itemSchema := tuple.ItemSchema(i)
+			if itemSchema == nil || itemSchema.Value == nil {
+				continue
+			}
 			itemPath := fmt.Sprintf("%s[%d]", path, i)
-			if errs := validateValue(schema.Items.Value, item, itemPath); len(errs) > 0 {
+			if errs := validateValue(itemSchema.Value, item, itemPath, dir, depth+1); len(errs) > 0 {
+				errors = append(errors, errs...)
+			}
+		}
+	} else if schema.Items != nil && schema.Items.Value != nil {
+		for i, item := range value {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if errs := validateValue(schema.Items.Value, item, itemPath, dir, depth+1); len(errs) > 0 {
 				errors = append(errors, errs...)
 			}
 		}
 	}
 
-	// Validate uniqueness
+	// Validate contains: enough items, but not too many, match its schema
+	if contains := openapi.ArrayContains(schema); contains != nil && contains.Schema.Value != nil {
+		matches := 0
+		for i, item := range value {
+			if len(validateValue(contains.Schema.Value, item, fmt.Sprintf("%s[%d]", path, i), dir, depth+1).Errors()) == 0 {
+				matches++
+			}
+		}
+		if matches < contains.Min {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: fmt.Sprintf("array must contain at least %d matching items, found %d", contains.Min, matches),
+				Code:    "contains_failed",
+			})
+		} else if contains.Max != nil && matches > *contains.Max {
+			errors = append(errors, &ValidationError{
+				Field:   path,
+				Message: fmt.Sprintf("array must contain at most %d matching items, found %d", *contains.Max, matches),
+				Code:    "contains_failed",
+			})
+		}
+	}
+
+	// Validate uniqueness. Items are compared by their JSON encoding, which
+	// also works for objects and arrays and ignores the order of object keys.
 	if schema.UniqueItems {
-		seen := make(map[interface{}]bool)
+		seen := make(map[string]bool)
 		for _, item := range value {
-			if seen[item] {
+			encoded, err := json.Marshal(item)
+			if err != nil {
+				continue
+			}
+			key := string(encoded)
+			if seen[key] {
 				errors = append(errors, &ValidationError{
 					Field:   path,
 					Message: "array items must be unique",
@@ -866,21 +1278,34 @@ func validateArray(schema *openapi3.Schema, value []interface{}, path string) Va
 				})
 				break
 			}
-			seen[item] = true
+			seen[key] = true
 		}
 	}
 
 	return errors
 }
 
-func validateObject(schema *openapi3.Schema, value map[string]interface{}, path string) ValidationErrors {
+// propertyPath returns the field path of a property of the object at path.
+// Top-level properties have no prefix, so fields read email, address.city or
+// items[2].sku.
+func propertyPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func validateObject(schema *openapi3.Schema, value map[string]interface{}, path string, dir direction, depth int) ValidationErrors {
 	var errors ValidationErrors
 
 	// Validate required properties
 	for _, required := range schema.Required {
 		if _, ok := value[required]; !ok {
+			if dir.omittable(schema.Properties[required]) {
+				continue
+			}
 			errors = append(errors, &ValidationError{
-				Field:   fmt.Sprintf("%s.%s", path, required),
+				Field:   propertyPath(path, required),
 				Message: fmt.Sprintf("missing required property: %s", required),
 				Code:    "required",
 			})
@@ -888,14 +1313,53 @@ func validateObject(schema *openapi3.Schema, value map[string]interface{}, path
 	}
 
 	// Validate properties
+	patternProperties := openapi.PatternProperties(schema)
+	propertyNames := openapi.PropertyNames(schema)
 	for propName, propValue := range value {
-		propPath := fmt.Sprintf("%s.%s", path, propName)
+		propPath := propertyPath(path, propName)
+
+		// Every property name must match propertyNames
+		if propertyNames != nil && propertyNames.Value != nil {
+			if errs := validateValue(propertyNames.Value, propName, propPath, dir, depth+1); len(errs) > 0 {
+				errors = append(errors, &ValidationError{
+					Field:   propPath,
+					Message: fmt.Sprintf("invalid property name %s: %s", propName, errs[0].Message),
+					Code:    "invalid_property_name",
+				})
+			}
+		}
+
+		// Properties matching a patternProperties pattern are validated
+		// against its schema, and aren't additional properties
+		matchedPattern := false
+		for _, pattern := range patternProperties {
+			if !pattern.Pattern.MatchString(propName) {
+				continue
+			}
+			matchedPattern = true
+			if pattern.Schema.Value == nil {
+				continue
+			}
+			if errs := validateValue(pattern.Schema.Value, propValue, propPath, dir, depth+1); len(errs) > 0 {
+				errors = append(errors, errs...)
+			}
+		}
 
 		// Check if property is defined in schema
 		if propSchema, ok := schema.Properties[propName]; ok {
-			if errs := validateValue(propSchema.Value, propValue, propPath); len(errs) > 0 {
+			if propSchema.Value != nil && propSchema.Value.Deprecated {
+				errors = append(errors, &ValidationError{
+					Field:    propPath,
+					Message:  fmt.Sprintf("property %s is deprecated", propName),
+					Code:     "deprecated_property",
+					Severity: SeverityWarning,
+				})
+			}
+			if errs := validateValue(propSchema.Value, propValue, propPath, dir, depth+1); len(errs) > 0 {
 				errors = append(errors, errs...)
 			}
+		} else if matchedPattern {
+			continue
 		} else if schema.AdditionalProperties.Has != nil && !*schema.AdditionalProperties.Has {
 			errors = append(errors, &ValidationError{
 				Field:   propPath,
@@ -903,7 +1367,7 @@ func validateObject(schema *openapi3.Schema, value map[string]interface{}, path
 				Code:    "additional_properties",
 			})
 		} else if schema.AdditionalProperties.Schema != nil {
-			if errs := validateValue(schema.AdditionalProperties.Schema.Value, propValue, propPath); len(errs) > 0 {
+			if errs := validateValue(schema.AdditionalProperties.Schema.Value, propValue, propPath, dir, depth+1); len(errs) > 0 {
 				errors = append(errors, errs...)
 			}
 		}
@@ -3,8 +3,12 @@ package validation
 import (
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/felipevolpatto/meridian/internal/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 )
@@ -376,4 +380,776 @@ func TestSecurityValidator_ValidateSecurity(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRequestValidator_QueryArrays(t *testing.T) {
+	createSpec := func(explode bool) *openapi3.T {
+		items := openapi3.NewIntegerSchema()
+		items.Max = openapi3.Float64Ptr(100)
+
+		param := openapi3.NewQueryParameter("ids").WithSchema(openapi3.NewArraySchema().WithItems(items).WithMaxItems(3))
+		param.Style = openapi3.SerializationForm
+		param.Explode = openapi3.BoolPtr(explode)
+
+		spec := &openapi3.T{Paths: openapi3.NewPaths()}
+		spec.Paths.Set("/items", &openapi3.PathItem{
+			Get: &openapi3.Operation{
+				Parameters: openapi3.Parameters{{Value: param}},
+				Responses:  openapi3.NewResponses(),
+			},
+		})
+		return spec
+	}
+
+	tests := []struct {
+		name          string
+		explode       bool
+		query         url.Values
+		expectedError bool
+	}{
+		{
+			name:    "Exploded repeated keys",
+			explode: true,
+			query:   url.Values{"ids": {"1", "2"}},
+		},
+		{
+			name:          "Exploded invalid item",
+			explode:       true,
+			query:         url.Values{"ids": {"1", "abc"}},
+			expectedError: true,
+		},
+		{
+			name:          "Exploded comma-separated value is a single item",
+			explode:       true,
+			query:         url.Values{"ids": {"1,2"}},
+			expectedError: true,
+		},
+		{
+			name:    "Non-exploded comma-separated",
+			explode: false,
+			query:   url.Values{"ids": {"1,2,3"}},
+		},
+		{
+			name:          "Non-exploded item above maximum",
+			explode:       false,
+			query:         url.Values{"ids": {"1,200"}},
+			expectedError: true,
+		},
+		{
+			name:          "Non-exploded too many items",
+			explode:       false,
+			query:         url.Values{"ids": {"1,2,3,4"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewRequestValidator(createSpec(tt.explode))
+			errors := validator.ValidateRequest("GET", "/items", nil, tt.query, nil)
+
+			if tt.expectedError {
+				assert.NotEmpty(t, errors, "Expected validation errors")
+				for _, err := range errors {
+					assert.Equal(t, "query.ids", err.Field)
+					assert.Equal(t, "invalid_format", err.Code)
+				}
+			} else {
+				assert.Empty(t, errors, "Expected no validation errors, got: %v", errors)
+			}
+		})
+	}
+}
+
+func TestValidateStringFormat_ExtendedEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		extended bool
+		value    string
+		valid    bool
+	}{
+		{name: "bare address", extended: false, value: "jane@example.com", valid: true},
+		{name: "bare address in extended mode", extended: true, value: "jane@example.com", valid: true},
+		{name: "display name", extended: true, value: "Jane Doe <jane@example.com>", valid: true},
+		{name: "quoted display name", extended: true, value: `"Doe, Jane" <jane@example.com>`, valid: true},
+		{name: "address list", extended: true, value: "Jane Doe <jane@example.com>, john@example.com", valid: true},
+		{name: "missing angle bracket", extended: true, value: "Jane Doe <jane@example.com", valid: false},
+		{name: "list with invalid address", extended: true, value: "jane@example.com, john", valid: false},
+	}
+
+	defer SetExtendedEmail(false)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetExtendedEmail(tt.extended)
+			errors := validateStringFormat("email", tt.value, "recipients")
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				assert.Len(t, errors, 1)
+				assert.Equal(t, "invalid_format", errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestValidateStringFormat_NearMisses(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		value  string
+		valid  bool
+	}{
+		{name: "email", format: "email", value: "jane@example.com", valid: true},
+		{name: "email bare at sign", format: "email", value: "@", valid: false},
+		{name: "email missing local part", format: "email", value: "@example.com", valid: false},
+		{name: "email missing domain", format: "email", value: "jane@", valid: false},
+		{name: "email with spaces", format: "email", value: "jane doe@example.com", valid: false},
+		{name: "email display name", format: "email", value: "Jane <jane@example.com>", valid: false},
+		{name: "uri", format: "uri", value: "https://example.com/path?q=1", valid: true},
+		{name: "uri mailto", format: "uri", value: "mailto:jane@example.com", valid: true},
+		{name: "uri relative path", format: "uri", value: "/path/only", valid: false},
+		{name: "uri plain text", format: "uri", value: "example.com", valid: false},
+		{name: "uri scheme separator only", format: "uri", value: "://", valid: false},
+		{name: "uuid", format: "uuid", value: "123e4567-e89b-12d3-a456-426614174000", valid: true},
+		{name: "uuid 36 chars of non-hex", format: "uuid", value: "zzzzzzzz-zzzz-zzzz-zzzz-zzzzzzzzzzzz", valid: false},
+		{name: "uuid misplaced hyphens", format: "uuid", value: "123e4567e-89b-12d3-a456-426614174000", valid: false},
+		{name: "uuid without hyphens", format: "uuid", value: "123e4567e89b12d3a456426614174000", valid: false},
+		{name: "uuid urn form", format: "uuid", value: "urn:uuid:123e4567-e89b-12d3-a456-426614174000", valid: false},
+		{name: "hostname", format: "hostname", value: "api.example.com", valid: true},
+		{name: "hostname leading hyphen", format: "hostname", value: "-api.example.com", valid: false},
+		{name: "hostname empty label", format: "hostname", value: "api..example.com", valid: false},
+		{name: "hostname underscore", format: "hostname", value: "api_v1.example.com", valid: false},
+		{name: "ipv4", format: "ipv4", value: "192.168.0.1", valid: true},
+		{name: "ipv4 out of range", format: "ipv4", value: "256.1.1.1", valid: false},
+		{name: "ipv4 mapped ipv6", format: "ipv4", value: "::ffff:192.168.0.1", valid: false},
+		{name: "ipv6", format: "ipv6", value: "2001:db8::1", valid: true},
+		{name: "ipv6 mapped ipv4", format: "ipv6", value: "::ffff:192.168.0.1", valid: true},
+		{name: "ipv6 given ipv4", format: "ipv6", value: "192.168.0.1", valid: false},
+		{name: "time", format: "time", value: "13:45:30", valid: true},
+		{name: "time fractional", format: "time", value: "13:45:30.123", valid: true},
+		{name: "time out of range", format: "time", value: "25:00:00", valid: false},
+		{name: "byte", format: "byte", value: "aGVsbG8=", valid: true},
+		{name: "byte missing padding", format: "byte", value: "aGVsbG8", valid: false},
+		{name: "byte invalid characters", format: "byte", value: "not base64!", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validateStringFormat(tt.format, tt.value, "field")
+			if tt.valid {
+				assert.Empty(t, errors)
+			} else {
+				assert.Len(t, errors, 1)
+				assert.Equal(t, "invalid_format", errors[0].Code)
+			}
+
+			schemaErrors := validateSchemaString(&openapi3.Schema{Type: "string", Format: tt.format}, tt.value)
+			assert.Equal(t, tt.valid, len(schemaErrors) == 0, "schema validation errors: %v", schemaErrors)
+		})
+	}
+}
+
+func TestRequestValidator_OneOfBody(t *testing.T) {
+	byEmail := openapi3.NewObjectSchema().
+		WithProperty("email", openapi3.NewStringSchema().WithFormat("email"))
+	byEmail.Required = []string{"email"}
+	byEmail.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.BoolPtr(false)}
+
+	byPhone := openapi3.NewObjectSchema().
+		WithProperty("phone", openapi3.NewStringSchema().WithPattern(`^\+[0-9]+$`))
+	byPhone.Required = []string{"phone"}
+	byPhone.AdditionalProperties = openapi3.AdditionalProperties{Has: openapi3.BoolPtr(false)}
+
+	schema := &openapi3.Schema{OneOf: openapi3.SchemaRefs{
+		{Ref: "#/components/schemas/CreateByEmail", Value: byEmail},
+		{Ref: "#/components/schemas/CreateByPhone", Value: byPhone},
+	}}
+
+	spec := &openapi3.T{Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/accounts", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(schema)},
+			Responses:   openapi3.NewResponses(),
+		},
+	})
+	validator := NewRequestValidator(spec)
+
+	tests := []struct {
+		name          string
+		body          string
+		expectedError bool
+	}{
+		{name: "Email branch", body: `{"email": "jane@example.com"}`},
+		{name: "Phone branch", body: `{"phone": "+15550100"}`},
+		{name: "No branch matches", body: `{"username": "jane"}`, expectedError: true},
+		{name: "Invalid email in email branch", body: `{"email": "not-an-email"}`, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateRequest("POST", "/accounts", nil, nil, []byte(tt.body))
+
+			if tt.expectedError {
+				assert.Len(t, errors, 1)
+				assert.Equal(t, "oneof_no_match", errors[0].Code)
+				assert.Contains(t, errors[0].Message, "CreateByEmail")
+				assert.Contains(t, errors[0].Message, "CreateByPhone")
+			} else {
+				assert.Empty(t, errors, "Expected no validation errors, got: %v", errors)
+			}
+		})
+	}
+}
+
+func TestRequestValidator_ResponseContentType(t *testing.T) {
+	schema := openapi3.NewObjectSchema().WithProperty("name", openapi3.NewStringSchema())
+	schema.Required = []string{"name"}
+
+	content := openapi3.NewContentWithJSONSchema(schema)
+	content["application/xml"] = openapi3.NewMediaType().WithSchema(schema)
+	content["text/csv"] = openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema())
+
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK").WithContent(content)})
+
+	spec := &openapi3.T{Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/reports", &openapi3.PathItem{
+		Get: &openapi3.Operation{Responses: responses},
+	})
+	validator := NewRequestValidator(spec)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		errorCode   string
+	}{
+		{name: "JSON body", contentType: "application/json", body: `{"name": "Q1"}`},
+		{name: "JSON body with charset", contentType: "application/json; charset=utf-8", body: `{"name": "Q1"}`},
+		{name: "Invalid JSON body", contentType: "application/json", body: `{}`, errorCode: "required"},
+		{name: "XML body is not schema validated", contentType: "application/xml", body: `<report><name>Q1</name></report>`},
+		{name: "CSV body is not schema validated", contentType: "text/csv", body: "name\nQ1\n"},
+		{name: "Undeclared content type", contentType: "text/html", body: `<p>Q1</p>`, errorCode: "unsupported_content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string][]string{"Content-Type": {tt.contentType}}
+			errors := validator.ValidateResponse("GET", "/reports", 200, headers, []byte(tt.body))
+
+			if tt.errorCode != "" {
+				assert.NotEmpty(t, errors, "Expected validation errors")
+				assert.Equal(t, tt.errorCode, errors[0].Code)
+			} else {
+				assert.Empty(t, errors, "Expected no validation errors, got: %v", errors)
+			}
+		})
+	}
+}
+
+func TestRequestValidator_ValidateResponseContentType(t *testing.T) {
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK").WithContent(openapi3.Content{
+		"application/xml": openapi3.NewMediaType().WithSchema(openapi3.NewObjectSchema()),
+	})})
+	op := &openapi3.Operation{Responses: responses}
+	validator := NewRequestValidator(&openapi3.T{Paths: openapi3.NewPaths()})
+
+	assert.Empty(t, validator.ValidateResponseContentType(op, 200, "application/xml; charset=utf-8"))
+
+	errors := validator.ValidateResponseContentType(op, 200, "application/json")
+	if assert.Len(t, errors, 1) {
+		assert.Equal(t, "content_type_mismatch", errors[0].Code)
+		assert.Contains(t, errors[0].Message, "application/xml")
+	}
+
+	// Statuses the spec doesn't describe have nothing to compare against
+	assert.Empty(t, validator.ValidateResponseContentType(op, 404, "application/json"))
+}
+
+func TestValidateStringFormat_UnknownFormatWarning(t *testing.T) {
+	results := validateStringFormat("slug", "ada-lovelace", "handle")
+
+	assert.Empty(t, results.Errors())
+	if assert.Len(t, results.Warnings(), 1) {
+		assert.Equal(t, "unknown_format", results[0].Code)
+		assert.Equal(t, SeverityWarning, results[0].Severity)
+		assert.Equal(t, "handle", results[0].Field)
+	}
+}
+
+func TestRequestValidator_DeprecatedUsageWarnings(t *testing.T) {
+	param := openapi3.NewQueryParameter("sort").WithSchema(openapi3.NewStringSchema())
+	param.Deprecated = true
+
+	spec := &openapi3.T{Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/items", &openapi3.PathItem{
+		Get: &openapi3.Operation{
+			Deprecated: true,
+			Parameters: openapi3.Parameters{{Value: param}},
+			Responses:  openapi3.NewResponses(),
+		},
+	})
+
+	results := NewRequestValidator(spec).ValidateRequest("GET", "/items", nil, url.Values{"sort": {"name"}}, nil)
+
+	assert.Empty(t, results.Errors())
+	var codes []string
+	for _, warning := range results.Warnings() {
+		codes = append(codes, warning.Code)
+	}
+	assert.ElementsMatch(t, []string{"deprecated_operation", "deprecated_parameter"}, codes)
+}
+
+func TestValidateSchema_FieldPaths(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Orders API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Order:
+      type: object
+      required: [email, address, items]
+      properties:
+        email:
+          type: string
+        address:
+          type: object
+          required: [city]
+          properties:
+            city:
+              type: string
+            zip:
+              type: string
+              maxLength: 5
+        items:
+          type: array
+          items:
+            type: object
+            required: [sku]
+            properties:
+              sku:
+                type: string
+              quantity:
+                type: integer
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	order := spec.Components.Schemas["Order"]
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{name: "Top-level required", body: `{"address": {"city": "Lisbon"}, "items": []}`, field: "email"},
+		{name: "Top-level type", body: `{"email": 1, "address": {"city": "Lisbon"}, "items": []}`, field: "email"},
+		{name: "Nested required", body: `{"email": "a@b.co", "address": {}, "items": []}`, field: "address.city"},
+		{name: "Nested constraint", body: `{"email": "a@b.co", "address": {"city": "Lisbon", "zip": "1000-001"}, "items": []}`, field: "address.zip"},
+		{name: "Array element required", body: `{"email": "a@b.co", "address": {"city": "Lisbon"}, "items": [{"sku": "a"}, {"sku": "b"}, {}]}`, field: "items[2].sku"},
+		{name: "Array element type", body: `{"email": "a@b.co", "address": {"city": "Lisbon"}, "items": [{"sku": "a", "quantity": "two"}]}`, field: "items[0].quantity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateSchema(order, []byte(tt.body))
+			if assert.Len(t, errors, 1, "errors: %v", errors) {
+				assert.Equal(t, tt.field, errors[0].Field)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_ExclusiveMinimum31(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(`
+openapi: 3.1.0
+info:
+  title: Prices API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Price:
+      type: number
+      exclusiveMinimum: 0
+`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec, err := openapi.ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	price := spec.Components.Schemas["Price"]
+
+	assert.Empty(t, ValidateSchema(price, []byte(`0.01`)))
+	if errors := ValidateSchema(price, []byte(`0`)); assert.Len(t, errors, 1) {
+		assert.Equal(t, "min_value", errors[0].Code)
+	}
+	assert.NotEmpty(t, ValidateSchema(price, []byte(`-5`)))
+}
+
+func TestValidateSchema_UniqueItems(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Tags API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Tags:
+      type: array
+      uniqueItems: true
+      items:
+        type: object
+        properties:
+          name:
+            type: string
+          aliases:
+            type: array
+            items:
+              type: string
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	tags := spec.Components.Schemas["Tags"]
+
+	tests := []struct {
+		name      string
+		body      string
+		wantError bool
+	}{
+		{name: "Distinct objects", body: `[{"name": "a"}, {"name": "b"}, {"name": "a", "aliases": ["x"]}]`},
+		{name: "Duplicate objects", body: `[{"name": "a", "aliases": ["x"]}, {"name": "b"}, {"name": "a", "aliases": ["x"]}]`, wantError: true},
+		{name: "Duplicates with reordered keys", body: `[{"name": "a", "aliases": []}, {"aliases": [], "name": "a"}]`, wantError: true},
+		{name: "Same values in another order", body: `[{"name": "a", "aliases": ["x", "y"]}, {"name": "a", "aliases": ["y", "x"]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateSchema(tags, []byte(tt.body))
+			if !tt.wantError {
+				assert.Empty(t, errors)
+				return
+			}
+			if assert.Len(t, errors, 1, "errors: %v", errors) {
+				assert.Equal(t, "unique_items", errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_Enums(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Settings API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Settings:
+      type: object
+      properties:
+        priority:
+          type: integer
+          enum: [1, 2, 3]
+        ratio:
+          type: number
+          enum: [0.5, 1.5]
+        enabled:
+          type: boolean
+          enum: [true]
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	settings := spec.Components.Schemas["Settings"]
+
+	tests := []struct {
+		name      string
+		body      string
+		wantField string
+	}{
+		{name: "Allowed values", body: `{"priority": 2, "ratio": 1.5, "enabled": true}`},
+		{name: "Integer out of set", body: `{"priority": 4}`, wantField: "priority"},
+		{name: "Number out of set", body: `{"ratio": 1}`, wantField: "ratio"},
+		{name: "Boolean out of set", body: `{"enabled": false}`, wantField: "enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateSchema(settings, []byte(tt.body))
+			if tt.wantField == "" {
+				assert.Empty(t, errors)
+				return
+			}
+			if assert.Len(t, errors, 1, "errors: %v", errors) {
+				assert.Equal(t, "invalid_enum", errors[0].Code)
+				assert.Equal(t, tt.wantField, errors[0].Field)
+			}
+		})
+	}
+
+	// Enums declared in Go may hold ints
+	schema := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: "integer", Enum: []interface{}{1, 2, 3}}}
+	assert.Empty(t, ValidateSchema(schema, []byte(`3`)))
+	assert.Len(t, ValidateSchema(schema, []byte(`5`)), 1)
+}
+
+func TestValidateSchema_MaxDepth(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Comments API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Comment:
+      type: object
+      properties:
+        text:
+          type: string
+        reply:
+          $ref: '#/components/schemas/Comment'
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	comment := spec.Components.Schemas["Comment"]
+
+	nested := func(levels int) []byte {
+		body := `{"text": "last"}`
+		for i := 0; i < levels; i++ {
+			body = `{"text": "reply", "reply": ` + body + `}`
+		}
+		return []byte(body)
+	}
+
+	assert.Empty(t, ValidateSchema(comment, nested(10)))
+
+	errors := ValidateSchema(comment, nested(1000))
+	if assert.Len(t, errors, 1) {
+		assert.Equal(t, "max_depth_exceeded", errors[0].Code)
+		assert.True(t, strings.HasPrefix(errors[0].Field, "reply.reply."), errors[0].Field)
+	}
+
+	SetMaxDepth(5)
+	defer SetMaxDepth(0)
+	errors = ValidateSchema(comment, nested(10))
+	if assert.Len(t, errors, 1) {
+		assert.Equal(t, "max_depth_exceeded", errors[0].Code)
+		assert.Equal(t, "reply.reply.reply.reply.reply.reply", errors[0].Field)
+	}
+	assert.Empty(t, ValidateSchema(comment, nested(5)))
+}
+
+func TestValidateSchema_Contains(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(`
+openapi: 3.1.0
+info:
+  title: Scores API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Scores:
+      type: array
+      contains:
+        type: integer
+        minimum: 1
+      minContains: 2
+      maxContains: 3
+`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec, err := openapi.ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	scores := spec.Components.Schemas["Scores"]
+
+	tests := []struct {
+		name      string
+		body      string
+		wantError bool
+	}{
+		{name: "Two positive integers", body: `[1, "a", 2, -3]`},
+		{name: "Three positive integers", body: `[1, 2, 3]`},
+		{name: "One positive integer", body: `[0, -1, 5, "7"]`, wantError: true},
+		{name: "Empty", body: `[]`, wantError: true},
+		{name: "Too many positive integers", body: `[1, 2, 3, 4]`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateSchema(scores, []byte(tt.body))
+			if !tt.wantError {
+				assert.Empty(t, errors)
+				return
+			}
+			if assert.Len(t, errors, 1, "errors: %v", errors) {
+				assert.Equal(t, "contains_failed", errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_ContainsIgnoresWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(path, []byte(`
+openapi: 3.1.0
+info:
+  title: Contacts API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Phones:
+      type: array
+      contains:
+        type: string
+        format: phone
+`), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	spec, err := openapi.ParseFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+
+	// An unknown format only warns, so the item still matches
+	results := ValidateSchema(spec.Components.Schemas["Phones"], []byte(`["555-1234"]`))
+	assert.Empty(t, results.Errors(), "results: %v", results)
+}
+
+func TestValidateSchema_PatternPropertiesAndPropertyNames(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info:
+  title: Headers API
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Headers:
+      type: object
+      properties:
+        host:
+          type: string
+      patternProperties:
+        "^x-":
+          type: string
+          maxLength: 10
+      additionalProperties: false
+    Labels:
+      type: object
+      propertyNames:
+        pattern: "^[a-z][a-z0-9-]*$"
+        maxLength: 8
+      additionalProperties:
+        type: string
+`))
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	headers := spec.Components.Schemas["Headers"]
+	labels := spec.Components.Schemas["Labels"]
+
+	tests := []struct {
+		name   string
+		schema *openapi3.SchemaRef
+		body   string
+		field  string
+		code   string
+	}{
+		{name: "Pattern property", schema: headers, body: `{"host": "example.com", "x-request-id": "abc"}`},
+		{name: "Pattern property value", schema: headers, body: `{"x-request-id": "a very long identifier"}`, field: "x-request-id", code: "max_length"},
+		{name: "Pattern property type", schema: headers, body: `{"x-retries": 3}`, field: "x-retries", code: "invalid_type"},
+		{name: "Unmatched property", schema: headers, body: `{"accept": "*/*"}`, field: "accept", code: "additional_properties"},
+		{name: "Valid names", schema: labels, body: `{"app": "web", "tier-2": "db"}`},
+		{name: "Name pattern", schema: labels, body: `{"App": "web"}`, field: "App", code: "invalid_property_name"},
+		{name: "Name length", schema: labels, body: `{"environment": "prod"}`, field: "environment", code: "invalid_property_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateSchema(tt.schema, []byte(tt.body))
+			if tt.code == "" {
+				assert.Empty(t, errors)
+				return
+			}
+			if assert.Len(t, errors, 1, "errors: %v", errors) {
+				assert.Equal(t, tt.field, errors[0].Field)
+				assert.Equal(t, tt.code, errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestRequestValidator_ReadOnlyWriteOnly(t *testing.T) {
+	id := openapi3.NewStringSchema()
+	id.ReadOnly = true
+	password := openapi3.NewStringSchema()
+	password.WriteOnly = true
+
+	user := openapi3.NewObjectSchema().
+		WithProperty("id", id).
+		WithProperty("name", openapi3.NewStringSchema()).
+		WithProperty("password", password)
+	user.Required = []string{"id", "name", "password"}
+
+	responses := openapi3.NewResponses()
+	responses.Set("200", &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK").WithJSONSchema(user)})
+
+	spec := &openapi3.T{Paths: openapi3.NewPaths()}
+	spec.Paths.Set("/users", &openapi3.PathItem{
+		Post: &openapi3.Operation{
+			RequestBody: &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithJSONSchema(user)},
+			Responses:   responses,
+		},
+	})
+	validator := NewRequestValidator(spec)
+
+	t.Run("request", func(t *testing.T) {
+		assert.Empty(t, validator.ValidateRequest("POST", "/users", nil, nil, []byte(`{"name": "Ada", "password": "secret"}`)))
+
+		errors := validator.ValidateRequest("POST", "/users", nil, nil, []byte(`{"name": "Ada"}`))
+		if assert.Len(t, errors, 1) {
+			assert.Equal(t, "password", errors[0].Field)
+			assert.Equal(t, "required", errors[0].Code)
+		}
+	})
+
+	t.Run("response", func(t *testing.T) {
+		headers := map[string][]string{"Content-Type": {"application/json"}}
+		assert.Empty(t, validator.ValidateResponse("POST", "/users", 200, headers, []byte(`{"id": "1", "name": "Ada"}`)))
+
+		errors := validator.ValidateResponse("POST", "/users", 200, headers, []byte(`{"name": "Ada"}`))
+		if assert.Len(t, errors, 1) {
+			assert.Equal(t, "id", errors[0].Field)
+			assert.Equal(t, "required", errors[0].Code)
+		}
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		// Without a direction every required property applies
+		assert.Len(t, ValidateSchema(openapi3.NewSchemaRef("", user), []byte(`{"name": "Ada"}`)), 2)
+	})
+}